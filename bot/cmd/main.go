@@ -0,0 +1,80 @@
+package main
+
+import (
+	"log"
+	"miniature/bot/internal/application"
+	"miniature/bot/internal/config"
+	"miniature/bot/internal/domain"
+	"miniature/bot/internal/infra/miniature"
+	"miniature/bot/internal/infra/postgres"
+	"miniature/bot/internal/infra/telegram"
+	"miniature/bot/internal/infra/whatsapp"
+	"miniature/bot/internal/interfaces"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+func main() {
+	shopID, err := uuid.Parse(config.ShopID)
+	if err != nil {
+		log.Fatalf("BOT_SHOP_ID must be the id of the shop this bot serves: %v", err)
+	}
+
+	messengers := map[domain.Channel]domain.Messenger{}
+	var telegramClient *telegram.Client
+	if config.TelegramToken != "" {
+		// Long polling keeps a getUpdates request open for TelegramPollTimeout
+		telegramHTTP := &http.Client{Timeout: config.TelegramPollTimeout + config.ServiceTimeout}
+		telegramClient = telegram.NewClient(config.TelegramBaseURL, config.TelegramToken, telegramHTTP)
+		messengers[domain.ChannelTelegram] = telegramClient
+	}
+	whatsAppEnabled := config.WhatsAppPhoneNumberID != "" && config.WhatsAppAccessToken != ""
+	if whatsAppEnabled {
+		if config.WhatsAppAppSecret == "" {
+			log.Fatal("BOT_WHATSAPP_APP_SECRET is required to verify WhatsApp webhooks")
+		}
+		whatsAppHTTP := &http.Client{Timeout: config.ServiceTimeout}
+		messengers[domain.ChannelWhatsApp] = whatsapp.NewClient(
+			config.WhatsAppBaseURL, config.WhatsAppPhoneNumberID, config.WhatsAppAccessToken, whatsAppHTTP,
+		)
+	}
+	if len(messengers) == 0 {
+		log.Fatal("no channel configured: set BOT_TELEGRAM_TOKEN and/or the BOT_WHATSAPP_* variables")
+	}
+
+	db := postgres.NewPostgresConnection()
+	defer db.Close()
+
+	serviceHTTP := &http.Client{Timeout: config.ServiceTimeout}
+	catalog := miniature.NewProductClient(config.ProductServiceURL, serviceHTTP)
+	orders := miniature.NewOrderClient(config.OrderServiceURL, config.ChannelAPIKey, serviceHTTP)
+
+	subscriptions := postgres.NewSubscriptionRepository(db)
+	application.NewStatusNotifier(messengers, orders, subscriptions).Start(config.StatusPollInterval)
+
+	bot := application.NewBot(
+		shopID,
+		messengers,
+		catalog,
+		orders,
+		postgres.NewConversationRepository(db),
+		subscriptions,
+		postgres.NewMessageLog(db),
+	)
+	bot.StartCleanup(config.CleanupInterval, config.HandledMessageRetention)
+
+	if !whatsAppEnabled {
+		log.Printf("Telegram bot running for shop %s", shopID)
+		bot.Poll(telegramClient)
+		return
+	}
+
+	if telegramClient != nil {
+		go bot.Poll(telegramClient)
+	}
+	handler := interfaces.NewWhatsAppHandler(bot, config.WhatsAppPhoneNumberID, config.WhatsAppAppSecret, config.WhatsAppVerifyToken)
+	route := interfaces.NewRouter(handler)
+	log.Printf("Bot for shop %s receiving WhatsApp webhooks on: %s", shopID, config.WebhookAddr)
+	route.Run(config.WebhookAddr)
+}
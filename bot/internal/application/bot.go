@@ -0,0 +1,282 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"miniature/bot/internal/config"
+	"miniature/bot/internal/domain"
+	"miniature/pkg/phone"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// retryDelay is how long Run waits after the messenger fails before polling again.
+const retryDelay = time.Second * 5
+
+// Bot takes orders for one shop through chat conversations on any number of channels.
+type Bot struct {
+	shopID        uuid.UUID
+	messengers    map[domain.Channel]domain.Messenger
+	catalog       domain.Catalog
+	orders        domain.OrderGateway
+	conversations domain.ConversationRepository
+	subscriptions domain.SubscriptionRepository
+	handled       domain.MessageLog
+	chats         chatLocks
+}
+
+func NewBot(
+	shopID uuid.UUID,
+	messengers map[domain.Channel]domain.Messenger,
+	catalog domain.Catalog,
+	orders domain.OrderGateway,
+	conversations domain.ConversationRepository,
+	subscriptions domain.SubscriptionRepository,
+	handled domain.MessageLog,
+) *Bot {
+	return &Bot{
+		shopID:        shopID,
+		messengers:    messengers,
+		catalog:       catalog,
+		orders:        orders,
+		conversations: conversations,
+		subscriptions: subscriptions,
+		handled:       handled,
+		chats:         chatLocks{locks: map[string]*chatLock{}},
+	}
+}
+
+// Poll long-polls a channel and handles its messages until the process exits. A message that fails
+// is logged and skipped, so one broken chat can't stall the others.
+func (b *Bot) Poll(poller domain.Poller) {
+	var offset int64
+	for {
+		updates, err := poller.Updates(offset, config.TelegramPollTimeout)
+		if err != nil {
+			log.Printf("polling updates: %v", err)
+			time.Sleep(retryDelay)
+			continue
+		}
+		for _, update := range updates {
+			offset = update.Offset + 1
+			if update.Message == nil {
+				continue
+			}
+			if err := b.HandleMessage(*update.Message); err != nil {
+				log.Printf("handling %s message %s from chat %s: %v",
+					update.Message.Channel, update.Message.ID, update.Message.ChatID, err)
+			}
+		}
+	}
+}
+
+// StartCleanup forgets old handled message ids every interval in the background.
+func (b *Bot) StartCleanup(interval, retention time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := b.handled.Forget(time.Now().Add(-retention)); err != nil {
+				log.Printf("forgetting handled messages: %v", err)
+			}
+		}
+	}()
+}
+
+// HandleMessage moves the chat's conversation one step forward and replies. A message that was
+// already handled is ignored.
+func (b *Bot) HandleMessage(message domain.IncomingMessage) error {
+	messenger, ok := b.messengers[message.Channel]
+	if !ok {
+		return fmt.Errorf("no messenger for channel %s", message.Channel)
+	}
+	if message.ID != "" {
+		first, err := b.handled.MarkHandled(message.Channel, message.ID)
+		if err != nil || !first {
+			return err
+		}
+	}
+
+	// Webhook deliveries of one chat can arrive at once, e.g. two taps on the confirm button.
+	unlock := b.chats.lock(string(message.Channel) + ":" + message.ChatID)
+	defer unlock()
+
+	conversation, err := b.conversations.Find(message.Channel, message.ChatID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if conversation == nil {
+		conversation = domain.NewConversation(message.Channel, message.ChatID)
+	} else if conversation.Expired(now, config.ConversationTTL) {
+		conversation.Reset()
+	}
+
+	reply, err := b.step(conversation, message)
+	if err != nil {
+		return err
+	}
+
+	conversation.UpdatedAt = now
+	if err := b.conversations.Save(conversation); err != nil {
+		return err
+	}
+	reply.Channel = message.Channel
+	reply.ChatID = message.ChatID
+	return messenger.SendMessage(reply)
+}
+
+func (b *Bot) step(c *domain.Conversation, message domain.IncomingMessage) (domain.OutgoingMessage, error) {
+	text := strings.TrimSpace(message.Text)
+	switch text {
+	case "/start":
+		c.Start()
+		return domain.OutgoingMessage{Text: msgWelcome}, nil
+	case "/cancel", msgCancelButton:
+		c.Reset()
+		return domain.OutgoingMessage{Text: msgCancelled}, nil
+	}
+
+	switch c.State {
+	case domain.StateIdle, domain.StateAwaitingCode:
+		return b.receiveCode(c, text)
+
+	case domain.StateAwaitingQuantity:
+		switch err := c.SetQuantity(text); {
+		case errors.Is(err, domain.ErrNotEnoughStock):
+			return domain.OutgoingMessage{Text: notEnoughStockMessage(c)}, nil
+		case err != nil:
+			return domain.OutgoingMessage{Text: msgInvalidQuantity}, nil
+		}
+		// A number the channel vouches for is the customer's; there is no need to ask for it
+		if message.SenderPhone != "" && c.SetPhone(message.SenderPhone) == nil {
+			return askAddress(c), nil
+		}
+		return domain.OutgoingMessage{Text: msgAskPhone, ContactButton: msgContactButton}, nil
+
+	case domain.StateAwaitingPhone:
+		// Orders are placed in the account of this number, so a typed one, which could be anyone's, is refused
+		if message.Contact == "" {
+			return domain.OutgoingMessage{Text: msgUseContactButton, ContactButton: msgContactButton}, nil
+		}
+		if err := c.SetPhone(message.Contact); err != nil {
+			return domain.OutgoingMessage{Text: msgInvalidPhone, ContactButton: msgContactButton}, nil
+		}
+		return askAddress(c), nil
+
+	case domain.StateAwaitingAddress:
+		if err := c.SetAddress(text); err != nil {
+			return domain.OutgoingMessage{Text: msgAddressTooShort}, nil
+		}
+		return domain.OutgoingMessage{Text: summaryMessage(c), Buttons: []string{msgConfirmButton, msgCancelButton}}, nil
+
+	case domain.StateAwaitingConfirm:
+		if text != msgConfirmButton {
+			return domain.OutgoingMessage{Text: msgUseButtons, Buttons: []string{msgConfirmButton, msgCancelButton}}, nil
+		}
+		return b.placeOrder(c)
+	}
+	return domain.OutgoingMessage{}, domain.ErrUnexpectedInput
+}
+
+func (b *Bot) receiveCode(c *domain.Conversation, text string) (domain.OutgoingMessage, error) {
+	if text == "" {
+		return domain.OutgoingMessage{Text: msgAskCode}, nil
+	}
+	code := strings.ToUpper(phone.NormalizeDigits(text))
+	product, err := b.catalog.FindByCode(b.shopID, code)
+	if err != nil {
+		log.Printf("looking up product code %q: %v", code, err)
+		return domain.OutgoingMessage{Text: msgLookupFailed}, nil
+	}
+	if product == nil {
+		return domain.OutgoingMessage{Text: msgProductNotFound}, nil
+	}
+	if err := c.SetProduct(product); err != nil {
+		return domain.OutgoingMessage{Text: msgOutOfStock}, nil
+	}
+	return domain.OutgoingMessage{Text: productMessage(c)}, nil
+}
+
+func (b *Bot) placeOrder(c *domain.Conversation) (domain.OutgoingMessage, error) {
+	request, err := c.Confirm(b.shopID)
+	if err != nil {
+		return domain.OutgoingMessage{}, err
+	}
+
+	order, err := b.orders.PlaceOrder(request)
+	switch {
+	case errors.Is(err, domain.ErrOutOfStock):
+		c.Reset()
+		return domain.OutgoingMessage{Text: msgSoldOut}, nil
+	case errors.Is(err, domain.ErrOrderRejected):
+		log.Printf("order from %s chat %s rejected: %v", c.Channel, c.ChatID, err)
+		c.Reset()
+		return domain.OutgoingMessage{Text: msgOrderRejected}, nil
+	case err != nil:
+		// The conversation stays at the confirmation step so the customer can simply try again
+		log.Printf("placing order for %s chat %s: %v", c.Channel, c.ChatID, err)
+		return domain.OutgoingMessage{Text: msgOrderFailed, Buttons: []string{msgConfirmButton, msgCancelButton}}, nil
+	}
+
+	c.Placed()
+	subscription := &domain.Subscription{
+		OrderID:    order.ID,
+		Channel:    c.Channel,
+		ChatID:     c.ChatID,
+		LastStatus: order.Status,
+		CreatedAt:  time.Now(),
+	}
+	if err := b.subscriptions.Create(subscription); err != nil {
+		// The order exists; the customer only misses status updates
+		log.Printf("subscribing %s chat %s to order %s: %v", c.Channel, c.ChatID, order.ID, err)
+	}
+	return domain.OutgoingMessage{Text: placedMessage(order)}, nil
+}
+
+// askAddress offers the address used last time, if any.
+func askAddress(c *domain.Conversation) domain.OutgoingMessage {
+	reply := domain.OutgoingMessage{Text: msgAskAddress}
+	if c.Address != "" {
+		reply.Buttons = []string{c.Address}
+	}
+	return reply
+}
+
+// chatLocks serializes the messages of each chat, so a conversation is never stepped from two
+// stale copies. Locks are dropped once no message of their chat is being handled.
+type chatLocks struct {
+	mu    sync.Mutex
+	locks map[string]*chatLock
+}
+
+type chatLock struct {
+	sync.Mutex
+	waiting int
+}
+
+func (c *chatLocks) lock(key string) (unlock func()) {
+	c.mu.Lock()
+	l, ok := c.locks[key]
+	if !ok {
+		l = &chatLock{}
+		c.locks[key] = l
+	}
+	l.waiting++
+	c.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		c.mu.Lock()
+		l.waiting--
+		if l.waiting == 0 {
+			delete(c.locks, key)
+		}
+		c.mu.Unlock()
+	}
+}
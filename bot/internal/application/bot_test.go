@@ -0,0 +1,207 @@
+package application
+
+import (
+	"miniature/bot/internal/domain"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fakeCatalog map[string]*domain.Product
+
+func (c fakeCatalog) FindByCode(_ uuid.UUID, code string) (*domain.Product, error) {
+	return c[code], nil
+}
+
+type fakeOrders struct {
+	mu     sync.Mutex
+	placed []*domain.OrderRequest
+	delay  time.Duration // of the call to the order service
+}
+
+func (o *fakeOrders) PlaceOrder(request *domain.OrderRequest) (*domain.Order, error) {
+	time.Sleep(o.delay)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.placed = append(o.placed, request)
+	return &domain.Order{ID: uuid.New(), Status: domain.StatusPending, TotalAmount: 250000}, nil
+}
+
+func (o *fakeOrders) FindOrder(uuid.UUID) (*domain.Order, error) {
+	return nil, nil
+}
+
+// fakeConversations hands out copies, like rows read from the database.
+type fakeConversations struct {
+	mu    sync.Mutex
+	saved map[string]domain.Conversation
+}
+
+func (r *fakeConversations) Find(channel domain.Channel, chatID string) (*domain.Conversation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.saved[string(channel)+chatID]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+func (r *fakeConversations) Save(c *domain.Conversation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saved[string(c.Channel)+c.ChatID] = *c
+	return nil
+}
+
+type fakeSubscriptions struct {
+	domain.SubscriptionRepository
+}
+
+func (fakeSubscriptions) Create(*domain.Subscription) error {
+	return nil
+}
+
+type fakeMessageLog struct {
+	mu      sync.Mutex
+	handled map[string]bool
+}
+
+func (l *fakeMessageLog) MarkHandled(channel domain.Channel, messageID string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key := string(channel) + messageID
+	first := !l.handled[key]
+	l.handled[key] = true
+	return first, nil
+}
+
+func (l *fakeMessageLog) Forget(time.Time) error {
+	return nil
+}
+
+type fakeMessenger struct {
+	mu   sync.Mutex
+	sent []domain.OutgoingMessage
+}
+
+func (m *fakeMessenger) SendMessage(message domain.OutgoingMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, message)
+	return nil
+}
+
+type botTest struct {
+	t         *testing.T
+	bot       *Bot
+	orders    *fakeOrders
+	messenger *fakeMessenger
+	channel   domain.Channel
+	next      int
+}
+
+func newBotTest(t *testing.T, channel domain.Channel) *botTest {
+	catalog := fakeCatalog{"SL38": {ID: uuid.New(), Code: "SL38", Name: "Scarf", Price: 125000, StockQuantity: 5, IsActive: true}}
+	orders := &fakeOrders{}
+	messenger := &fakeMessenger{}
+	bot := NewBot(
+		uuid.New(), map[domain.Channel]domain.Messenger{channel: messenger}, catalog, orders,
+		&fakeConversations{saved: map[string]domain.Conversation{}}, fakeSubscriptions{},
+		&fakeMessageLog{handled: map[string]bool{}},
+	)
+	return &botTest{t: t, bot: bot, orders: orders, messenger: messenger, channel: channel}
+}
+
+// send handles message from chat 42 and returns the reply.
+func (b *botTest) send(message domain.IncomingMessage) domain.OutgoingMessage {
+	b.t.Helper()
+	b.next++
+	message.Channel, message.ChatID, message.ID = b.channel, "42", strconv.Itoa(b.next)
+	if err := b.bot.HandleMessage(message); err != nil {
+		b.t.Fatalf("HandleMessage(%+v): %v", message, err)
+	}
+	return b.messenger.sent[len(b.messenger.sent)-1]
+}
+
+func TestBotOnlyTakesTheSendersOwnContact(t *testing.T) {
+	b := newBotTest(t, domain.ChannelTelegram)
+	b.send(domain.IncomingMessage{Text: "/start"})
+	b.send(domain.IncomingMessage{Text: "sl38"})
+
+	reply := b.send(domain.IncomingMessage{Text: "۲"})
+	if reply.Text != msgAskPhone || reply.ContactButton == "" {
+		t.Fatalf("after the quantity the bot replied %+v, want a request for the contact", reply)
+	}
+
+	reply = b.send(domain.IncomingMessage{Text: "09121234567"})
+	if reply.Text != msgUseContactButton || reply.ContactButton == "" {
+		t.Errorf("a typed number got %+v, want it refused", reply)
+	}
+
+	reply = b.send(domain.IncomingMessage{Contact: "+12025550123"})
+	if reply.Text != msgInvalidPhone {
+		t.Errorf("a foreign number got %+v, want it refused", reply)
+	}
+
+	reply = b.send(domain.IncomingMessage{Contact: "+989121234567"})
+	if reply.Text != msgAskAddress {
+		t.Fatalf("the shared contact got %+v, want the address asked for", reply)
+	}
+	b.send(domain.IncomingMessage{Text: "تهران، خیابان ولیعصر، پلاک ۱۰"})
+	b.send(domain.IncomingMessage{Text: msgConfirmButton})
+
+	if len(b.orders.placed) != 1 {
+		t.Fatalf("placed %d orders, want 1", len(b.orders.placed))
+	}
+	if got := b.orders.placed[0]; got.CustomerPhone != "+989121234567" || got.Items[0].Quantity != 2 {
+		t.Errorf("placed %+v, want 2 of SL38 for +989121234567", got)
+	}
+}
+
+func TestBotTakesTheNumberWhatsAppVouchesFor(t *testing.T) {
+	b := newBotTest(t, domain.ChannelWhatsApp)
+	b.send(domain.IncomingMessage{Text: "SL38", SenderPhone: "+989351234567"})
+
+	reply := b.send(domain.IncomingMessage{Text: "1", SenderPhone: "+989351234567"})
+	if reply.Text != msgAskAddress {
+		t.Fatalf("after the quantity the bot replied %+v, want the address asked for", reply)
+	}
+	b.send(domain.IncomingMessage{Text: "تهران، خیابان ولیعصر، پلاک ۱۰", SenderPhone: "+989351234567"})
+	b.send(domain.IncomingMessage{Text: msgConfirmButton, SenderPhone: "+989351234567"})
+
+	if len(b.orders.placed) != 1 || b.orders.placed[0].CustomerPhone != "+989351234567" {
+		t.Errorf("placed %+v, want one order for +989351234567", b.orders.placed)
+	}
+}
+
+func TestBotPlacesOneOrderForParallelConfirmTaps(t *testing.T) {
+	b := newBotTest(t, domain.ChannelWhatsApp)
+	b.send(domain.IncomingMessage{Text: "SL38", SenderPhone: "+989351234567"})
+	b.send(domain.IncomingMessage{Text: "1", SenderPhone: "+989351234567"})
+	b.send(domain.IncomingMessage{Text: "تهران، خیابان ولیعصر، پلاک ۱۰", SenderPhone: "+989351234567"})
+
+	// Each tap is its own webhook delivery with its own message id.
+	b.orders.delay = 20 * time.Millisecond
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			message := domain.IncomingMessage{
+				Channel: b.channel, ID: "tap" + strconv.Itoa(i), ChatID: "42", Text: msgConfirmButton, SenderPhone: "+989351234567",
+			}
+			if err := b.bot.HandleMessage(message); err != nil {
+				t.Errorf("HandleMessage(%+v): %v", message, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(b.orders.placed) != 1 {
+		t.Errorf("placed %d orders for parallel confirm taps, want 1", len(b.orders.placed))
+	}
+}
@@ -0,0 +1,78 @@
+package application
+
+import (
+	"fmt"
+	"miniature/bot/internal/domain"
+	"miniature/pkg/phone"
+	"strconv"
+)
+
+// Replies are in Persian, the language of the shops' customers.
+const (
+	msgWelcome          = "سلام! برای ثبت سفارش، کد محصول را از کپشن پست بفرستید (مثلاً SL38)."
+	msgAskCode          = "کد محصول را بفرستید."
+	msgProductNotFound  = "محصولی با این کد پیدا نشد. لطفاً کد را دوباره بررسی کنید."
+	msgOutOfStock       = "متأسفانه این محصول موجود نیست."
+	msgInvalidQuantity  = "لطفاً تعداد را به صورت یک عدد بفرستید."
+	msgAskPhone         = "برای ثبت سفارش، شماره موبایل خود را با دکمه زیر بفرستید."
+	msgContactButton    = "ارسال شماره من"
+	msgUseContactButton = "لطفاً شماره را تایپ نکنید و فقط از دکمه «ارسال شماره من» استفاده کنید."
+	msgInvalidPhone     = "شماره این حساب یک موبایل ایرانی معتبر نیست."
+	msgAskAddress       = "آدرس کامل تحویل را بفرستید."
+	msgAddressTooShort  = "آدرس خیلی کوتاه است. لطفاً آدرس کامل را بفرستید."
+	msgConfirmButton    = "تأیید سفارش"
+	msgCancelButton     = "انصراف"
+	msgUseButtons       = "لطفاً یکی از دکمه‌ها را انتخاب کنید."
+	msgCancelled        = "سفارش لغو شد. هر وقت خواستید کد محصول را بفرستید."
+	msgOrderFailed      = "ثبت سفارش ممکن نشد. لطفاً کمی بعد دوباره تلاش کنید."
+	msgOrderRejected    = "سفارش پذیرفته نشد. لطفاً دوباره از ابتدا شروع کنید."
+	msgSoldOut          = "در این فاصله موجودی محصول تمام شد. سفارش ثبت نشد."
+	msgLookupFailed     = "در حال حاضر امکان بررسی محصول نیست. لطفاً کمی بعد دوباره تلاش کنید."
+)
+
+var statusMessages = map[string]string{
+	domain.StatusPaid:      "پرداخت سفارش %s تأیید شد.",
+	domain.StatusConfirmed: "سفارش %s تأیید شد و در حال آماده‌سازی است.",
+	domain.StatusShipped:   "سفارش %s ارسال شد.",
+	domain.StatusDelivered: "سفارش %s تحویل داده شد. از خرید شما متشکریم!",
+	domain.StatusCancelled: "سفارش %s لغو شد.",
+}
+
+func productMessage(c *domain.Conversation) string {
+	return fmt.Sprintf("%s\nقیمت: %s تومان\nموجودی: %d\nچند عدد می‌خواهید؟", c.ProductName, money(c.UnitPrice), c.Available)
+}
+
+func notEnoughStockMessage(c *domain.Conversation) string {
+	return fmt.Sprintf("فقط %d عدد موجود است. لطفاً تعداد کمتری وارد کنید.", c.Available)
+}
+
+func summaryMessage(c *domain.Conversation) string {
+	return fmt.Sprintf(
+		"خلاصه سفارش:\n%s (%s) × %d\nمبلغ کل: %s تومان\nموبایل: %s\nآدرس: %s\n\nسفارش را تأیید می‌کنید؟",
+		c.ProductName, c.ProductCode, c.Quantity, money(c.Total()), phone.Local(c.Phone), c.Address,
+	)
+}
+
+func placedMessage(order *domain.Order) string {
+	return fmt.Sprintf(
+		"سفارش شما ثبت شد.\nشماره سفارش: %s\nمبلغ قابل پرداخت: %s تومان\nتغییرات وضعیت سفارش را همین‌جا اطلاع می‌دهیم.",
+		shortID(order), money(order.TotalAmount),
+	)
+}
+
+func statusMessage(order *domain.Order) (string, bool) {
+	format, ok := statusMessages[order.Status]
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf(format, shortID(order)), true
+}
+
+// shortID is the part of the order id shown to customers.
+func shortID(order *domain.Order) string {
+	return order.ID.String()[:8]
+}
+
+func money(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
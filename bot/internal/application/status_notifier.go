@@ -0,0 +1,81 @@
+package application
+
+import (
+	"log"
+	"miniature/bot/internal/config"
+	"miniature/bot/internal/domain"
+	"time"
+)
+
+// StatusNotifier tells chats when the orders they placed change status. The order service has no
+// push mechanism, so it polls the orders that may still change.
+type StatusNotifier struct {
+	messengers    map[domain.Channel]domain.Messenger
+	orders        domain.OrderGateway
+	subscriptions domain.SubscriptionRepository
+}
+
+func NewStatusNotifier(
+	messengers map[domain.Channel]domain.Messenger,
+	orders domain.OrderGateway,
+	subscriptions domain.SubscriptionRepository,
+) *StatusNotifier {
+	return &StatusNotifier{messengers: messengers, orders: orders, subscriptions: subscriptions}
+}
+
+// Start checks for status changes every interval in the background.
+func (n *StatusNotifier) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := n.RunOnce(); err != nil {
+				log.Printf("checking order statuses: %v", err)
+			}
+		}
+	}()
+}
+
+// RunOnce checks one batch of subscriptions, least recently checked first. A failing order is
+// logged and skipped so it doesn't hold up the rest.
+func (n *StatusNotifier) RunOnce() error {
+	subscriptions, err := n.subscriptions.FindActive(config.StatusPollBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		if err := n.check(subscription); err != nil {
+			log.Printf("checking order %s: %v", subscription.OrderID, err)
+		}
+	}
+	return nil
+}
+
+func (n *StatusNotifier) check(subscription *domain.Subscription) error {
+	order, err := n.orders.FindOrder(subscription.OrderID)
+	if err != nil {
+		return err
+	}
+	if order.Status == subscription.LastStatus {
+		return n.subscriptions.Touch(subscription.OrderID)
+	}
+
+	messenger, ok := n.messengers[subscription.Channel]
+	if !ok {
+		// The channel was switched off; the order is checked again once it is back
+		return n.subscriptions.Touch(subscription.OrderID)
+	}
+	if text, ok := statusMessage(order); ok {
+		err := messenger.SendMessage(domain.OutgoingMessage{
+			Channel: subscription.Channel,
+			ChatID:  subscription.ChatID,
+			Text:    text,
+		})
+		if err != nil {
+			// The status is not recorded, so the message is sent on the next run
+			return err
+		}
+	}
+	return n.subscriptions.UpdateStatus(subscription.OrderID, order.Status)
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// Each shop runs its own bot, so a bot process serves exactly one shop. A channel is enabled
+// when its credentials are set.
+var (
+	ShopID = os.Getenv("BOT_SHOP_ID")
+
+	// TelegramBaseURL can point at a local fake Bot API server in tests.
+	TelegramBaseURL     = "https://api.telegram.org"
+	TelegramToken       = os.Getenv("BOT_TELEGRAM_TOKEN")
+	TelegramPollTimeout = time.Second * 30
+
+	// WhatsApp Business Cloud API. WhatsAppBaseURL can point at a local stub in tests.
+	WhatsAppBaseURL       = "https://graph.facebook.com/v21.0"
+	WhatsAppPhoneNumberID = os.Getenv("BOT_WHATSAPP_PHONE_NUMBER_ID")
+	WhatsAppAccessToken   = os.Getenv("BOT_WHATSAPP_ACCESS_TOKEN")
+	WhatsAppAppSecret     = os.Getenv("BOT_WHATSAPP_APP_SECRET")   // signs incoming webhooks
+	WhatsAppVerifyToken   = os.Getenv("BOT_WHATSAPP_VERIFY_TOKEN") // checked when the webhook is registered
+
+	// WebhookAddr is where the webhook receiver listens; it only runs when WhatsApp is enabled.
+	WebhookAddr = "localhost:8085"
+)
+
+// Services the bot talks to. ChannelAPIKey must match ORDER_CHANNEL_API_KEY of the order service.
+var (
+	OrderServiceURL   = "http://localhost:8083"
+	ProductServiceURL = "http://localhost:8082"
+	ChannelAPIKey     = os.Getenv("ORDER_CHANNEL_API_KEY")
+	ServiceTimeout    = time.Second * 10
+)
+
+var (
+	// ConversationTTL is how long an unfinished conversation is kept before the bot starts over.
+	ConversationTTL = time.Hour
+
+	// Handled message ids are kept this long to drop redelivered webhooks, which Meta retries
+	// for a few days at most.
+	HandledMessageRetention = time.Hour * 24 * 7
+	CleanupInterval         = time.Hour
+
+	// Orders placed by the bot are checked for status changes every StatusPollInterval.
+	StatusPollInterval  = time.Minute
+	StatusPollBatchSize = 100
+)
@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"miniature/pkg/phone"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// State is the step of the ordering conversation a chat is at.
+type State string
+
+// A conversation moves IDLE → AWAITING_CODE → AWAITING_QUANTITY → AWAITING_PHONE → AWAITING_ADDRESS
+// → AWAITING_CONFIRM and back to IDLE once the order is placed or the customer cancels.
+const (
+	StateIdle             State = "IDLE"
+	StateAwaitingCode     State = "AWAITING_CODE"
+	StateAwaitingQuantity State = "AWAITING_QUANTITY"
+	StateAwaitingPhone    State = "AWAITING_PHONE"
+	StateAwaitingAddress  State = "AWAITING_ADDRESS"
+	StateAwaitingConfirm  State = "AWAITING_CONFIRM"
+)
+
+const minAddressLength = 10
+
+// Product is what the bot shows a customer about a product code.
+type Product struct {
+	ID            uuid.UUID `json:"id"`
+	Code          string    `json:"code"`
+	Name          string    `json:"name"`
+	Price         float64   `json:"price"`
+	StockQuantity int       `json:"stock_quantity"`
+	IsActive      bool      `json:"is_active"`
+}
+
+// Conversation is the ordering dialogue with one chat. Phone and Address are kept after an order so
+// they can be offered again next time.
+type Conversation struct {
+	Channel     Channel
+	ChatID      string
+	State       State
+	ProductCode string
+	ProductName string
+	UnitPrice   float64
+	Available   int
+	Quantity    int
+	Phone       string
+	Address     string
+	UpdatedAt   time.Time
+}
+
+func NewConversation(channel Channel, chatID string) *Conversation {
+	return &Conversation{Channel: channel, ChatID: chatID, State: StateIdle}
+}
+
+// Expired reports whether the customer left the conversation unfinished for longer than ttl.
+func (c *Conversation) Expired(now time.Time, ttl time.Duration) bool {
+	return c.State != StateIdle && now.Sub(c.UpdatedAt) > ttl
+}
+
+func (c *Conversation) Start() {
+	c.clearDraft()
+	c.State = StateAwaitingCode
+}
+
+// Reset drops the order being drafted.
+func (c *Conversation) Reset() {
+	c.clearDraft()
+	c.State = StateIdle
+}
+
+func (c *Conversation) SetProduct(product *Product) error {
+	if c.State != StateIdle && c.State != StateAwaitingCode {
+		return ErrUnexpectedInput
+	}
+	if !product.IsActive || product.StockQuantity <= 0 {
+		return ErrNotEnoughStock
+	}
+	c.ProductCode = product.Code
+	c.ProductName = product.Name
+	c.UnitPrice = product.Price
+	c.Available = product.StockQuantity
+	c.State = StateAwaitingQuantity
+	return nil
+}
+
+// SetQuantity accepts a number typed in ASCII, Persian or Arabic-Indic digits.
+func (c *Conversation) SetQuantity(input string) error {
+	if c.State != StateAwaitingQuantity {
+		return ErrUnexpectedInput
+	}
+	quantity, err := strconv.Atoi(strings.TrimSpace(phone.NormalizeDigits(input)))
+	if err != nil || quantity <= 0 {
+		return ErrInvalidQuantity
+	}
+	if quantity > c.Available {
+		return ErrNotEnoughStock
+	}
+	c.Quantity = quantity
+	c.State = StateAwaitingPhone
+	return nil
+}
+
+func (c *Conversation) SetPhone(input string) error {
+	if c.State != StateAwaitingPhone {
+		return ErrUnexpectedInput
+	}
+	normalized, err := phone.Normalize(input)
+	if err != nil {
+		return err
+	}
+	c.Phone = normalized
+	c.State = StateAwaitingAddress
+	return nil
+}
+
+func (c *Conversation) SetAddress(input string) error {
+	if c.State != StateAwaitingAddress {
+		return ErrUnexpectedInput
+	}
+	address := strings.TrimSpace(input)
+	if len([]rune(address)) < minAddressLength {
+		return ErrAddressTooShort
+	}
+	c.Address = address
+	c.State = StateAwaitingConfirm
+	return nil
+}
+
+// Confirm returns the order the customer agreed to. The conversation stays in AWAITING_CONFIRM
+// until Placed is called, so a failed attempt can be confirmed again.
+func (c *Conversation) Confirm(shopID uuid.UUID) (*OrderRequest, error) {
+	if c.State != StateAwaitingConfirm {
+		return nil, ErrUnexpectedInput
+	}
+	return &OrderRequest{
+		ShopID:          shopID,
+		CustomerPhone:   c.Phone,
+		DeliveryAddress: c.Address,
+		Items:           []OrderItemRequest{{Code: c.ProductCode, Quantity: c.Quantity}},
+	}, nil
+}
+
+func (c *Conversation) Placed() {
+	c.Reset()
+}
+
+func (c *Conversation) Total() float64 {
+	return c.UnitPrice * float64(c.Quantity)
+}
+
+func (c *Conversation) clearDraft() {
+	c.ProductCode = ""
+	c.ProductName = ""
+	c.UnitPrice = 0
+	c.Available = 0
+	c.Quantity = 0
+}
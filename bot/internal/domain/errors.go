@@ -0,0 +1,14 @@
+package domain
+
+import "errors"
+
+var (
+	ErrUnexpectedInput = errors.New("input does not fit the conversation state")
+	ErrInvalidQuantity = errors.New("quantity must be a positive whole number")
+	ErrNotEnoughStock  = errors.New("not enough stock")
+	ErrAddressTooShort = errors.New("address is too short")
+
+	// Returned by the OrderGateway
+	ErrOutOfStock    = errors.New("a product ran out of stock")
+	ErrOrderRejected = errors.New("order rejected")
+)
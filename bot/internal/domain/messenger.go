@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// Channel is a chat platform customers order through.
+type Channel string
+
+const (
+	ChannelTelegram Channel = "TELEGRAM"
+	ChannelWhatsApp Channel = "WHATSAPP"
+)
+
+// IncomingMessage is a chat message normalized from any channel. ChatID identifies the chat within
+// its channel; ID is the platform's message id and is used to drop redelivered messages.
+type IncomingMessage struct {
+	Channel Channel
+	ID      string
+	ChatID  string
+	Text    string
+	// Contact is the sender's own phone number, shared with a contact button. Channels only set it
+	// when they can tell the number belongs to the sender.
+	Contact string
+	// SenderPhone is the sender's own number when the channel vouches for it, as WhatsApp does.
+	SenderPhone string
+}
+
+// OutgoingMessage is a reply to a chat. Buttons are offered as quick replies;
+// ContactButton, when set, labels an extra button that shares the customer's own phone number.
+// Channels that can't show a button drop it, so the text must make sense on its own.
+type OutgoingMessage struct {
+	Channel       Channel
+	ChatID        string
+	Text          string
+	Buttons       []string
+	ContactButton string
+}
+
+// Messenger sends messages to one channel.
+type Messenger interface {
+	SendMessage(message OutgoingMessage) error
+}
+
+// Update is a message polled from a channel together with its position in the update stream.
+// Message is nil for updates the bot doesn't handle, which still move the offset forward.
+type Update struct {
+	Offset  int64
+	Message *IncomingMessage
+}
+
+// Poller is a channel the bot pulls messages from instead of receiving webhooks.
+type Poller interface {
+	// Updates long-polls for updates at or after offset, waiting up to timeout.
+	Updates(offset int64, timeout time.Duration) ([]Update, error)
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Order statuses as reported by the order service.
+const (
+	StatusPending   = "PENDING"
+	StatusPaid      = "PAID"
+	StatusConfirmed = "CONFIRMED"
+	StatusShipped   = "SHIPPED"
+	StatusDelivered = "DELIVERED"
+	StatusCancelled = "CANCELLED"
+)
+
+// IsFinal reports whether an order with status can't change any more.
+func IsFinal(status string) bool {
+	return status == StatusDelivered || status == StatusCancelled
+}
+
+type OrderItemRequest struct {
+	Code     string `json:"code"`
+	Quantity int    `json:"quantity"`
+}
+
+// OrderRequest is the order a conversation ends with, in the shape of the order service's channel API.
+type OrderRequest struct {
+	ShopID          uuid.UUID          `json:"shop_id"`
+	CustomerPhone   string             `json:"customer_phone"`
+	DeliveryAddress string             `json:"delivery_address"`
+	Items           []OrderItemRequest `json:"items"`
+}
+
+// Order is the part of an order the bot reports back to the chat.
+type Order struct {
+	ID          uuid.UUID `json:"id"`
+	Status      string    `json:"status"`
+	TotalAmount float64   `json:"total_amount"`
+}
+
+// Subscription makes the bot tell a chat when the status of an order it placed changes.
+type Subscription struct {
+	OrderID    uuid.UUID
+	Channel    Channel
+	ChatID     string
+	LastStatus string
+	CreatedAt  time.Time
+}
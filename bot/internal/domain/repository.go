@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Catalog looks products up by the code customers see in captions.
+type Catalog interface {
+	// FindByCode returns nil if the shop has no active product with code.
+	FindByCode(shopID uuid.UUID, code string) (*Product, error)
+}
+
+// OrderGateway places and follows orders through the order service.
+type OrderGateway interface {
+	// PlaceOrder returns ErrOutOfStock or an error wrapping ErrOrderRejected when the order service
+	// refuses the order.
+	PlaceOrder(request *OrderRequest) (*Order, error)
+	FindOrder(orderID uuid.UUID) (*Order, error)
+}
+
+type ConversationRepository interface {
+	// Find returns nil if the chat never talked to the bot.
+	Find(channel Channel, chatID string) (*Conversation, error)
+	Save(conversation *Conversation) error
+}
+
+// MessageLog remembers handled messages, because webhooks are retried and may deliver a message twice.
+type MessageLog interface {
+	// MarkHandled records the message and reports false if it was recorded before.
+	MarkHandled(channel Channel, messageID string) (bool, error)
+	// Forget drops the records of messages handled before before.
+	Forget(before time.Time) error
+}
+
+type SubscriptionRepository interface {
+	Create(subscription *Subscription) error
+	// FindActive returns up to limit subscriptions of orders that may still change, least recently
+	// checked first.
+	FindActive(limit int) ([]*Subscription, error)
+	// UpdateStatus records the status the chat was last told about; a final status ends the subscription.
+	UpdateStatus(orderID uuid.UUID, status string) error
+	// Touch marks a subscription as checked without a change.
+	Touch(orderID uuid.UUID) error
+}
@@ -0,0 +1,128 @@
+// Package miniature calls the platform's own services on behalf of the bot.
+package miniature
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"miniature/bot/internal/domain"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ProductClient reads products from the product service's public code lookup.
+type ProductClient struct {
+	baseURL string
+	http    *http.Client
+}
+
+func NewProductClient(baseURL string, httpClient *http.Client) *ProductClient {
+	return &ProductClient{baseURL: strings.TrimRight(baseURL, "/"), http: httpClient}
+}
+
+func (c *ProductClient) FindByCode(shopID uuid.UUID, code string) (*domain.Product, error) {
+	endpoint := fmt.Sprintf("%s/v1/shops/%s/products/by-code/%s", c.baseURL, shopID, url.PathEscape(code))
+	resp, err := c.http.Get(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, unexpectedStatus(resp)
+	}
+	var product domain.Product
+	if err := json.NewDecoder(resp.Body).Decode(&product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// OrderClient uses the order service's channel API, authenticated with the shared channel key.
+type OrderClient struct {
+	baseURL    string
+	channelKey string
+	http       *http.Client
+}
+
+func NewOrderClient(baseURL, channelKey string, httpClient *http.Client) *OrderClient {
+	return &OrderClient{baseURL: strings.TrimRight(baseURL, "/"), channelKey: channelKey, http: httpClient}
+}
+
+func (c *OrderClient) PlaceOrder(request *domain.OrderRequest) (*domain.Order, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/v1/channel/orders", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusCreated:
+	case resp.StatusCode == http.StatusConflict:
+		return nil, domain.ErrOutOfStock
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		var decoded errorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&decoded)
+		return nil, fmt.Errorf("%w: %s", domain.ErrOrderRejected, decoded.Error)
+	default:
+		return nil, unexpectedStatus(resp)
+	}
+
+	var order domain.Order
+	if err := json.NewDecoder(resp.Body).Decode(&order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (c *OrderClient) FindOrder(orderID uuid.UUID) (*domain.Order, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/v1/channel/orders/"+orderID.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, unexpectedStatus(resp)
+	}
+	var order domain.Order
+	if err := json.NewDecoder(resp.Body).Decode(&order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (c *OrderClient) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("X-Channel-Key", c.channelKey)
+	return c.http.Do(req)
+}
+
+func unexpectedStatus(resp *http.Response) error {
+	var decoded errorResponse
+	_ = json.NewDecoder(resp.Body).Decode(&decoded)
+	return fmt.Errorf("%s %s: status %d: %s", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, decoded.Error)
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"database/sql"
+	"miniature/bot/internal/domain"
+)
+
+type conversationRepository struct {
+	db *sql.DB
+}
+
+func NewConversationRepository(db *sql.DB) *conversationRepository {
+	return &conversationRepository{db: db}
+}
+
+func (r *conversationRepository) Find(channel domain.Channel, chatID string) (*domain.Conversation, error) {
+	c := &domain.Conversation{}
+	query := `SELECT channel, chat_id, state, product_code, product_name, unit_price, available, quantity, phone, address, updated_at
+              FROM bot_conversations WHERE channel = $1 AND chat_id = $2`
+	err := r.db.QueryRow(query, channel, chatID).Scan(
+		&c.Channel, &c.ChatID, &c.State, &c.ProductCode, &c.ProductName, &c.UnitPrice, &c.Available, &c.Quantity,
+		&c.Phone, &c.Address, &c.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *conversationRepository) Save(c *domain.Conversation) error {
+	query := `INSERT INTO bot_conversations
+              (channel, chat_id, state, product_code, product_name, unit_price, available, quantity, phone, address, updated_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+              ON CONFLICT (channel, chat_id) DO UPDATE SET
+                state = EXCLUDED.state, product_code = EXCLUDED.product_code, product_name = EXCLUDED.product_name,
+                unit_price = EXCLUDED.unit_price, available = EXCLUDED.available, quantity = EXCLUDED.quantity,
+                phone = EXCLUDED.phone, address = EXCLUDED.address, updated_at = EXCLUDED.updated_at`
+	_, err := r.db.Exec(query,
+		c.Channel, c.ChatID, c.State, c.ProductCode, c.ProductName, c.UnitPrice, c.Available, c.Quantity,
+		c.Phone, c.Address, c.UpdatedAt,
+	)
+	return err
+}
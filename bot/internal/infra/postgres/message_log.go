@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"database/sql"
+	"miniature/bot/internal/domain"
+	"time"
+)
+
+type messageLog struct {
+	db *sql.DB
+}
+
+func NewMessageLog(db *sql.DB) *messageLog {
+	return &messageLog{db: db}
+}
+
+func (l *messageLog) MarkHandled(channel domain.Channel, messageID string) (bool, error) {
+	result, err := l.db.Exec(
+		`INSERT INTO bot_handled_messages (channel, message_id, handled_at) VALUES ($1, $2, NOW())
+         ON CONFLICT (channel, message_id) DO NOTHING`,
+		channel, messageID,
+	)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
+
+func (l *messageLog) Forget(before time.Time) error {
+	_, err := l.db.Exec(`DELETE FROM bot_handled_messages WHERE handled_at < $1`, before)
+	return err
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"database/sql"
+	"miniature/bot/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type subscriptionRepository struct {
+	db *sql.DB
+}
+
+func NewSubscriptionRepository(db *sql.DB) *subscriptionRepository {
+	return &subscriptionRepository{db: db}
+}
+
+func (r *subscriptionRepository) Create(s *domain.Subscription) error {
+	query := `INSERT INTO bot_order_subscriptions (order_id, channel, chat_id, last_status, is_active, checked_at, created_at)
+              VALUES ($1, $2, $3, $4, TRUE, $5, $5)`
+	_, err := r.db.Exec(query, s.OrderID, s.Channel, s.ChatID, s.LastStatus, s.CreatedAt)
+	return err
+}
+
+func (r *subscriptionRepository) FindActive(limit int) ([]*domain.Subscription, error) {
+	query := `SELECT order_id, channel, chat_id, last_status, created_at FROM bot_order_subscriptions
+              WHERE is_active ORDER BY checked_at LIMIT $1`
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*domain.Subscription
+	for rows.Next() {
+		s := &domain.Subscription{}
+		if err := rows.Scan(&s.OrderID, &s.Channel, &s.ChatID, &s.LastStatus, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	return subscriptions, rows.Err()
+}
+
+func (r *subscriptionRepository) UpdateStatus(orderID uuid.UUID, status string) error {
+	query := `UPDATE bot_order_subscriptions SET last_status = $1, is_active = $2, checked_at = NOW() WHERE order_id = $3`
+	_, err := r.db.Exec(query, status, !domain.IsFinal(status), orderID)
+	return err
+}
+
+func (r *subscriptionRepository) Touch(orderID uuid.UUID) error {
+	_, err := r.db.Exec(`UPDATE bot_order_subscriptions SET checked_at = NOW() WHERE order_id = $1`, orderID)
+	return err
+}
@@ -0,0 +1,158 @@
+// Package telegram is a small client for the Telegram Bot API, covering what the ordering bot needs.
+package telegram
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"miniature/bot/internal/domain"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewClient talks to the Bot API at baseURL, normally https://api.telegram.org. httpClient must
+// allow requests longer than the long-polling timeout passed to Updates.
+func NewClient(baseURL, token string, httpClient *http.Client) *Client {
+	return &Client{baseURL: strings.TrimRight(baseURL, "/"), token: token, http: httpClient}
+}
+
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	Description string          `json:"description"`
+}
+
+type update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *message `json:"message"`
+}
+
+type message struct {
+	Chat struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+	From *struct {
+		ID int64 `json:"id"`
+	} `json:"from"`
+	Text    string `json:"text"`
+	Contact *struct {
+		PhoneNumber string `json:"phone_number"`
+		UserID      int64  `json:"user_id"`
+	} `json:"contact"`
+}
+
+// ownContact returns the phone number of a shared contact if it is the sender's own. Any contact
+// can be shared, but only the contact button sends one whose user_id is the sender's.
+func (m *message) ownContact() string {
+	if m.Contact == nil || m.From == nil || m.Contact.UserID == 0 || m.Contact.UserID != m.From.ID {
+		return ""
+	}
+	return m.Contact.PhoneNumber
+}
+
+type keyboardButton struct {
+	Text           string `json:"text"`
+	RequestContact bool   `json:"request_contact,omitempty"`
+}
+
+type replyKeyboard struct {
+	Keyboard        [][]keyboardButton `json:"keyboard,omitempty"`
+	ResizeKeyboard  bool               `json:"resize_keyboard,omitempty"`
+	OneTimeKeyboard bool               `json:"one_time_keyboard,omitempty"`
+	RemoveKeyboard  bool               `json:"remove_keyboard,omitempty"`
+}
+
+type sendMessageRequest struct {
+	ChatID      int64         `json:"chat_id"`
+	Text        string        `json:"text"`
+	ReplyMarkup replyKeyboard `json:"reply_markup"`
+}
+
+func (c *Client) Updates(offset int64, timeout time.Duration) ([]domain.Update, error) {
+	request := map[string]any{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message"},
+	}
+	var updates []update
+	if err := c.call("getUpdates", request, &updates); err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Update, 0, len(updates))
+	for _, u := range updates {
+		// Edits, channel posts and the like have no message; they still move the offset forward
+		incoming := domain.Update{Offset: u.UpdateID}
+		if u.Message != nil {
+			incoming.Message = &domain.IncomingMessage{
+				Channel: domain.ChannelTelegram,
+				ID:      strconv.FormatInt(u.UpdateID, 10),
+				ChatID:  strconv.FormatInt(u.Message.Chat.ID, 10),
+				Text:    u.Message.Text,
+				Contact: u.Message.ownContact(),
+			}
+		}
+		result = append(result, incoming)
+	}
+	return result, nil
+}
+
+func (c *Client) SendMessage(msg domain.OutgoingMessage) error {
+	chatID, err := strconv.ParseInt(msg.ChatID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("telegram chat id %q: %w", msg.ChatID, err)
+	}
+	request := sendMessageRequest{ChatID: chatID, Text: msg.Text}
+	if msg.ContactButton != "" {
+		request.ReplyMarkup.Keyboard = append(request.ReplyMarkup.Keyboard, []keyboardButton{{Text: msg.ContactButton, RequestContact: true}})
+	}
+	for _, text := range msg.Buttons {
+		request.ReplyMarkup.Keyboard = append(request.ReplyMarkup.Keyboard, []keyboardButton{{Text: text}})
+	}
+	if len(request.ReplyMarkup.Keyboard) > 0 {
+		request.ReplyMarkup.ResizeKeyboard = true
+		request.ReplyMarkup.OneTimeKeyboard = true
+	} else {
+		request.ReplyMarkup.RemoveKeyboard = true
+	}
+	return c.call("sendMessage", request, nil)
+}
+
+func (c *Client) call(method string, request any, result any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Post(c.baseURL+"/bot"+c.token+"/"+method, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// The URL holds the token, so only the underlying error is reported
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("telegram %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var decoded apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return fmt.Errorf("telegram %s: status %d: %w", method, resp.StatusCode, err)
+	}
+	if !decoded.OK {
+		return fmt.Errorf("telegram %s: %s", method, decoded.Description)
+	}
+	if result != nil {
+		return json.Unmarshal(decoded.Result, result)
+	}
+	return nil
+}
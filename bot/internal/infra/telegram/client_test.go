@@ -0,0 +1,129 @@
+package telegram
+
+import (
+	"encoding/json"
+	"io"
+	"miniature/bot/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeBotAPI answers Bot API methods with canned results and keeps the requests it got.
+type fakeBotAPI struct {
+	t        *testing.T
+	results  map[string]string
+	requests map[string][]map[string]any
+}
+
+func newFakeBotAPI(t *testing.T, results map[string]string) (*fakeBotAPI, *Client) {
+	api := &fakeBotAPI{t: t, results: results, requests: map[string][]map[string]any{}}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	return api, NewClient(server.URL+"/", "123:secret", server.Client())
+}
+
+func (f *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method, ok := strings.CutPrefix(r.URL.Path, "/bot123:secret/")
+	if !ok || r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `{"ok":false,"error_code":404,"description":"Not Found"}`)
+		return
+	}
+	var request map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		f.t.Errorf("%s: request is not JSON: %v", method, err)
+	}
+	f.requests[method] = append(f.requests[method], request)
+
+	result, ok := f.results[method]
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`)
+		return
+	}
+	io.WriteString(w, `{"ok":true,"result":`+result+`}`)
+}
+
+func TestUpdates(t *testing.T) {
+	api, client := newFakeBotAPI(t, map[string]string{"getUpdates": `[
+		{"update_id": 10, "message": {"chat": {"id": 42}, "from": {"id": 42}, "text": "SL38"}},
+		{"update_id": 11, "message": {"chat": {"id": 42}, "from": {"id": 42},
+			"contact": {"phone_number": "+989121234567", "user_id": 42}}},
+		{"update_id": 12, "message": {"chat": {"id": 42}, "from": {"id": 42},
+			"contact": {"phone_number": "+989351234567", "user_id": 77}}},
+		{"update_id": 13, "message": {"chat": {"id": 42}, "from": {"id": 42},
+			"contact": {"phone_number": "+989351234567"}}},
+		{"update_id": 14, "edited_message": {"chat": {"id": 42}, "text": "SL39"}}
+	]`})
+
+	updates, err := client.Updates(10, 30*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := func(id, text, contact string) *domain.IncomingMessage {
+		return &domain.IncomingMessage{Channel: domain.ChannelTelegram, ID: id, ChatID: "42", Text: text, Contact: contact}
+	}
+	want := []domain.Update{
+		{Offset: 10, Message: message("10", "SL38", "")},
+		{Offset: 11, Message: message("11", "", "+989121234567")},
+		{Offset: 12, Message: message("12", "", "")}, // someone else's contact
+		{Offset: 13, Message: message("13", "", "")}, // a contact that is not a Telegram user
+		{Offset: 14},
+	}
+	if !reflect.DeepEqual(updates, want) {
+		t.Errorf("Updates =\n%+v\nwant\n%+v", updates, want)
+	}
+
+	request := api.requests["getUpdates"][0]
+	if request["offset"] != 10.0 || request["timeout"] != 30.0 {
+		t.Errorf("getUpdates request = %v", request)
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	api, client := newFakeBotAPI(t, map[string]string{"sendMessage": `{"message_id": 1}`})
+
+	err := client.SendMessage(domain.OutgoingMessage{ChatID: "42", Text: "شماره؟", ContactButton: "ارسال شماره"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.SendMessage(domain.OutgoingMessage{ChatID: "42", Text: "ثبت شد"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := api.requests["sendMessage"]
+	if len(requests) != 2 {
+		t.Fatalf("sent %d messages, want 2", len(requests))
+	}
+	withButton := requests[0]["reply_markup"].(map[string]any)
+	button := withButton["keyboard"].([]any)[0].([]any)[0].(map[string]any)
+	if requests[0]["chat_id"] != 42.0 || button["text"] != "ارسال شماره" || button["request_contact"] != true {
+		t.Errorf("message with a contact button = %v", requests[0])
+	}
+	if markup := requests[1]["reply_markup"].(map[string]any); markup["remove_keyboard"] != true {
+		t.Errorf("message without buttons does not remove the keyboard: %v", requests[1])
+	}
+}
+
+func TestCallErrors(t *testing.T) {
+	_, client := newFakeBotAPI(t, nil)
+
+	err := client.SendMessage(domain.OutgoingMessage{ChatID: "42", Text: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "chat not found") {
+		t.Errorf("SendMessage error = %v, want the API's description", err)
+	}
+	if err := client.SendMessage(domain.OutgoingMessage{ChatID: "not-a-chat", Text: "hi"}); err == nil {
+		t.Error("SendMessage accepted a chat id that is not a number")
+	}
+
+	unreachable := NewClient("http://127.0.0.1:1", "123:secret", &http.Client{Timeout: time.Second})
+	_, err = unreachable.Updates(0, 0)
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("Updates error = %v, want an error that does not leak the token", err)
+	}
+}
@@ -0,0 +1,135 @@
+// Package whatsapp connects the bot to the WhatsApp Business Cloud API: an outbound client for
+// replies and the parsing and signature check of incoming webhooks.
+package whatsapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"miniature/bot/internal/domain"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits of interactive reply buttons.
+const (
+	maxButtons     = 3
+	maxButtonTitle = 20
+)
+
+type Client struct {
+	baseURL       string
+	phoneNumberID string
+	accessToken   string
+	http          *http.Client
+}
+
+// NewClient sends messages from the business number phoneNumberID through the Graph API at
+// baseURL, normally https://graph.facebook.com/<version>; tests point it at a local stub.
+func NewClient(baseURL, phoneNumberID, accessToken string, httpClient *http.Client) *Client {
+	return &Client{
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		phoneNumberID: phoneNumberID,
+		accessToken:   accessToken,
+		http:          httpClient,
+	}
+}
+
+type textBody struct {
+	Body string `json:"body"`
+}
+
+type replyButton struct {
+	Type  string `json:"type"`
+	Reply struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	} `json:"reply"`
+}
+
+type interactive struct {
+	Type   string   `json:"type"`
+	Body   textBody `json:"body"`
+	Action struct {
+		Buttons []replyButton `json:"buttons"`
+	} `json:"action"`
+}
+
+type sendRequest struct {
+	MessagingProduct string       `json:"messaging_product"`
+	RecipientType    string       `json:"recipient_type"`
+	To               string       `json:"to"`
+	Type             string       `json:"type"`
+	Text             *textBody    `json:"text,omitempty"`
+	Interactive      *interactive `json:"interactive,omitempty"`
+}
+
+type errorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"error"`
+}
+
+// SendMessage sends msg as reply buttons when they fit WhatsApp's limits and as plain text
+// otherwise. There is no contact button: WhatsApp tells the bot the sender's number anyway.
+func (c *Client) SendMessage(msg domain.OutgoingMessage) error {
+	request := sendRequest{
+		MessagingProduct: "whatsapp",
+		RecipientType:    "individual",
+		To:               msg.ChatID,
+	}
+	if buttons := replyButtons(msg.Buttons); len(buttons) > 0 {
+		request.Type = "interactive"
+		request.Interactive = &interactive{Type: "button", Body: textBody{Body: msg.Text}}
+		request.Interactive.Action.Buttons = buttons
+	} else {
+		request.Type = "text"
+		request.Text = &textBody{Body: msg.Text}
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/"+c.phoneNumberID+"/messages", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var decoded errorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&decoded)
+		return fmt.Errorf("whatsapp send: status %d: %s (code %d)", resp.StatusCode, decoded.Error.Message, decoded.Error.Code)
+	}
+	return nil
+}
+
+// replyButtons returns the buttons to show, or none if any of them breaks WhatsApp's limits;
+// dropping only some would offer the customer a choice that is incomplete.
+func replyButtons(titles []string) []replyButton {
+	if len(titles) == 0 || len(titles) > maxButtons {
+		return nil
+	}
+	buttons := make([]replyButton, 0, len(titles))
+	for i, title := range titles {
+		if utf8.RuneCountInString(title) > maxButtonTitle {
+			return nil
+		}
+		button := replyButton{Type: "reply"}
+		button.Reply.ID = strconv.Itoa(i)
+		button.Reply.Title = title
+		buttons = append(buttons, button)
+	}
+	return buttons
+}
@@ -0,0 +1,108 @@
+package whatsapp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"miniature/bot/internal/domain"
+	"strings"
+)
+
+const signaturePrefix = "sha256="
+
+// VerifySignature checks the X-Hub-Signature-256 header Meta sends with every webhook: an
+// HMAC-SHA256 of the raw body keyed with the app secret.
+func VerifySignature(appSecret string, body []byte, header string) bool {
+	if appSecret == "" || !strings.HasPrefix(header, signaturePrefix) {
+		return false
+	}
+	given, err := hex.DecodeString(strings.TrimPrefix(header, signaturePrefix))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(appSecret))
+	mac.Write(body)
+	return hmac.Equal(given, mac.Sum(nil))
+}
+
+type webhook struct {
+	Object string `json:"object"`
+	Entry  []struct {
+		Changes []struct {
+			Field string `json:"field"`
+			Value struct {
+				Metadata struct {
+					PhoneNumberID string `json:"phone_number_id"`
+				} `json:"metadata"`
+				Messages []message `json:"messages"`
+			} `json:"value"`
+		} `json:"changes"`
+	} `json:"entry"`
+}
+
+type message struct {
+	From string `json:"from"`
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Text struct {
+		Body string `json:"body"`
+	} `json:"text"`
+	Interactive struct {
+		ButtonReply struct {
+			Title string `json:"title"`
+		} `json:"button_reply"`
+	} `json:"interactive"`
+	Button struct {
+		Text string `json:"text"`
+	} `json:"button"`
+}
+
+// ParseWebhook extracts the customer messages sent to the business number phoneNumberID.
+// Delivery statuses, other numbers of the same account and media messages are skipped.
+func ParseWebhook(body []byte, phoneNumberID string) ([]domain.IncomingMessage, error) {
+	var payload webhook
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	var messages []domain.IncomingMessage
+	for _, entry := range payload.Entry {
+		for _, change := range entry.Changes {
+			if change.Field != "messages" || change.Value.Metadata.PhoneNumberID != phoneNumberID {
+				continue
+			}
+			for _, m := range change.Value.Messages {
+				incoming, ok := normalize(m)
+				if ok {
+					messages = append(messages, incoming)
+				}
+			}
+		}
+	}
+	return messages, nil
+}
+
+// normalize turns a WhatsApp message into the channel-neutral model. The chat is the customer's
+// WhatsApp id, which is their phone number without the leading +. Shared contact cards are skipped:
+// they can hold anyone's number, and the sender's own is already known.
+func normalize(m message) (domain.IncomingMessage, bool) {
+	incoming := domain.IncomingMessage{
+		Channel:     domain.ChannelWhatsApp,
+		ID:          m.ID,
+		ChatID:      m.From,
+		SenderPhone: "+" + m.From,
+	}
+	switch m.Type {
+	case "text":
+		incoming.Text = m.Text.Body
+	case "interactive":
+		// Replies are matched by their title, like typed text
+		incoming.Text = m.Interactive.ButtonReply.Title
+	case "button":
+		incoming.Text = m.Button.Text
+	default:
+		return incoming, false
+	}
+	return incoming, m.From != ""
+}
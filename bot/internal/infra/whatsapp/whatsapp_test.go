@@ -0,0 +1,150 @@
+package whatsapp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"miniature/bot/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"object":"whatsapp_business_account"}`)
+	valid := sign("app-secret", body)
+	tests := []struct {
+		name   string
+		secret string
+		body   []byte
+		header string
+		want   bool
+	}{
+		{"valid", "app-secret", body, valid, true},
+		{"upper-case hex", "app-secret", body, signaturePrefix + strings.ToUpper(strings.TrimPrefix(valid, signaturePrefix)), true},
+		{"other secret", "other-secret", body, valid, false},
+		{"changed body", "app-secret", []byte(`{"object":"page"}`), valid, false},
+		{"no prefix", "app-secret", body, strings.TrimPrefix(valid, signaturePrefix), false},
+		{"sha1 header", "app-secret", body, "sha1=" + strings.TrimPrefix(valid, signaturePrefix), false},
+		{"not hex", "app-secret", body, signaturePrefix + "zz", false},
+		{"missing header", "app-secret", body, "", false},
+		{"no secret configured", "", body, sign("", body), false},
+	}
+	for _, tt := range tests {
+		if got := VerifySignature(tt.secret, tt.body, tt.header); got != tt.want {
+			t.Errorf("%s: VerifySignature = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseWebhook(t *testing.T) {
+	body := []byte(`{
+		"object": "whatsapp_business_account",
+		"entry": [{"changes": [
+			{"field": "messages", "value": {
+				"metadata": {"phone_number_id": "111"},
+				"messages": [
+					{"from": "989121234567", "id": "wamid.1", "type": "text", "text": {"body": "SL38"}},
+					{"from": "989121234567", "id": "wamid.2", "type": "interactive",
+						"interactive": {"type": "button_reply", "button_reply": {"id": "0", "title": "تأیید سفارش"}}},
+					{"from": "989121234567", "id": "wamid.3", "type": "button", "button": {"text": "انصراف"}},
+					{"from": "989121234567", "id": "wamid.4", "type": "contacts",
+						"contacts": [{"phones": [{"phone": "+989351234567", "wa_id": "989351234567"}]}]},
+					{"from": "989121234567", "id": "wamid.5", "type": "image", "image": {"id": "media"}},
+					{"id": "wamid.6", "type": "text", "text": {"body": "no sender"}}
+				]
+			}},
+			{"field": "messages", "value": {
+				"metadata": {"phone_number_id": "222"},
+				"messages": [{"from": "989121234567", "id": "wamid.7", "type": "text", "text": {"body": "other number"}}]
+			}},
+			{"field": "messages", "value": {
+				"metadata": {"phone_number_id": "111"},
+				"statuses": [{"id": "wamid.8", "status": "delivered"}]
+			}}
+		]}]
+	}`)
+
+	messages, err := ParseWebhook(body, "111")
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := func(id, text string) domain.IncomingMessage {
+		return domain.IncomingMessage{
+			Channel: domain.ChannelWhatsApp, ID: id, ChatID: "989121234567", Text: text, SenderPhone: "+989121234567",
+		}
+	}
+	want := []domain.IncomingMessage{
+		message("wamid.1", "SL38"),
+		message("wamid.2", "تأیید سفارش"),
+		message("wamid.3", "انصراف"),
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("ParseWebhook =\n%+v\nwant\n%+v", messages, want)
+	}
+
+	if _, err := ParseWebhook([]byte(`{"entry": [`), "111"); err == nil {
+		t.Error("ParseWebhook accepted a cut body")
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v19.0/111/messages" || r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"error": {"message": "Invalid OAuth access token.", "code": 190}}`)
+			return
+		}
+		var request map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("request is not JSON: %v", err)
+		}
+		requests = append(requests, request)
+		io.WriteString(w, `{"messaging_product": "whatsapp", "messages": [{"id": "wamid.out"}]}`)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL+"/v19.0/", "111", "access-token", server.Client())
+
+	messages := []domain.OutgoingMessage{
+		{ChatID: "989121234567", Text: "سفارش را تأیید می‌کنید؟", Buttons: []string{"تأیید سفارش", "انصراف"}},
+		{ChatID: "989121234567", Text: "آدرس کامل تحویل را بفرستید.", Buttons: []string{strings.Repeat("خ", maxButtonTitle+1)}},
+		{ChatID: "989121234567", Text: "کد محصول را بفرستید.", ContactButton: "ارسال شماره من"},
+	}
+	for _, msg := range messages {
+		if err := client.SendMessage(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(requests) != 3 {
+		t.Fatalf("sent %d requests, want 3", len(requests))
+	}
+	interactive := requests[0]["interactive"].(map[string]any)
+	buttons := interactive["action"].(map[string]any)["buttons"].([]any)
+	if requests[0]["type"] != "interactive" || len(buttons) != 2 ||
+		buttons[1].(map[string]any)["reply"].(map[string]any)["title"] != "انصراف" {
+		t.Errorf("message with buttons = %v", requests[0])
+	}
+	for _, request := range requests[1:] {
+		if request["type"] != "text" || request["to"] != "989121234567" || request["interactive"] != nil {
+			t.Errorf("message without usable buttons = %v, want plain text", request)
+		}
+	}
+
+	unauthorized := NewClient(server.URL+"/v19.0", "111", "expired", server.Client())
+	err := unauthorized.SendMessage(domain.OutgoingMessage{ChatID: "989121234567", Text: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "Invalid OAuth access token") || !strings.Contains(err.Error(), "190") {
+		t.Errorf("SendMessage error = %v, want the API's message and code", err)
+	}
+}
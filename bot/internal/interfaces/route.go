@@ -0,0 +1,19 @@
+package interfaces
+
+import "github.com/gin-gonic/gin"
+
+func NewRouter(whatsApp *WhatsAppHandler) *gin.Engine {
+	r := gin.Default()
+
+	// Health check endpoint
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "UP"})
+	})
+
+	webhooks := r.Group("/webhooks")
+	{
+		webhooks.GET("/whatsapp", whatsApp.Verify)
+		webhooks.POST("/whatsapp", whatsApp.Receive)
+	}
+	return r
+}
@@ -0,0 +1,60 @@
+package interfaces
+
+import (
+	"io"
+	"log"
+	"miniature/bot/internal/application"
+	"miniature/bot/internal/infra/whatsapp"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookBody is far above what Meta sends in one webhook call.
+const maxWebhookBody = 1 << 20
+
+type WhatsAppHandler struct {
+	bot           *application.Bot
+	phoneNumberID string
+	appSecret     string
+	verifyToken   string
+}
+
+func NewWhatsAppHandler(bot *application.Bot, phoneNumberID, appSecret, verifyToken string) *WhatsAppHandler {
+	return &WhatsAppHandler{bot: bot, phoneNumberID: phoneNumberID, appSecret: appSecret, verifyToken: verifyToken}
+}
+
+// Verify answers the challenge Meta sends when the webhook URL is registered.
+func (h *WhatsAppHandler) Verify(c *gin.Context) {
+	if c.Query("hub.mode") != "subscribe" || h.verifyToken == "" || c.Query("hub.verify_token") != h.verifyToken {
+		c.JSON(http.StatusForbidden, gin.H{"error": "verification failed"})
+		return
+	}
+	c.String(http.StatusOK, c.Query("hub.challenge"))
+}
+
+// Receive handles a webhook call. Once the signature is valid the call is always acknowledged:
+// a message that fails is logged, because a retry would not be handled again anyway.
+func (h *WhatsAppHandler) Receive(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBody))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "could not read body"})
+		return
+	}
+	if !whatsapp.VerifySignature(h.appSecret, body, c.GetHeader("X-Hub-Signature-256")) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid signature"})
+		return
+	}
+
+	messages, err := whatsapp.ParseWebhook(body, h.phoneNumberID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	for _, message := range messages {
+		if err := h.bot.HandleMessage(message); err != nil {
+			log.Printf("handling WhatsApp message %s from %s: %v", message.ID, message.ChatID, err)
+		}
+	}
+	c.Status(http.StatusOK)
+}
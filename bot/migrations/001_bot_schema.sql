@@ -0,0 +1,27 @@
+-- Ordering conversations, one per chat
+CREATE TABLE IF NOT EXISTS bot_conversations (
+    chat_id BIGINT PRIMARY KEY,
+    state TEXT NOT NULL CHECK (state IN (
+        'IDLE', 'AWAITING_CODE', 'AWAITING_QUANTITY', 'AWAITING_PHONE', 'AWAITING_ADDRESS', 'AWAITING_CONFIRM'
+    )),
+    product_code TEXT NOT NULL DEFAULT '',
+    product_name TEXT NOT NULL DEFAULT '',
+    unit_price DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    available INTEGER NOT NULL DEFAULT 0,
+    quantity INTEGER NOT NULL DEFAULT 0,
+    phone VARCHAR(20) NOT NULL DEFAULT '',
+    address TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Orders placed by the bot whose status changes are reported back to the chat
+CREATE TABLE IF NOT EXISTS bot_order_subscriptions (
+    order_id UUID PRIMARY KEY,
+    chat_id BIGINT NOT NULL,
+    last_status TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    checked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bot_order_subscriptions_active ON bot_order_subscriptions (checked_at) WHERE is_active;
@@ -0,0 +1,22 @@
+-- Chats are identified per channel; WhatsApp chat ids are phone numbers, so ids become text
+ALTER TABLE bot_conversations ADD COLUMN IF NOT EXISTS channel TEXT NOT NULL DEFAULT 'TELEGRAM'
+    CHECK (channel IN ('TELEGRAM', 'WHATSAPP'));
+ALTER TABLE bot_conversations ALTER COLUMN chat_id TYPE TEXT USING chat_id::TEXT;
+ALTER TABLE bot_conversations DROP CONSTRAINT IF EXISTS bot_conversations_pkey;
+ALTER TABLE bot_conversations ADD PRIMARY KEY (channel, chat_id);
+ALTER TABLE bot_conversations ALTER COLUMN channel DROP DEFAULT;
+
+ALTER TABLE bot_order_subscriptions ADD COLUMN IF NOT EXISTS channel TEXT NOT NULL DEFAULT 'TELEGRAM'
+    CHECK (channel IN ('TELEGRAM', 'WHATSAPP'));
+ALTER TABLE bot_order_subscriptions ALTER COLUMN chat_id TYPE TEXT USING chat_id::TEXT;
+ALTER TABLE bot_order_subscriptions ALTER COLUMN channel DROP DEFAULT;
+
+-- Webhooks are retried, so handled message ids are kept to ignore redeliveries
+CREATE TABLE IF NOT EXISTS bot_handled_messages (
+    channel TEXT NOT NULL,
+    message_id TEXT NOT NULL,
+    handled_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (channel, message_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bot_handled_messages_handled_at ON bot_handled_messages (handled_at);
@@ -3,9 +3,13 @@ package main
 import (
 	"log"
 	"miniature/customer/internal/application"
+	"miniature/customer/internal/config"
 	"miniature/customer/internal/domain"
 	"miniature/customer/internal/infra/postgres"
+	"miniature/customer/internal/infra/sms"
 	"miniature/customer/internal/interfaces"
+	"miniature/pkg/ratelimit"
+	"miniature/pkg/token"
 )
 
 func main() {
@@ -14,9 +18,24 @@ func main() {
 	defer db.Close()
 
 	var repo domain.CustomerRepository = postgres.NewCustomerRepository(db)
-	var usecase application.CustomerUsecase = application.NewCustomerService(repo)
+	var otpRepo domain.OTPRepository = postgres.NewOTPRepository(db)
+	var refreshRepo domain.RefreshTokenRepository = postgres.NewRefreshTokenRepository(db)
+	var sender domain.SMSSender = sms.NewLogSender()
+	revocations := token.NewPostgresRevocationStore(db)
+	signer, err := token.NewSignerFromConfig()
+	if err != nil {
+		log.Fatalf("cannot load signing keys: %v", err)
+	}
+
+	var limits ratelimit.Store = ratelimit.NewMemoryStore()
+	if config.RateLimitStore == "postgres" {
+		limits = ratelimit.NewPostgresStore(db)
+	}
+	guard := application.NewAbuseGuard(limits, postgres.NewAuditRepository(db))
+
+	var usecase application.CustomerUsecase = application.NewCustomerService(repo, otpRepo, refreshRepo, signer, revocations, sender, guard)
 	handler := interfaces.NewCustomerHandler(usecase)
-	route := interfaces.NewRouter(*handler)
+	route := interfaces.NewRouter(*handler, token.NewAuthenticator(signer, revocations), signer)
 
 	addr := "localhost:8080"
 	route.Run(addr)
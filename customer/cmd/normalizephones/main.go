@@ -0,0 +1,80 @@
+// Command normalizephones rewrites every customer phone number to E.164 (+989XXXXXXXXX).
+//
+// Numbers that cannot be parsed, or that would collide with another customer once
+// normalized, are reported and left untouched so they can be merged by hand.
+package main
+
+import (
+	"flag"
+	"log"
+	"miniature/customer/internal/infra/postgres"
+	"miniature/pkg/phone"
+)
+
+type row struct {
+	id    string
+	phone string
+}
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report the changes without writing them")
+	flag.Parse()
+
+	db := postgres.NewPostgresConnection()
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT id, phone FROM customer ORDER BY created_at`)
+	if err != nil {
+		log.Fatalf("cannot list customers: %v", err)
+	}
+	var customers []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.id, &r.phone); err != nil {
+			log.Fatalf("cannot read customer: %v", err)
+		}
+		customers = append(customers, r)
+	}
+	if err := rows.Err(); err != nil {
+		log.Fatalf("cannot list customers: %v", err)
+	}
+	rows.Close()
+
+	// Current owner of every phone value, so collisions are caught before they hit the unique index.
+	owners := make(map[string]string, len(customers))
+	for _, c := range customers {
+		owners[c.phone] = c.id
+	}
+
+	var updated, unchanged, invalid, conflicts int
+	for _, c := range customers {
+		normalized, err := phone.Normalize(c.phone)
+		if err != nil {
+			log.Printf("⚠️ customer %s: %q: %v", c.id, c.phone, err)
+			invalid++
+			continue
+		}
+		if normalized == c.phone {
+			unchanged++
+			continue
+		}
+		if owner, taken := owners[normalized]; taken && owner != c.id {
+			log.Printf("⚠️ customer %s: %q normalizes to %s, which belongs to customer %s", c.id, c.phone, normalized, owner)
+			conflicts++
+			continue
+		}
+
+		log.Printf("customer %s: %q -> %s", c.id, c.phone, normalized)
+		if !*dryRun {
+			if _, err := db.Exec(`UPDATE customer SET phone = $1 WHERE id = $2`, normalized, c.id); err != nil {
+				log.Fatalf("cannot update customer %s: %v", c.id, err)
+			}
+		}
+		delete(owners, c.phone)
+		owners[normalized] = c.id
+		updated++
+	}
+
+	log.Printf("✅ %d updated, %d already normalized, %d invalid, %d conflicts (dry run: %t)",
+		updated, unchanged, invalid, conflicts, *dryRun)
+}
@@ -0,0 +1,99 @@
+package application
+
+import (
+	"log"
+	"miniature/customer/internal/config"
+	"miniature/customer/internal/domain"
+	"miniature/pkg/ratelimit"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AbuseGuard rate limits the unauthenticated auth endpoints per IP and per phone and phone changes
+// per customer, locks a phone out after repeated wrong codes and records every refused attempt.
+type AbuseGuard struct {
+	registerByIP       *ratelimit.Limiter
+	loginByIP          *ratelimit.Limiter
+	loginByPhone       *ratelimit.Limiter // every code texted to a phone, whatever it is for
+	verifyByIP         *ratelimit.Limiter
+	phoneChangeByUser  *ratelimit.Limiter
+	lockout            *ratelimit.Lockout
+	phoneChangeLockout *ratelimit.Lockout // kept apart so guessing a phone change code can't lock the owner's login
+	audit              domain.AuditRepository
+}
+
+func NewAbuseGuard(store ratelimit.Store, audit domain.AuditRepository) *AbuseGuard {
+	return &AbuseGuard{
+		registerByIP: ratelimit.NewLimiter(store, "register:ip", config.RegisterIPLimit, config.RateLimitWindow),
+		loginByIP:    ratelimit.NewLimiter(store, "login:ip", config.LoginIPLimit, config.RateLimitWindow),
+		loginByPhone: ratelimit.NewLimiter(store, "login:phone", config.LoginPhoneLimit, config.RateLimitWindow),
+		verifyByIP:   ratelimit.NewLimiter(store, "verify:ip", config.VerifyIPLimit, config.RateLimitWindow),
+		phoneChangeByUser: ratelimit.NewLimiter(
+			store, "phone_change:user", config.PhoneChangeUserLimit, config.RateLimitWindow,
+		),
+		lockout: ratelimit.NewLockout(store, "verify:phone", config.LockoutThreshold, config.LockoutWindow, config.LockoutDurations),
+		phoneChangeLockout: ratelimit.NewLockout(
+			store, "phone_change:phone", config.LockoutThreshold, config.LockoutWindow, config.LockoutDurations,
+		),
+		audit: audit,
+	}
+}
+
+func (g *AbuseGuard) allow(limiter *ratelimit.Limiter, key, action, phone, ip, reason string) error {
+	allowed, retryAfter, err := limiter.Allow(key)
+	if err != nil {
+		return err
+	}
+	if allowed {
+		return nil
+	}
+
+	g.record(action, phone, ip, reason)
+	return &domain.RateLimitError{RetryAfter: retryAfter}
+}
+
+func (g *AbuseGuard) checkLockout(lockout *ratelimit.Lockout, action, phone, ip string) error {
+	remaining, err := lockout.Locked(phone)
+	if err != nil {
+		return err
+	}
+	if remaining <= 0 {
+		return nil
+	}
+
+	g.record(action, phone, ip, "phone_locked")
+	return &domain.RateLimitError{RetryAfter: remaining}
+}
+
+// verificationFailed counts a wrong code against phone and locks it once the threshold is reached.
+func (g *AbuseGuard) verificationFailed(lockout *ratelimit.Lockout, action, phone, ip string) error {
+	duration, err := lockout.Failure(phone)
+	if err != nil {
+		return err
+	}
+	if duration > 0 {
+		g.record(action, phone, ip, "lockout_started")
+	}
+	return nil
+}
+
+func (g *AbuseGuard) verificationSucceeded(lockout *ratelimit.Lockout, phone string) error {
+	return lockout.Success(phone)
+}
+
+// record writes to the audit log. A failing audit write must not turn a refused
+// attempt into a server error, so it is only logged.
+func (g *AbuseGuard) record(action, phone, ip, reason string) {
+	event := domain.AuthEvent{
+		ID:        uuid.New(),
+		Action:    action,
+		Phone:     phone,
+		IP:        ip,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+	if err := g.audit.Record(&event); err != nil {
+		log.Printf("cannot record auth audit event %s/%s: %v", action, reason, err)
+	}
+}
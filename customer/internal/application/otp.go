@@ -0,0 +1,160 @@
+package application
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"miniature/customer/internal/config"
+	"miniature/customer/internal/domain"
+	"miniature/pkg/phone"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestLoginOTP texts a login code to phone. It behaves the same whether or not
+// the phone is registered, so the endpoint cannot be used to enumerate customers.
+func (cs *customerService) RequestLoginOTP(rawPhone, ip string) error {
+	phone, err := phone.Normalize(rawPhone)
+	if err != nil {
+		return err
+	}
+
+	if err := cs.guard.allow(cs.guard.loginByIP, ip, domain.AuthActionLogin, phone, ip, "ip_rate_limited"); err != nil {
+		return err
+	}
+	if err := cs.guard.checkLockout(cs.guard.lockout, domain.AuthActionLogin, phone, ip); err != nil {
+		return err
+	}
+	if err := cs.guard.allow(cs.guard.loginByPhone, phone, domain.AuthActionLogin, phone, ip, "phone_rate_limited"); err != nil {
+		return err
+	}
+
+	customer, err := cs.repo.FindByPhone(phone)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return nil
+	}
+
+	return cs.sendOTP(phone, domain.OTPPurposeLogin, nil, "Your login code is %s")
+}
+
+// VerifyLoginOTP returns the customer owning phone if code is the last code sent to it.
+// An unknown phone fails exactly like a wrong code.
+func (cs *customerService) VerifyLoginOTP(rawPhone, code, ip string) (*domain.Customer, error) {
+	phone, err := phone.Normalize(rawPhone)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cs.guard.allow(cs.guard.verifyByIP, ip, domain.AuthActionLoginVerify, phone, ip, "ip_rate_limited"); err != nil {
+		return nil, err
+	}
+	if err := cs.guard.checkLockout(cs.guard.lockout, domain.AuthActionLoginVerify, phone, ip); err != nil {
+		return nil, err
+	}
+
+	if _, err := cs.verifyOTP(phone, domain.OTPPurposeLogin, code); err != nil {
+		if errors.Is(err, domain.ErrInvalidOTP) || errors.Is(err, domain.ErrOTPAttemptsExceeded) {
+			if gErr := cs.guard.verificationFailed(cs.guard.lockout, domain.AuthActionLoginVerify, phone, ip); gErr != nil {
+				return nil, gErr
+			}
+		}
+		return nil, err
+	}
+	if err := cs.guard.verificationSucceeded(cs.guard.lockout, phone); err != nil {
+		return nil, err
+	}
+
+	customer, err := cs.repo.FindByPhone(phone)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, domain.ErrInvalidOTP
+	}
+
+	return customer, nil
+}
+
+// sendOTP issues a fresh code for phone and texts it using format, which must contain a single %s.
+func (cs *customerService) sendOTP(phone string, purpose domain.OTPPurpose, userID *uuid.UUID, format string) error {
+	code, err := generateOTPCode(config.OTPLength)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	otp := domain.OTP{
+		ID:        uuid.New(),
+		Phone:     phone,
+		Purpose:   purpose,
+		UserID:    userID,
+		CodeHash:  hashOTPCode(phone, code),
+		Attempts:  0,
+		ExpiresAt: now.Add(config.OTPTTL),
+		CreatedAt: now,
+	}
+	if err := cs.otpRepo.Create(&otp); err != nil {
+		return err
+	}
+
+	return cs.sms.Send(phone, fmt.Sprintf(format, code))
+}
+
+// verifyOTP checks code against the latest code issued to phone for purpose and consumes it on success.
+func (cs *customerService) verifyOTP(phone string, purpose domain.OTPPurpose, code string) (*domain.OTP, error) {
+	otp, err := cs.otpRepo.FindActiveByPhone(phone, purpose)
+	if err != nil {
+		return nil, err
+	}
+	if otp == nil {
+		return nil, domain.ErrInvalidOTP
+	}
+	if otp.IsExpired(time.Now()) {
+		return nil, domain.ErrOTPExpired
+	}
+
+	// The attempt is counted before the code is compared, so parallel guesses cannot get past the limit.
+	if err := cs.otpRepo.IncrementAttempts(otp, config.OTPMaxAttempts); err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(otp.CodeHash), []byte(hashOTPCode(phone, code))) {
+		if otp.Attempts >= config.OTPMaxAttempts {
+			return nil, domain.ErrOTPAttemptsExceeded
+		}
+
+		return nil, domain.ErrInvalidOTP
+	}
+
+	if err := cs.otpRepo.MarkConsumed(otp); err != nil {
+		return nil, err
+	}
+
+	return otp, nil
+}
+
+// generateOTPCode returns a uniformly random numeric code of the given length.
+func generateOTPCode(length int) (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(length)), nil)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", length, n), nil
+}
+
+// hashOTPCode binds the code to the phone it was sent to, so a stolen hash is useless for other numbers.
+func hashOTPCode(phone, code string) string {
+	mac := hmac.New(sha256.New, config.OTPSecret)
+	mac.Write([]byte(phone + ":" + code))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
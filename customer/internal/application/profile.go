@@ -0,0 +1,127 @@
+package application
+
+import (
+	"errors"
+	"miniature/customer/internal/domain"
+	"miniature/pkg/phone"
+)
+
+func (cs *customerService) UpdateProfile(userID string, name *string) (*domain.Customer, error) {
+	customer, err := cs.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, domain.ErrCustomerNotFound
+	}
+
+	if name != nil {
+		customer.Name = *name
+	}
+
+	if err := cs.repo.Update(customer); err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// RequestPhoneChange texts a confirmation code to newPhone. The number is only
+// swapped once the code is confirmed, which proves the customer owns it.
+func (cs *customerService) RequestPhoneChange(userID, rawPhone, ip string) error {
+	newPhone, err := phone.Normalize(rawPhone)
+	if err != nil {
+		return err
+	}
+
+	action := domain.AuthActionPhoneChange
+	if err := cs.guard.allow(cs.guard.phoneChangeByUser, userID, action, newPhone, ip, "user_rate_limited"); err != nil {
+		return err
+	}
+	if err := cs.guard.checkLockout(cs.guard.phoneChangeLockout, action, newPhone, ip); err != nil {
+		return err
+	}
+	if err := cs.guard.allow(cs.guard.loginByPhone, newPhone, action, newPhone, ip, "phone_rate_limited"); err != nil {
+		return err
+	}
+
+	customer, err := cs.repo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return domain.ErrCustomerNotFound
+	}
+	if err := cs.ensurePhoneAvailable(newPhone); err != nil {
+		return err
+	}
+
+	return cs.sendOTP(newPhone, domain.OTPPurposePhoneChange, &customer.ID, "Your phone change code is %s")
+}
+
+// ConfirmPhoneChange swaps the phone number and ends every session of the customer,
+// since their tokens were issued for the old number.
+func (cs *customerService) ConfirmPhoneChange(userID, rawPhone, code, ip string) (*domain.Customer, error) {
+	newPhone, err := phone.Normalize(rawPhone)
+	if err != nil {
+		return nil, err
+	}
+
+	action := domain.AuthActionPhoneChangeVerify
+	if err := cs.guard.allow(cs.guard.verifyByIP, ip, action, newPhone, ip, "ip_rate_limited"); err != nil {
+		return nil, err
+	}
+	if err := cs.guard.checkLockout(cs.guard.phoneChangeLockout, action, newPhone, ip); err != nil {
+		return nil, err
+	}
+
+	otp, err := cs.verifyOTP(newPhone, domain.OTPPurposePhoneChange, code)
+	if err == nil && (otp.UserID == nil || otp.UserID.String() != userID) {
+		err = domain.ErrInvalidOTP
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidOTP) || errors.Is(err, domain.ErrOTPAttemptsExceeded) {
+			if gErr := cs.guard.verificationFailed(cs.guard.phoneChangeLockout, action, newPhone, ip); gErr != nil {
+				return nil, gErr
+			}
+		}
+		return nil, err
+	}
+	if err := cs.guard.verificationSucceeded(cs.guard.phoneChangeLockout, newPhone); err != nil {
+		return nil, err
+	}
+
+	customer, err := cs.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, domain.ErrCustomerNotFound
+	}
+	if err := cs.ensurePhoneAvailable(newPhone); err != nil {
+		return nil, err
+	}
+
+	customer.Phone = newPhone
+	if err := cs.repo.Update(customer); err != nil {
+		return nil, err
+	}
+
+	if err := cs.LogoutAll(userID); err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+func (cs *customerService) ensurePhoneAvailable(phone string) error {
+	existing, err := cs.repo.FindByPhone(phone)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return domain.ErrPhoneTaken
+	}
+
+	return nil
+}
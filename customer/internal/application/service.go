@@ -3,38 +3,97 @@ package application
 import (
 	"github.com/google/uuid"
 	"miniature/customer/internal/domain"
+	"miniature/pkg/authz"
+	"miniature/pkg/phone"
+	"miniature/pkg/token"
 	"time"
 )
 
 type customerService struct {
-	repo domain.CustomerRepository
+	repo        domain.CustomerRepository
+	otpRepo     domain.OTPRepository
+	refreshRepo domain.RefreshTokenRepository
+	signer      *token.Signer
+	revocations token.RevocationStore
+	sms         domain.SMSSender
+	guard       *AbuseGuard
 }
 
-func NewCustomerService(repo domain.CustomerRepository) *customerService {
-	return &customerService{repo: repo}
+func NewCustomerService(
+	repo domain.CustomerRepository,
+	otpRepo domain.OTPRepository,
+	refreshRepo domain.RefreshTokenRepository,
+	signer *token.Signer,
+	revocations token.RevocationStore,
+	sms domain.SMSSender,
+	guard *AbuseGuard,
+) *customerService {
+	return &customerService{
+		repo:        repo,
+		otpRepo:     otpRepo,
+		refreshRepo: refreshRepo,
+		signer:      signer,
+		revocations: revocations,
+		sms:         sms,
+		guard:       guard,
+	}
 }
 
-func (cs *customerService) RegisterCustomer(phone, name, role string) (*domain.Customer, error) {
-	customer := domain.Customer{
-		ID:              uuid.New(),
-		Phone:           phone,
-		Name:            name,
-		Role:            role,
-		TotalSpent:      0,
-		CashbackBalance: 0,
-		CreatedAt:       time.Time{},
+// RegisterCustomer creates the customer if phone is new and texts a login code either way.
+// Like RequestLoginOTP, the outcome does not reveal whether the phone was already registered.
+func (cs *customerService) RegisterCustomer(rawPhone, name, roleStr, ip string) error {
+	phone, err := phone.Normalize(rawPhone)
+	if err != nil {
+		return err
+	}
+
+	role, err := authz.ParseRole(roleStr)
+	if err != nil {
+		return err
+	}
+	if !role.CanSelfRegister() {
+		return authz.ErrInvalidRole
 	}
 
-	err := cs.repo.Create(&customer)
+	if err := cs.guard.allow(cs.guard.registerByIP, ip, domain.AuthActionRegister, phone, ip, "ip_rate_limited"); err != nil {
+		return err
+	}
+	if err := cs.guard.allow(cs.guard.loginByPhone, phone, domain.AuthActionRegister, phone, ip, "phone_rate_limited"); err != nil {
+		return err
+	}
 
-	return &customer, err
+	existing, err := cs.repo.FindByPhone(phone)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		customer := domain.Customer{
+			ID:              uuid.New(),
+			Phone:           phone,
+			Name:            name,
+			Role:            role,
+			TotalSpent:      0,
+			CashbackBalance: 0,
+			CreatedAt:       time.Now(),
+		}
+		if err := cs.repo.Create(&customer); err != nil {
+			return err
+		}
+	}
+
+	return cs.sendOTP(phone, domain.OTPPurposeLogin, nil, "Your login code is %s")
 }
 
 func (cs *customerService) GetCustomerByID(id string) (*domain.Customer, error) {
 	return cs.repo.FindByID(id)
 }
 
-func (cs *customerService) GetCustomerByPhone(phone string) (*domain.Customer, error) {
+func (cs *customerService) GetCustomerByPhone(rawPhone string) (*domain.Customer, error) {
+	phone, err := phone.Normalize(rawPhone)
+	if err != nil {
+		return nil, err
+	}
+
 	return cs.repo.FindByPhone(phone)
 }
 
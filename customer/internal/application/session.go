@@ -0,0 +1,126 @@
+package application
+
+import (
+	"miniature/customer/internal/domain"
+	"miniature/pkg/authz"
+	"miniature/pkg/token"
+	"miniature/pkg/token/config"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IssueTokens starts a new session for customer with a fresh refresh token family.
+func (cs *customerService) IssueTokens(customer *domain.Customer) (*domain.TokenPair, error) {
+	return cs.issueTokens(customer.ID, customer.Role, uuid.New(), nil)
+}
+
+func (cs *customerService) RefreshTokens(refreshToken string) (*domain.TokenPair, error) {
+	current, err := cs.refreshRepo.FindByHash(token.HashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	if current.RevokedAt != nil {
+		// A revoked token being presented again means it leaked: end the whole session.
+		if err := cs.refreshRepo.RevokeFamily(current.FamilyID.String()); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	if !current.IsActive(time.Now()) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	customer, err := cs.repo.FindByID(current.UserID.String())
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	return cs.issueTokens(customer.ID, customer.Role, current.FamilyID, current)
+}
+
+// Logout revokes the access token identified by claims and, if given, the refresh token of the same session.
+func (cs *customerService) Logout(claims *token.CustomClaims, refreshToken string) error {
+	if err := cs.revokeAccessToken(claims); err != nil {
+		return err
+	}
+	if refreshToken == "" {
+		return nil
+	}
+
+	return cs.refreshRepo.RevokeByHash(claims.UserID, token.HashRefreshToken(refreshToken))
+}
+
+// LogoutAll ends every session of userID on every device.
+func (cs *customerService) LogoutAll(userID string) error {
+	if err := cs.refreshRepo.RevokeAllForUser(userID); err != nil {
+		return err
+	}
+
+	return cs.revocations.RevokeAllForUser(userID, time.Now())
+}
+
+// RevokeToken revokes a single access token by its jti. Its exact expiry is unknown here,
+// so the entry is kept for the longest lifetime an access token can have.
+func (cs *customerService) RevokeToken(jti, userID string) error {
+	return cs.revocations.Revoke(jti, userID, time.Now().Add(config.AccessTokenTTL))
+}
+
+func (cs *customerService) revokeAccessToken(claims *token.CustomClaims) error {
+	expiresAt := time.Now().Add(config.AccessTokenTTL)
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	return cs.revocations.Revoke(claims.ID, claims.UserID, expiresAt)
+}
+
+func (cs *customerService) issueTokens(userID uuid.UUID, role authz.Role, familyID uuid.UUID, previous *domain.RefreshToken) (*domain.TokenPair, error) {
+	accessToken, err := cs.signer.GenerateToken(userID.String(), role.String())
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshHash, err := token.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	next := domain.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: refreshHash,
+		ExpiresAt: now.Add(config.RefreshTokenTTL),
+		CreatedAt: now,
+	}
+
+	if previous == nil {
+		err = cs.refreshRepo.Create(&next)
+	} else {
+		var rotated bool
+		rotated, err = cs.refreshRepo.Rotate(previous, &next)
+		if err == nil && !rotated {
+			err = cs.refreshRepo.RevokeFamily(familyID.String())
+			if err == nil {
+				err = domain.ErrInvalidRefreshToken
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(config.AccessTokenTTL.Seconds()),
+	}, nil
+}
@@ -0,0 +1,148 @@
+package application
+
+import (
+	"errors"
+	"miniature/customer/internal/domain"
+	"miniature/pkg/authz"
+	"miniature/pkg/token"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type oneCustomer struct {
+	domain.CustomerRepository
+	customer *domain.Customer
+}
+
+func (r oneCustomer) FindByID(id string) (*domain.Customer, error) {
+	if r.customer.ID.String() != id {
+		return nil, nil
+	}
+	return r.customer, nil
+}
+
+type memoryRefreshTokens struct {
+	tokens map[string]*domain.RefreshToken // by hash
+}
+
+func (r *memoryRefreshTokens) Create(t *domain.RefreshToken) error {
+	r.tokens[t.TokenHash] = t
+	return nil
+}
+
+func (r *memoryRefreshTokens) FindByHash(tokenHash string) (*domain.RefreshToken, error) {
+	if t, ok := r.tokens[tokenHash]; ok {
+		copied := *t
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryRefreshTokens) Rotate(old, next *domain.RefreshToken) (bool, error) {
+	stored := r.tokens[old.TokenHash]
+	if stored.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	stored.RevokedAt = &now
+	r.tokens[next.TokenHash] = next
+	return true, nil
+}
+
+func (r *memoryRefreshTokens) RevokeByHash(_, tokenHash string) error {
+	return r.revoke(func(t *domain.RefreshToken) bool { return t.TokenHash == tokenHash })
+}
+
+func (r *memoryRefreshTokens) RevokeFamily(familyID string) error {
+	return r.revoke(func(t *domain.RefreshToken) bool { return t.FamilyID.String() == familyID })
+}
+
+func (r *memoryRefreshTokens) RevokeAllForUser(userID string) error {
+	return r.revoke(func(t *domain.RefreshToken) bool { return t.UserID.String() == userID })
+}
+
+func (r *memoryRefreshTokens) revoke(match func(*domain.RefreshToken) bool) error {
+	now := time.Now()
+	for _, t := range r.tokens {
+		if match(t) && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func newSessionTest(t *testing.T) (*customerService, *domain.Customer) {
+	t.Helper()
+	key, err := token.GenerateSigningKey("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := token.NewSigner([]*token.SigningKey{key}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	customer := &domain.Customer{ID: uuid.New(), Phone: "+989121234567", Role: authz.RoleCustomer}
+	refreshRepo := &memoryRefreshTokens{tokens: map[string]*domain.RefreshToken{}}
+	return NewCustomerService(oneCustomer{customer: customer}, nil, refreshRepo, signer, nil, nil, nil), customer
+}
+
+func TestRefreshTokenReplayEndsTheSession(t *testing.T) {
+	cs, customer := newSessionTest(t)
+
+	first, err := cs.IssueTokens(customer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := cs.IssueTokens(customer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := cs.RefreshTokens(first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The attacker replays the token the customer already exchanged.
+	if _, err := cs.RefreshTokens(first.RefreshToken); !errors.Is(err, domain.ErrInvalidRefreshToken) {
+		t.Fatalf("replayed refresh token = %v, want ErrInvalidRefreshToken", err)
+	}
+	if _, err := cs.RefreshTokens(second.RefreshToken); !errors.Is(err, domain.ErrInvalidRefreshToken) {
+		t.Errorf("refresh token of the replayed session = %v, want it revoked with its family", err)
+	}
+	if _, err := cs.RefreshTokens(other.RefreshToken); err != nil {
+		t.Errorf("refresh token of another session = %v, want it left alone", err)
+	}
+}
+
+func TestRefreshTokens(t *testing.T) {
+	cs, customer := newSessionTest(t)
+	pair, err := cs.IssueTokens(customer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := cs.IssueTokens(customer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.refreshRepo.(*memoryRefreshTokens).tokens[token.HashRefreshToken(expired.RefreshToken)].ExpiresAt = time.Now()
+
+	tests := []struct {
+		name         string
+		refreshToken string
+		want         error
+	}{
+		{"unknown", "not-a-refresh-token", domain.ErrInvalidRefreshToken},
+		{"expired", expired.RefreshToken, domain.ErrInvalidRefreshToken},
+		{"active", pair.RefreshToken, nil},
+		{"used", pair.RefreshToken, domain.ErrInvalidRefreshToken},
+	}
+	for _, tt := range tests {
+		next, err := cs.RefreshTokens(tt.refreshToken)
+		if tt.want == nil && (err != nil || next.AccessToken == "" || next.RefreshToken == tt.refreshToken) ||
+			tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: RefreshTokens = %+v, %v, want %v", tt.name, next, err, tt.want)
+		}
+	}
+}
@@ -1,10 +1,23 @@
 package application
 
-import "miniature/customer/internal/domain"
+import (
+	"miniature/customer/internal/domain"
+	"miniature/pkg/token"
+)
 
 type CustomerUsecase interface {
-	RegisterCustomer(phone, name, role string) (*domain.Customer, error)
+	RegisterCustomer(phone, name, role, ip string) error
 	GetCustomerByID(id string) (*domain.Customer, error)
 	GetCustomerByPhone(phone string) (*domain.Customer, error)
 	UpdateCustomer(*domain.Customer) error
+	RequestLoginOTP(phone, ip string) error
+	VerifyLoginOTP(phone, code, ip string) (*domain.Customer, error)
+	IssueTokens(customer *domain.Customer) (*domain.TokenPair, error)
+	RefreshTokens(refreshToken string) (*domain.TokenPair, error)
+	Logout(claims *token.CustomClaims, refreshToken string) error
+	LogoutAll(userID string) error
+	RevokeToken(jti, userID string) error
+	UpdateProfile(userID string, name *string) (*domain.Customer, error)
+	RequestPhoneChange(userID, newPhone, ip string) error
+	ConfirmPhoneChange(userID, newPhone, code, ip string) (*domain.Customer, error)
 }
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"time"
+)
+
+var (
+	OTPSecret      = []byte("your_otp_secret_here") // HMAC key used to hash login codes
+	OTPLength      = 6
+	OTPTTL         = time.Minute * 2 // 2 minutes expiration
+	OTPMaxAttempts = 5
+)
+
+// Abuse protection for /register, /login, /login/verify and phone changes.
+var (
+	RateLimitStore = "memory" // "memory" for a single instance, "postgres" to share limits between instances
+
+	RegisterIPLimit      = 10 // registrations per IP per RateLimitWindow
+	LoginIPLimit         = 20 // code requests per IP per RateLimitWindow
+	LoginPhoneLimit      = 5  // code requests per phone per RateLimitWindow
+	VerifyIPLimit        = 30 // code checks per IP per RateLimitWindow
+	PhoneChangeUserLimit = 5  // phone change codes per customer per RateLimitWindow
+	RateLimitWindow      = time.Minute * 10
+	LockoutThreshold     = 5 // failed code checks per phone before it is locked
+	LockoutWindow        = time.Hour * 24
+	LockoutDurations     = []time.Duration{time.Minute, time.Minute * 5, time.Minute * 15, time.Hour}
+)
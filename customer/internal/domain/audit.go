@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+// AuthEvent is an entry of the authentication audit log.
+type AuthEvent struct {
+	ID        uuid.UUID
+	Action    string // REGISTER, LOGIN, LOGIN_VERIFY, PHONE_CHANGE, PHONE_CHANGE_VERIFY
+	Phone     string
+	IP        string
+	Reason    string // why the attempt was blocked, e.g. ip_rate_limited
+	CreatedAt time.Time
+}
+
+const (
+	AuthActionRegister    = "REGISTER"
+	AuthActionLogin       = "LOGIN"
+	AuthActionLoginVerify = "LOGIN_VERIFY"
+
+	AuthActionPhoneChange       = "PHONE_CHANGE"
+	AuthActionPhoneChangeVerify = "PHONE_CHANGE_VERIFY"
+)
+
+// RateLimitError is returned when a request is refused by a rate limit or lockout.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("too many requests, retry in %d seconds", int(e.RetryAfter.Seconds())+1)
+}
@@ -2,24 +2,17 @@ package domain
 
 import (
 	"github.com/google/uuid"
+	"miniature/pkg/authz"
 	"time"
 )
 
-//type Role string
-
-//const (
-//	RoleCustomer Role = "CUSTOMER"
-//	RoleSeller   Role = "SELLER"
-//	RoleAdmin    Role = "ADMIN"
-//)
-
 type Customer struct {
-	ID              uuid.UUID `json:"id"`
-	Phone           string    `json:"phone"`
-	Name            string    `json:"name"`
-	Role            string    `json:"role"`
-	TotalSpent      float64   `json:"total_spent"`
-	CashbackBalance float64   `json:"cashback_balance"`
-	IsActive        bool      `json:"is_active"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID              uuid.UUID  `json:"id"`
+	Phone           string     `json:"phone"`
+	Name            string     `json:"name"`
+	Role            authz.Role `json:"role"`
+	TotalSpent      float64    `json:"total_spent"`
+	CashbackBalance float64    `json:"cashback_balance"`
+	IsActive        bool       `json:"is_active"`
+	CreatedAt       time.Time  `json:"created_at"`
 }
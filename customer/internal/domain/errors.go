@@ -0,0 +1,12 @@
+package domain
+
+import "errors"
+
+var (
+	ErrCustomerNotFound    = errors.New("user not found")
+	ErrInvalidOTP          = errors.New("invalid verification code")
+	ErrOTPExpired          = errors.New("verification code expired")
+	ErrOTPAttemptsExceeded = errors.New("too many attempts, request a new code")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrPhoneTaken          = errors.New("phone number is already registered")
+)
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// OTPPurpose tells what a one-time code was issued for, so a login code
+// cannot be used to confirm a phone change and vice versa.
+type OTPPurpose string
+
+const (
+	OTPPurposeLogin       OTPPurpose = "LOGIN"
+	OTPPurposePhoneChange OTPPurpose = "PHONE_CHANGE"
+)
+
+// OTP is a one-time code sent to a phone number during login or a phone change.
+// Only the hash of the code is ever stored.
+type OTP struct {
+	ID         uuid.UUID
+	Phone      string
+	Purpose    OTPPurpose
+	UserID     *uuid.UUID // customer who asked for a phone change; nil for login codes
+	CodeHash   string
+	Attempts   int
+	ExpiresAt  time.Time
+	ConsumedAt *time.Time
+	CreatedAt  time.Time
+}
+
+func (o *OTP) IsExpired(now time.Time) bool {
+	return now.After(o.ExpiresAt)
+}
+
+// SMSSender delivers text messages to a phone number.
+type SMSSender interface {
+	Send(phone, message string) error
+}
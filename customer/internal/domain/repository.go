@@ -6,3 +6,28 @@ type CustomerRepository interface {
 	FindByPhone(phone string) (*Customer, error)
 	Update(customer *Customer) error
 }
+
+type OTPRepository interface {
+	Create(otp *OTP) error
+	// FindActiveByPhone returns the most recent unconsumed code for phone and purpose, or nil if there is none.
+	FindActiveByPhone(phone string, purpose OTPPurpose) (*OTP, error)
+	// IncrementAttempts counts a check of the code unless it already had max checks, in which case it
+	// returns ErrOTPAttemptsExceeded.
+	IncrementAttempts(otp *OTP, max int) error
+	// MarkConsumed uses the code up. It returns ErrInvalidOTP if it was used up already.
+	MarkConsumed(otp *OTP) error
+}
+
+type RefreshTokenRepository interface {
+	Create(token *RefreshToken) error
+	FindByHash(tokenHash string) (*RefreshToken, error)
+	// Rotate revokes old and stores next atomically. It returns false if old was already revoked.
+	Rotate(old, next *RefreshToken) (bool, error)
+	RevokeByHash(userID, tokenHash string) error
+	RevokeFamily(familyID string) error
+	RevokeAllForUser(userID string) error
+}
+
+type AuditRepository interface {
+	Record(event *AuthEvent) error
+}
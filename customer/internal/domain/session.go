@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// RefreshToken is a long-lived, single-use credential exchanged for a new token pair.
+// Tokens descending from the same login share a FamilyID so that a replayed token
+// can revoke the whole chain.
+type RefreshToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	FamilyID  uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"database/sql"
+	"miniature/customer/internal/domain"
+)
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *auditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Record(event *domain.AuthEvent) error {
+	query := `
+		INSERT INTO auth_audit_log (id, action, phone, ip, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.Exec(query,
+		event.ID,
+		event.Action,
+		event.Phone,
+		event.IP,
+		event.Reason,
+		event.CreatedAt,
+	)
+
+	return err
+}
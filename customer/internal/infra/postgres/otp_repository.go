@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"miniature/customer/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type otpRepository struct {
+	db *sql.DB
+}
+
+func NewOTPRepository(db *sql.DB) *otpRepository {
+	return &otpRepository{db: db}
+}
+
+// Create stores a new code and invalidates any code previously issued to the same phone for the same purpose.
+func (r *otpRepository) Create(otp *domain.OTP) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`UPDATE customer_otps SET consumed_at = $1 WHERE phone = $2 AND purpose = $3 AND consumed_at IS NULL`,
+		otp.CreatedAt, otp.Phone, otp.Purpose)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO customer_otps (id, phone, purpose, user_id, code_hash, attempts, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err = tx.Exec(query,
+		otp.ID,
+		otp.Phone,
+		otp.Purpose,
+		otp.UserID,
+		otp.CodeHash,
+		otp.Attempts,
+		otp.ExpiresAt,
+		otp.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *otpRepository) FindActiveByPhone(phone string, purpose domain.OTPPurpose) (*domain.OTP, error) {
+	query := `
+		SELECT id, phone, purpose, user_id, code_hash, attempts, expires_at, consumed_at, created_at
+		FROM customer_otps
+		WHERE phone = $1 AND purpose = $2 AND consumed_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	row := r.db.QueryRow(query, phone, purpose)
+
+	var otp domain.OTP
+	var userID uuid.NullUUID
+	var consumedAt sql.NullTime
+	if err := row.Scan(
+		&otp.ID,
+		&otp.Phone,
+		&otp.Purpose,
+		&userID,
+		&otp.CodeHash,
+		&otp.Attempts,
+		&otp.ExpiresAt,
+		&consumedAt,
+		&otp.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if userID.Valid {
+		otp.UserID = &userID.UUID
+	}
+	if consumedAt.Valid {
+		otp.ConsumedAt = &consumedAt.Time
+	}
+
+	return &otp, nil
+}
+
+func (r *otpRepository) IncrementAttempts(otp *domain.OTP, max int) error {
+	query := `UPDATE customer_otps SET attempts = attempts + 1 WHERE id = $1 AND attempts < $2 RETURNING attempts`
+
+	err := r.db.QueryRow(query, otp.ID, max).Scan(&otp.Attempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrOTPAttemptsExceeded
+	}
+	return err
+}
+
+func (r *otpRepository) MarkConsumed(otp *domain.OTP) error {
+	now := time.Now()
+	result, err := r.db.Exec(`UPDATE customer_otps SET consumed_at = $1 WHERE id = $2 AND consumed_at IS NULL`, now, otp.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	// Another request used the code between reading and consuming it
+	if rowsAffected == 0 {
+		return domain.ErrInvalidOTP
+	}
+	otp.ConsumedAt = &now
+
+	return nil
+}
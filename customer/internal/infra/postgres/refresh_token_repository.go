@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"miniature/customer/internal/domain"
+)
+
+type refreshTokenRepository struct {
+	db *sql.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) *refreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Create(t *domain.RefreshToken) error {
+	return insertRefreshToken(r.db, t)
+}
+
+func (r *refreshTokenRepository) FindByHash(tokenHash string) (*domain.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, family_id, token_hash, expires_at, revoked_at, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+	row := r.db.QueryRow(query, tokenHash)
+
+	var t domain.RefreshToken
+	var revokedAt sql.NullTime
+	if err := row.Scan(
+		&t.ID,
+		&t.UserID,
+		&t.FamilyID,
+		&t.TokenHash,
+		&t.ExpiresAt,
+		&revokedAt,
+		&t.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if revokedAt.Valid {
+		t.RevokedAt = &revokedAt.Time
+	}
+
+	return &t, nil
+}
+
+func (r *refreshTokenRepository) Rotate(old, next *domain.RefreshToken) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE refresh_tokens SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, old.ID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected == 0 {
+		// Someone else already used this token.
+		return false, nil
+	}
+
+	if err := insertRefreshToken(tx, next); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *refreshTokenRepository) RevokeByHash(userID, tokenHash string) error {
+	_, err := r.db.Exec(`UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND token_hash = $2 AND revoked_at IS NULL`,
+		userID, tokenHash)
+
+	return err
+}
+
+func (r *refreshTokenRepository) RevokeFamily(familyID string) error {
+	_, err := r.db.Exec(`UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`, familyID)
+
+	return err
+}
+
+func (r *refreshTokenRepository) RevokeAllForUser(userID string) error {
+	_, err := r.db.Exec(`UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`, userID)
+
+	return err
+}
+
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func insertRefreshToken(db execer, t *domain.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := db.Exec(query,
+		t.ID,
+		t.UserID,
+		t.FamilyID,
+		t.TokenHash,
+		t.ExpiresAt,
+		t.CreatedAt,
+	)
+
+	return err
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"database/sql"
 	"errors"
+	"github.com/lib/pq"
 	"miniature/customer/internal/domain"
 )
 
@@ -87,7 +88,7 @@ func (r *customerRepository) FindByPhone(phone string) (*domain.Customer, error)
 func (r *customerRepository) Update(customer *domain.Customer) error {
 
 	query := `
-		UPDATE customers
+		UPDATE customer
 		SET name = $1, phone = $2, role = $3, total_spent = $4, cashback_balance = $5
 		WHERE id = $6
 	`
@@ -99,6 +100,10 @@ func (r *customerRepository) Update(customer *domain.Customer) error {
 		customer.CashbackBalance,
 		customer.ID,
 	)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation on phone
+		return domain.ErrPhoneTaken
+	}
 
 	return err
 }
@@ -0,0 +1,19 @@
+package sms
+
+import (
+	"log"
+)
+
+// LogSender writes messages to the application log instead of delivering them.
+// It is meant for local development and tests.
+type LogSender struct{}
+
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+func (s *LogSender) Send(phone, message string) error {
+	log.Printf("[sms] to=%s message=%q", phone, message)
+
+	return nil
+}
@@ -1,13 +1,14 @@
 package interfaces
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"miniature/pkg/token"
 	"net/http"
 	"strings"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+func AuthMiddleware(auth *token.Authenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
@@ -17,9 +18,14 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-		claims, err := token.ValidateToken(tokenStr)
+		claims, err := auth.Authenticate(tokenStr)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			if errors.Is(err, token.ErrInvalidToken) || errors.Is(err, token.ErrTokenRevoked) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "could not verify token"})
 
 			return
 		}
@@ -27,6 +33,21 @@ func AuthMiddleware() gin.HandlerFunc {
 		// store user info in context
 		c.Set("user_id", claims.UserID)
 		c.Set("role", claims.Role)
+		c.Set("claims", claims)
 		c.Next()
 	}
 }
+
+func JWKSHandler(signer *token.Signer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		jwks, err := signer.JWKS()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not build key set"})
+
+			return
+		}
+
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, jwks)
+	}
+}
@@ -1,5 +1,10 @@
 package interfaces
 
+import (
+	"miniature/customer/internal/domain"
+	"miniature/pkg/jalali"
+)
+
 type CreateCustomerRequest struct {
 	Name  string `json:"name" binding:"required"`
 	Phone string `json:"phone" binding:"required"`
@@ -9,3 +14,49 @@ type CreateCustomerRequest struct {
 type LoginRequest struct {
 	Phone string `json:"phone" binding:"required"`
 }
+
+type VerifyLoginRequest struct {
+	Phone string `json:"phone" binding:"required"`
+	Code  string `json:"code" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RevokeRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	JTI    string `json:"jti"` // revoke a single access token instead of every session of the user
+}
+
+type UpdateProfileRequest struct {
+	Name *string `json:"name" binding:"omitempty,min=1"`
+}
+
+type ChangePhoneRequest struct {
+	Phone string `json:"phone" binding:"required"`
+}
+
+type ConfirmPhoneChangeRequest struct {
+	Phone string `json:"phone" binding:"required"`
+	Code  string `json:"code" binding:"required"`
+}
+
+// CustomerResponse is a customer as returned to them. CreatedAtJalali is only set for clients that
+// ask for ?calendar=jalali.
+type CustomerResponse struct {
+	*domain.Customer
+	CreatedAtJalali string `json:"created_at_jalali,omitempty"`
+}
+
+func newCustomerResponse(customer *domain.Customer, calendar jalali.Calendar) *CustomerResponse {
+	response := &CustomerResponse{Customer: customer}
+	if calendar == jalali.Jalali {
+		response.CreatedAtJalali = jalali.Format(customer.CreatedAt)
+	}
+	return response
+}
@@ -1,10 +1,17 @@
 package interfaces
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
+	"io"
 	"miniature/customer/internal/application"
+	"miniature/customer/internal/config"
+	"miniature/customer/internal/domain"
+	"miniature/pkg/authz"
+	"miniature/pkg/phone"
 	"miniature/pkg/token"
 	"net/http"
+	"strconv"
 )
 
 type CustomerHandler struct {
@@ -24,24 +31,23 @@ func (h *CustomerHandler) Register(c *gin.Context) {
 		return
 	}
 
-	customer, cErr := h.usecase.RegisterCustomer(req.Phone, req.Name, req.Role)
+	cErr := h.usecase.RegisterCustomer(req.Phone, req.Name, req.Role, c.ClientIP())
 	if cErr != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": cErr.Error()})
+		if errors.Is(cErr, authz.ErrInvalidRole) || isPhoneError(cErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": cErr.Error()})
 
-		return
-	}
-
-	token, err := token.GenerateToken(customer.ID.String(), customer.Role)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not generate token"})
+			return
+		}
+		if respondRateLimited(c, cErr) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not register"})
 
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"user":  customer,
-		"token": token,
-	})
+	// Same response for new and existing phones; the account is usable after /login/verify.
+	c.JSON(http.StatusAccepted, codeSentResponse())
 }
 
 func (h *CustomerHandler) Login(c *gin.Context) {
@@ -51,27 +57,138 @@ func (h *CustomerHandler) Login(c *gin.Context) {
 		return
 	}
 
-	customer, err := h.usecase.GetCustomerByPhone(req.Phone)
-	if err != nil || customer == nil {
-		c.JSON(http.StatusNoContent, gin.H{"error": "user not found"})
+	if err := h.usecase.RequestLoginOTP(req.Phone, c.ClientIP()); err != nil {
+		if isPhoneError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if respondRateLimited(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not send verification code"})
+		return
+	}
+
+	// Same response whether or not the phone is registered.
+	c.JSON(http.StatusOK, codeSentResponse())
+}
 
+func (h *CustomerHandler) VerifyLogin(c *gin.Context) {
+	var req VerifyLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
 		return
 	}
 
-	token, err := token.GenerateToken(customer.ID.String(), customer.Role)
+	customer, err := h.usecase.VerifyLoginOTP(req.Phone, req.Code, c.ClientIP())
+	if err != nil {
+		if respondRateLimited(c, err) {
+			return
+		}
+		switch {
+		case isPhoneError(err):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrInvalidOTP), errors.Is(err, domain.ErrOTPExpired):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrOTPAttemptsExceeded):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not verify code"})
+		}
+		return
+	}
+
+	tokens, err := h.usecase.IssueTokens(customer)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not generate token"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *CustomerHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	tokens, err := h.usecase.RefreshTokens(req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRefreshToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not refresh token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
 }
 
 func (h *CustomerHandler) Logout(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "logout successful. Just discard the token on client side."})
+	var req LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	claims, ok := claimsFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.usecase.Logout(claims, req.RefreshToken); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not logout"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logout successful"})
+}
+
+func (h *CustomerHandler) LogoutAll(c *gin.Context) {
+	claims, ok := claimsFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.usecase.LogoutAll(claims.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not logout"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logged out of all devices"})
+}
+
+func (h *CustomerHandler) RevokeTokens(c *gin.Context) {
+	var req RevokeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	var err error
+	if req.JTI != "" {
+		err = h.usecase.RevokeToken(req.JTI, req.UserID)
+	} else {
+		err = h.usecase.LogoutAll(req.UserID)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not revoke tokens"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "tokens revoked"})
 }
 
 func (h *CustomerHandler) Me(c *gin.Context) {
+	calendar, ok := calendarFromQuery(c)
+	if !ok {
+		return
+	}
 	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
@@ -79,10 +196,137 @@ func (h *CustomerHandler) Me(c *gin.Context) {
 	}
 
 	customer, err := h.usecase.GetCustomerByID(userID.(string))
-	if err != nil {
+	if err != nil || customer == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, customer)
+	c.JSON(http.StatusOK, newCustomerResponse(customer, calendar))
+}
+
+func (h *CustomerHandler) UpdateMe(c *gin.Context) {
+	calendar, ok := calendarFromQuery(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	customer, err := h.usecase.UpdateProfile(userID.(string), req.Name)
+	if err != nil {
+		if errors.Is(err, domain.ErrCustomerNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newCustomerResponse(customer, calendar))
+}
+
+func (h *CustomerHandler) ChangePhone(c *gin.Context) {
+	var req ChangePhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.usecase.RequestPhoneChange(userID.(string), req.Phone, c.ClientIP()); err != nil {
+		if respondRateLimited(c, err) {
+			return
+		}
+		switch {
+		case isPhoneError(err):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrPhoneTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrCustomerNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not send verification code"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "verification code sent to the new phone number",
+		"expires_in": int(config.OTPTTL.Seconds()),
+	})
+}
+
+func (h *CustomerHandler) ConfirmPhoneChange(c *gin.Context) {
+	var req ConfirmPhoneChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	customer, err := h.usecase.ConfirmPhoneChange(userID.(string), req.Phone, req.Code, c.ClientIP())
+	if err != nil {
+		if respondRateLimited(c, err) {
+			return
+		}
+		switch {
+		case isPhoneError(err):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrInvalidOTP), errors.Is(err, domain.ErrOTPExpired):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrOTPAttemptsExceeded):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrPhoneTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrCustomerNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not change phone"})
+		}
+		return
+	}
+
+	// Every token issued for the old number is now revoked, including the one used for this request.
+	c.JSON(http.StatusOK, gin.H{
+		"user":    customer,
+		"message": "phone changed, please log in again",
+	})
+}
+
+func codeSentResponse() gin.H {
+	return gin.H{
+		"message":    "if the phone number is registered, a verification code has been sent",
+		"expires_in": int(config.OTPTTL.Seconds()),
+	}
+}
+
+// respondRateLimited writes a 429 with Retry-After if err is a rate limit or lockout error.
+func respondRateLimited(c *gin.Context, err error) bool {
+	var rateLimitErr *domain.RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		return false
+	}
+
+	c.Header("Retry-After", strconv.Itoa(int(rateLimitErr.RetryAfter.Seconds())+1))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": rateLimitErr.Error()})
+	return true
+}
+
+func isPhoneError(err error) bool {
+	return errors.Is(err, phone.ErrInvalid) || errors.Is(err, phone.ErrNotMobile)
+}
+
+func claimsFromContext(c *gin.Context) (*token.CustomClaims, bool) {
+	raw, exists := c.Get("claims")
+	if !exists {
+		return nil, false
+	}
+	claims, ok := raw.(*token.CustomClaims)
+
+	return claims, ok
 }
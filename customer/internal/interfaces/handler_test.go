@@ -0,0 +1,38 @@
+package interfaces
+
+import (
+	"miniature/customer/internal/application"
+	"miniature/customer/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// deletedCustomerUsecase behaves as if the signed-in customer was deleted after their token was issued.
+type deletedCustomerUsecase struct {
+	application.CustomerUsecase
+}
+
+func (deletedCustomerUsecase) GetCustomerByID(string) (*domain.Customer, error) {
+	return nil, nil
+}
+
+func TestMeOfDeletedCustomer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCustomerHandler(deletedCustomerUsecase{})
+	router := gin.New()
+	router.GET("/me", func(c *gin.Context) {
+		c.Set("user_id", "0b6f1c9e-3f4f-4c55-9a55-1d7f0b7f6b10")
+		h.Me(c)
+	})
+
+	for _, target := range []string{"/me", "/me?calendar=jalali"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("GET %s = %d %s, want 404", target, w.Code, w.Body)
+		}
+	}
+}
@@ -0,0 +1,437 @@
+package interfaces
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"miniature/customer/internal/application"
+	"miniature/customer/internal/config"
+	"miniature/customer/internal/domain"
+	"miniature/customer/internal/infra/sms"
+	"miniature/pkg/ratelimit"
+	"miniature/pkg/token"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memoryCustomers, memoryOTPs and memoryRefreshTokens stand in for the postgres repositories.
+// memoryRefreshTokens only accepts new sessions; the other methods are not reached here.
+type memoryCustomers struct {
+	mu        sync.Mutex
+	customers []*domain.Customer
+}
+
+func (r *memoryCustomers) Create(customer *domain.Customer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *customer
+	r.customers = append(r.customers, &copied)
+	return nil
+}
+
+func (r *memoryCustomers) FindByID(id string) (*domain.Customer, error) {
+	return r.find(func(c *domain.Customer) bool { return c.ID.String() == id })
+}
+
+func (r *memoryCustomers) FindByPhone(phone string) (*domain.Customer, error) {
+	return r.find(func(c *domain.Customer) bool { return c.Phone == phone })
+}
+
+func (r *memoryCustomers) Update(customer *domain.Customer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, c := range r.customers {
+		if c.ID == customer.ID {
+			copied := *customer
+			r.customers[i] = &copied
+		}
+	}
+	return nil
+}
+
+func (r *memoryCustomers) find(match func(*domain.Customer) bool) (*domain.Customer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.customers {
+		if match(c) {
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+type memoryOTPs struct {
+	mu   sync.Mutex
+	otps []*domain.OTP
+}
+
+func (r *memoryOTPs) Create(otp *domain.OTP) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *otp
+	r.otps = append(r.otps, &copied)
+	return nil
+}
+
+func (r *memoryOTPs) FindActiveByPhone(phone string, purpose domain.OTPPurpose) (*domain.OTP, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.otps) - 1; i >= 0; i-- {
+		if otp := r.otps[i]; otp.Phone == phone && otp.Purpose == purpose && otp.ConsumedAt == nil {
+			copied := *otp
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+// IncrementAttempts and MarkConsumed check and change a code under one lock, as the conditional
+// updates of the postgres repository do.
+func (r *memoryOTPs) IncrementAttempts(otp *domain.OTP, max int) error {
+	return r.update(otp.ID.String(), func(stored *domain.OTP) error {
+		if stored.Attempts >= max {
+			return domain.ErrOTPAttemptsExceeded
+		}
+		stored.Attempts++
+		otp.Attempts = stored.Attempts
+		return nil
+	})
+}
+
+func (r *memoryOTPs) MarkConsumed(otp *domain.OTP) error {
+	return r.update(otp.ID.String(), func(stored *domain.OTP) error {
+		if stored.ConsumedAt != nil {
+			return domain.ErrInvalidOTP
+		}
+		now := time.Now()
+		stored.ConsumedAt = &now
+		return nil
+	})
+}
+
+func (r *memoryOTPs) update(id string, fn func(*domain.OTP) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, otp := range r.otps {
+		if otp.ID.String() == id {
+			return fn(otp)
+		}
+	}
+	return nil
+}
+
+type memoryRefreshTokens struct {
+	domain.RefreshTokenRepository
+}
+
+func (memoryRefreshTokens) Create(*domain.RefreshToken) error {
+	return nil
+}
+
+type noRevocations struct{}
+
+func (noRevocations) Revoke(string, string, time.Time) error      { return nil }
+func (noRevocations) RevokeAllForUser(string, time.Time) error    { return nil }
+func (noRevocations) IsRevoked(*token.CustomClaims) (bool, error) { return false, nil }
+
+type discardAudit struct{}
+
+func (discardAudit) Record(*domain.AuthEvent) error { return nil }
+
+// otpServer runs the customer routes on in-memory repositories. Codes go through sms.LogSender,
+// whose output the test reads them back from.
+type otpServer struct {
+	t      *testing.T
+	server *httptest.Server
+	smsLog *lockedBuffer
+}
+
+// lockedBuffer is written by the server's goroutines and read by the test.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func newOTPServer(t *testing.T) *otpServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	key, err := token.GenerateSigningKey("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := token.NewSigner([]*token.SigningKey{key}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	guard := application.NewAbuseGuard(ratelimit.NewMemoryStore(), discardAudit{})
+	usecase := application.NewCustomerService(
+		&memoryCustomers{}, &memoryOTPs{}, memoryRefreshTokens{}, signer, noRevocations{}, sms.NewLogSender(), guard,
+	)
+	router := NewRouter(*NewCustomerHandler(usecase), token.NewAuthenticator(signer, noRevocations{}), signer)
+
+	smsLog := &lockedBuffer{}
+	previous := log.Writer()
+	log.SetOutput(smsLog)
+	server := httptest.NewServer(router)
+	t.Cleanup(func() {
+		server.Close()
+		log.SetOutput(previous)
+	})
+	return &otpServer{t: t, server: server, smsLog: smsLog}
+}
+
+func (s *otpServer) do(method, path, bearer string, body any) (int, map[string]any) {
+	s.t.Helper()
+	var raw []byte
+	if body != nil {
+		var err error
+		if raw, err = json.Marshal(body); err != nil {
+			s.t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, s.server.URL+path, bytes.NewReader(raw))
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	resp, err := s.server.Client().Do(req)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var decoded map[string]any
+	json.NewDecoder(resp.Body).Decode(&decoded)
+	return resp.StatusCode, decoded
+}
+
+var codeSent = regexp.MustCompile(`\[sms\] to=(\+98\d{10}) message="Your (?:login|phone change) code is (\d+)"`)
+
+// lastCode returns the last code texted to phone, or "" if none was.
+func (s *otpServer) lastCode(phone string) string {
+	code := ""
+	for _, m := range codeSent.FindAllStringSubmatch(s.smsLog.String(), -1) {
+		if m[1] == phone {
+			code = m[2]
+		}
+	}
+	return code
+}
+
+func TestOTPLoginFlow(t *testing.T) {
+	s := newOTPServer(t)
+
+	status, _ := s.do(http.MethodPost, "/v1/customer/register", "", gin.H{"phone": "۰۹۱۲ ۱۲۳ ۴۵۶۷", "name": "Sara", "role": "CUSTOMER"})
+	if status != http.StatusAccepted {
+		t.Fatalf("register = %d, want 202", status)
+	}
+	code := s.lastCode("+989121234567")
+	if len(code) != 6 {
+		t.Fatalf("no login code was texted to the normalized number:\n%s", s.smsLog)
+	}
+
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	status, body := s.do(http.MethodPost, "/v1/customer/login/verify", "", gin.H{"phone": "09121234567", "code": wrong})
+	if status != http.StatusUnauthorized {
+		t.Fatalf("verify with a wrong code = %d %v, want 401", status, body)
+	}
+
+	status, body = s.do(http.MethodPost, "/v1/customer/login/verify", "", gin.H{"phone": "09121234567", "code": code})
+	if status != http.StatusOK {
+		t.Fatalf("verify = %d %v, want 200", status, body)
+	}
+	accessToken, _ := body["token"].(string)
+	refreshToken, _ := body["refresh_token"].(string)
+	if accessToken == "" || refreshToken == "" {
+		t.Fatalf("verify did not return a token pair: %v", body)
+	}
+
+	status, body = s.do(http.MethodPost, "/v1/customer/login/verify", "", gin.H{"phone": "09121234567", "code": code})
+	if status != http.StatusUnauthorized {
+		t.Errorf("reusing a code = %d %v, want 401", status, body)
+	}
+
+	status, body = s.do(http.MethodGet, "/v1/customer/me", accessToken, nil)
+	if status != http.StatusOK || body["phone"] != "+989121234567" || body["name"] != "Sara" {
+		t.Errorf("me = %d %v, want Sara at +989121234567", status, body)
+	}
+
+	status, _ = s.do(http.MethodPost, "/v1/customer/login", "", gin.H{"phone": "+98 912 123 4567"})
+	if status != http.StatusOK {
+		t.Fatalf("login = %d, want 200", status)
+	}
+	if next := s.lastCode("+989121234567"); next == "" || strings.Count(s.smsLog.String(), "to=+989121234567") != 2 {
+		t.Errorf("login did not text a second code:\n%s", s.smsLog)
+	}
+}
+
+func TestOTPLoginOfUnknownPhone(t *testing.T) {
+	s := newOTPServer(t)
+
+	status, body := s.do(http.MethodPost, "/v1/customer/login", "", gin.H{"phone": "09351234567"})
+	if status != http.StatusOK {
+		t.Fatalf("login = %d %v, want the same 200 as for a registered phone", status, body)
+	}
+	if code := s.lastCode("+989351234567"); code != "" {
+		t.Errorf("a code was texted to an unregistered phone")
+	}
+
+	status, _ = s.do(http.MethodPost, "/v1/customer/login/verify", "", gin.H{"phone": "09351234567", "code": "123456"})
+	if status != http.StatusUnauthorized {
+		t.Errorf("verify = %d, want 401", status)
+	}
+}
+
+func TestOTPRegisterRefusesAppointedRoles(t *testing.T) {
+	s := newOTPServer(t)
+
+	for _, role := range []string{"ADMIN", "OWNER"} {
+		status, _ := s.do(http.MethodPost, "/v1/customer/register", "", gin.H{"phone": "09121234567", "name": "Sara", "role": role})
+		if status != http.StatusBadRequest {
+			t.Errorf("register as %s = %d, want 400", role, status)
+		}
+	}
+	if s.smsLog.String() != "" {
+		t.Errorf("a code was texted for a refused registration:\n%s", s.smsLog)
+	}
+}
+
+// parallelVerify checks each of codes for phone at the same time and returns the statuses.
+func (s *otpServer) parallelVerify(phone string, codes []string) []int {
+	statuses := make([]int, len(codes))
+	var wg sync.WaitGroup
+	for i, code := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i], _ = s.do(http.MethodPost, "/v1/customer/login/verify", "", gin.H{"phone": phone, "code": code})
+		}()
+	}
+	wg.Wait()
+	return statuses
+}
+
+func TestOTPCodeLogsInOnce(t *testing.T) {
+	s := newOTPServer(t)
+	s.do(http.MethodPost, "/v1/customer/register", "", gin.H{"phone": "09121234567", "name": "Sara", "role": "CUSTOMER"})
+	code := s.lastCode("+989121234567")
+
+	ok := 0
+	for _, status := range s.parallelVerify("09121234567", []string{code, code, code, code}) {
+		if status == http.StatusOK {
+			ok++
+		}
+	}
+	if ok != 1 {
+		t.Errorf("%d parallel checks of the same code logged in, want 1", ok)
+	}
+}
+
+func TestOTPParallelGuessesKeepTheAttemptLimit(t *testing.T) {
+	s := newOTPServer(t)
+	s.do(http.MethodPost, "/v1/customer/register", "", gin.H{"phone": "09121234567", "name": "Sara", "role": "CUSTOMER"})
+	code := s.lastCode("+989121234567")
+
+	guesses := make([]string, 0, config.OTPMaxAttempts*2)
+	for i := 0; len(guesses) < cap(guesses); i++ {
+		if guess := fmt.Sprintf("%06d", i); guess != code {
+			guesses = append(guesses, guess)
+		}
+	}
+
+	// Only the guesses under the limit are told the code is wrong; the rest are refused outright
+	wrong := 0
+	for _, status := range s.parallelVerify("09121234567", guesses) {
+		if status == http.StatusUnauthorized {
+			wrong++
+		}
+	}
+	if wrong != config.OTPMaxAttempts-1 {
+		t.Errorf("%d of %d parallel guesses were checked, want %d", wrong, len(guesses), config.OTPMaxAttempts-1)
+	}
+}
+
+// login registers phone and returns an access token for it.
+func (s *otpServer) login(phone string) string {
+	s.t.Helper()
+	s.do(http.MethodPost, "/v1/customer/register", "", gin.H{"phone": phone, "name": "Sara", "role": "CUSTOMER"})
+	status, body := s.do(http.MethodPost, "/v1/customer/login/verify", "", gin.H{"phone": phone, "code": s.lastCode(phone)})
+	accessToken, _ := body["token"].(string)
+	if status != http.StatusOK || accessToken == "" {
+		s.t.Fatalf("verify = %d %v, want a token", status, body)
+	}
+	return accessToken
+}
+
+func TestOTPPhoneChangeIsRateLimitedPerCustomer(t *testing.T) {
+	s := newOTPServer(t)
+	accessToken := s.login("+989121234567")
+
+	for i := 0; i <= config.PhoneChangeUserLimit; i++ {
+		want := http.StatusOK
+		if i == config.PhoneChangeUserLimit {
+			want = http.StatusTooManyRequests
+		}
+		newPhone := fmt.Sprintf("+98935123450%d", i)
+		if status, body := s.do(http.MethodPost, "/v1/customer/me/phone", accessToken, gin.H{"phone": newPhone}); status != want {
+			t.Fatalf("phone change request %d = %d %v, want %d", i+1, status, body, want)
+		}
+	}
+}
+
+func TestOTPPhoneChangeGuessesLockTheNewPhone(t *testing.T) {
+	s := newOTPServer(t)
+	accessToken := s.login("+989121234567")
+	newPhone := "+989351234567"
+
+	if status, body := s.do(http.MethodPost, "/v1/customer/me/phone", accessToken, gin.H{"phone": newPhone}); status != http.StatusOK {
+		t.Fatalf("phone change request = %d %v, want 200", status, body)
+	}
+	code := s.lastCode(newPhone)
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	for i := 0; i < config.LockoutThreshold; i++ {
+		s.do(http.MethodPost, "/v1/customer/me/phone/verify", accessToken, gin.H{"phone": newPhone, "code": wrong})
+	}
+
+	status, body := s.do(http.MethodPost, "/v1/customer/me/phone", accessToken, gin.H{"phone": newPhone})
+	if status != http.StatusTooManyRequests {
+		t.Errorf("phone change request after %d wrong codes = %d %v, want 429", config.LockoutThreshold, status, body)
+	}
+
+	// The lockout covers phone changes only, the customer can still log in with their own number.
+	s.do(http.MethodPost, "/v1/customer/login", "", gin.H{"phone": "+989121234567"})
+	status, body = s.do(http.MethodPost, "/v1/customer/login/verify", "", gin.H{"phone": "+989121234567", "code": s.lastCode("+989121234567")})
+	if status != http.StatusOK {
+		t.Errorf("login after the phone change lockout = %d %v, want 200", status, body)
+	}
+}
@@ -2,11 +2,16 @@ package interfaces
 
 import (
 	"github.com/gin-gonic/gin"
+	"miniature/pkg/authz"
+	"miniature/pkg/token"
 )
 
-func NewRouter(handler CustomerHandler) *gin.Engine {
+func NewRouter(handler CustomerHandler, auth *token.Authenticator, signer *token.Signer) *gin.Engine {
 	r := gin.Default()
 
+	// Public keys other services use to verify our tokens
+	r.GET("/.well-known/jwks.json", JWKSHandler(signer))
+
 	// Grouped routes
 	v1 := r.Group("/v1")
 	{
@@ -14,12 +19,19 @@ func NewRouter(handler CustomerHandler) *gin.Engine {
 		{
 			customers.POST("/register", handler.Register)
 			customers.POST("/login", handler.Login)
-			customers.POST("/logout", handler.Logout)
+			customers.POST("/login/verify", handler.VerifyLogin)
+			customers.POST("/refresh", handler.Refresh)
 
 			protected := customers.Group("/")
-			protected.Use(AuthMiddleware())
+			protected.Use(AuthMiddleware(auth))
 			{
-				protected.GET("/me", handler.Me)
+				protected.GET("/me", authz.Require(authz.PermProfileRead), handler.Me)
+				protected.PATCH("/me", authz.Require(authz.PermProfileUpdate), handler.UpdateMe)
+				protected.POST("/me/phone", authz.Require(authz.PermProfileUpdate), handler.ChangePhone)
+				protected.POST("/me/phone/verify", authz.Require(authz.PermProfileUpdate), handler.ConfirmPhoneChange)
+				protected.POST("/logout", handler.Logout)
+				protected.POST("/logout/all", handler.LogoutAll)
+				protected.POST("/admin/revoke", authz.Require(authz.PermTokenRevoke), handler.RevokeTokens)
 			}
 		}
 	}
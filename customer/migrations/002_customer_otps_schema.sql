@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS customer_otps
+(
+    id          UUID PRIMARY KEY,
+    phone       VARCHAR(20) NOT NULL,
+    code_hash   TEXT        NOT NULL,
+    attempts    INTEGER     NOT NULL DEFAULT 0,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    consumed_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_otps_phone ON customer_otps (phone, created_at DESC);
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens
+(
+    id         UUID PRIMARY KEY,
+    user_id    UUID        NOT NULL,
+    family_id  UUID        NOT NULL,
+    token_hash TEXT        NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+
+-- Access tokens revoked one by one, by their jti. Rows can be purged once expires_at has passed.
+CREATE TABLE IF NOT EXISTS revoked_tokens
+(
+    jti        TEXT PRIMARY KEY,
+    user_id    UUID        NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
+
+-- Every access token issued to user_id before revoked_before is rejected ("log out all devices").
+CREATE TABLE IF NOT EXISTS user_token_revocations
+(
+    user_id        UUID PRIMARY KEY,
+    revoked_before TIMESTAMPTZ NOT NULL
+);
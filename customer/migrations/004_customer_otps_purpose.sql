@@ -0,0 +1,6 @@
+ALTER TABLE customer_otps
+    ADD COLUMN IF NOT EXISTS purpose TEXT NOT NULL DEFAULT 'LOGIN' CHECK (purpose IN ('LOGIN', 'PHONE_CHANGE')),
+    ADD COLUMN IF NOT EXISTS user_id UUID;
+
+DROP INDEX IF EXISTS idx_customer_otps_phone;
+CREATE INDEX IF NOT EXISTS idx_customer_otps_phone ON customer_otps (phone, purpose, created_at DESC);
@@ -0,0 +1,27 @@
+-- Shared state for the "postgres" rate limit store.
+CREATE TABLE IF NOT EXISTS rate_limit_counters
+(
+    key      TEXT PRIMARY KEY,
+    count    INTEGER     NOT NULL,
+    reset_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS rate_limit_blocks
+(
+    key           TEXT PRIMARY KEY,
+    blocked_until TIMESTAMPTZ NOT NULL
+);
+
+-- Attempts refused by a rate limit or lockout.
+CREATE TABLE IF NOT EXISTS auth_audit_log
+(
+    id         UUID PRIMARY KEY,
+    action     TEXT        NOT NULL,
+    phone      VARCHAR(20),
+    ip         TEXT        NOT NULL,
+    reason     TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_audit_log_created_at ON auth_audit_log (created_at);
+CREATE INDEX IF NOT EXISTS idx_auth_audit_log_phone ON auth_audit_log (phone);
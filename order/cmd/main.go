@@ -0,0 +1,65 @@
+package main
+
+import (
+	"log"
+	"miniature/order/internal/application"
+	"miniature/order/internal/config"
+	"miniature/order/internal/domain"
+	"miniature/order/internal/infra/notify"
+	"miniature/order/internal/infra/postgres"
+	"miniature/order/internal/infra/storage"
+	"miniature/order/internal/interfaces"
+	"miniature/pkg/token"
+	tokenconfig "miniature/pkg/token/config"
+)
+
+func main() {
+
+	db := postgres.NewPostgresConnection()
+	defer db.Close()
+
+	repo := postgres.NewRepository(db)
+	cashback := postgres.NewCashbackRepository(db)
+	carts := postgres.NewCartRepository(db)
+	jobLock := postgres.NewJobLock(db)
+	exports := postgres.NewExportRepository(db)
+	notifier := notify.NewLogNotifier()
+
+	var files domain.FileStorage = storage.NewLocalStorage(config.LocalStorageDir)
+	if config.StorageBackend == "s3" {
+		s3, err := storage.NewS3Storage(config.S3Endpoint, config.S3Region, config.S3Bucket, config.S3AccessKey, config.S3SecretKey)
+		if err != nil {
+			log.Fatalf("cannot configure S3 storage: %v", err)
+		}
+		files = s3
+	}
+	service := application.NewOrderService(
+		repo,
+		postgres.NewProductRepository(db),
+		postgres.NewShopRepository(db),
+		cashback,
+		carts,
+		postgres.NewCustomerRepository(db),
+		postgres.NewDraftRepository(db),
+		postgres.NewAnalyticsRepository(db),
+		exports,
+		files,
+		notifier,
+	)
+	handler := interfaces.NewHandler(service)
+	application.NewCashbackJobs(cashback, notifier, jobLock).Start(config.CashbackJobInterval)
+	application.NewCartSweeper(carts, jobLock).Start(config.CartSweepInterval)
+	application.NewExportWorker(exports, files).Start(config.ExportJobInterval)
+	verifier := token.NewJWKSVerifier(tokenconfig.JWKSURL)
+	if err := verifier.Refresh(); err != nil {
+		log.Printf("cannot load JWKS from %s, will retry: %v", tokenconfig.JWKSURL, err)
+	}
+	verifier.Start(tokenconfig.JWKSRefreshInterval)
+	auth := token.NewAuthenticator(verifier, token.NewPostgresRevocationStore(db))
+	route := interfaces.NewRouter(handler, auth, config.ChannelAPIKey)
+
+	addr := "localhost:8083"
+	route.Run(addr)
+
+	log.Printf("Order service running on: %s", addr)
+}
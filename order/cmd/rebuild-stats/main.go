@@ -0,0 +1,50 @@
+// Command rebuild-stats recomputes product_stats from the order history and lists every product
+// whose recorded stats had drifted. It only reports unless -apply is given.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"miniature/order/internal/domain"
+	"miniature/order/internal/infra/postgres"
+	"os"
+)
+
+func main() {
+	apply := flag.Bool("apply", false, "replace the recorded stats with the recomputed ones")
+	flag.Parse()
+
+	db := postgres.NewPostgresConnection()
+	defer db.Close()
+
+	rebuild, err := postgres.NewProductStatsRepository(db).Rebuild(*apply)
+	if err != nil {
+		log.Fatalf("rebuilding product stats: %v", err)
+	}
+
+	fmt.Printf("orders with a stale stats flag: %d\n", rebuild.FlaggedOrders)
+	fmt.Printf("products with drifted stats: %d\n", len(rebuild.Drift))
+	for _, drift := range rebuild.Drift {
+		fmt.Printf("  %s  recorded %s  expected %s\n", drift.ProductID, describe(drift.Recorded), describe(drift.Expected))
+	}
+
+	switch {
+	case *apply:
+		fmt.Println("product stats rebuilt")
+	case len(rebuild.Drift) > 0 || rebuild.FlaggedOrders > 0:
+		fmt.Println("run with -apply to fix them")
+		os.Exit(1)
+	}
+}
+
+func describe(stats *domain.ProductStats) string {
+	if stats == nil {
+		return "nothing"
+	}
+	last := "never"
+	if stats.LastPurchasedAt != nil {
+		last = stats.LastPurchasedAt.Format("2006-01-02 15:04:05")
+	}
+	return fmt.Sprintf("%d sold, %.2f revenue, last %s", stats.TotalSold, stats.TotalRevenue, last)
+}
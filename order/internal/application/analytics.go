@@ -0,0 +1,67 @@
+package application
+
+import (
+	"miniature/order/internal/domain"
+	"miniature/pkg/authz"
+)
+
+// defaultTopProducts is how many products GetTopProducts returns when no limit is given.
+const defaultTopProducts = 10
+
+func (s *orderService) GetSalesReport(shopIDStr, requestingUserIDStr string, r domain.ReportRange) (*domain.SalesReport, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopAnalyticsRead); err != nil {
+		return nil, err
+	}
+	sales, err := s.analytics.FindSales(shopIDStr, r.From, r.To)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewSalesReport(r, sales), nil
+}
+
+// GetTopProducts ranks the shop's products sold in the range. A limit of zero or less means the default.
+func (s *orderService) GetTopProducts(
+	shopIDStr, requestingUserIDStr string,
+	r domain.ReportRange,
+	by domain.ProductRanking,
+	limit int,
+) (*domain.TopProductsReport, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopAnalyticsRead); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultTopProducts
+	}
+	items, err := s.analytics.FindSoldItems(shopIDStr, r.From, r.To)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewTopProductsReport(r, items, by, limit), nil
+}
+
+func (s *orderService) GetCustomersReport(shopIDStr, requestingUserIDStr string, r domain.ReportRange) (*domain.CustomersReport, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopAnalyticsRead); err != nil {
+		return nil, err
+	}
+	sales, err := s.analytics.FindSales(shopIDStr, r.From, r.To)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewCustomersReport(r, sales), nil
+}
+
+// GetBestSellers ranks the shop's products by their all-time sales, read from product_stats rather
+// than the order history. A limit of zero or less means the default.
+func (s *orderService) GetBestSellers(
+	shopIDStr, requestingUserIDStr string,
+	by domain.ProductRanking,
+	limit int,
+) ([]*domain.ProductStats, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopAnalyticsRead); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultTopProducts
+	}
+	return s.analytics.FindBestSellers(shopIDStr, by, limit)
+}
@@ -0,0 +1,150 @@
+package application
+
+import (
+	"miniature/order/internal/config"
+	"miniature/order/internal/domain"
+	"miniature/pkg/authz"
+	"miniature/pkg/qrpayload"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OpenCart starts an in-store cart session at the shop's counter.
+func (s *orderService) OpenCart(shopIDStr, requestingUserIDStr string) (*domain.CartSession, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersManage); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &domain.CartSession{
+		ID:        uuid.New(),
+		ShopID:    uuid.MustParse(shopIDStr),
+		OpenedBy:  uuid.MustParse(requestingUserIDStr),
+		Status:    domain.CartOpen,
+		ExpiresAt: now.Add(config.CartSessionTTL),
+		CreatedAt: now,
+		Items:     []*domain.CartItem{},
+	}
+	if err := s.carts.Create(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (s *orderService) GetCart(shopIDStr, sessionIDStr, requestingUserIDStr string) (*domain.CartSession, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersManage); err != nil {
+		return nil, err
+	}
+	return s.findShopCart(shopIDStr, sessionIDStr)
+}
+
+// ScanCartItem adds a product identified by its code or by the payload of its QR code and reserves its stock.
+func (s *orderService) ScanCartItem(
+	shopIDStr, sessionIDStr, requestingUserIDStr string,
+	code, qr string,
+	quantity int,
+) (*domain.CartSession, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersManage); err != nil {
+		return nil, err
+	}
+	if quantity <= 0 {
+		return nil, domain.ErrInvalidQuantity
+	}
+	if _, err := s.findShopCart(shopIDStr, sessionIDStr); err != nil {
+		return nil, err
+	}
+
+	product, err := s.resolveScan(shopIDStr, code, qr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.carts.AddItem(sessionIDStr, product, quantity, time.Now().Add(config.CartSessionTTL)); err != nil {
+		return nil, err
+	}
+	return s.carts.FindByID(sessionIDStr)
+}
+
+// SetCartItemQuantity changes a line's quantity, reserving or releasing the difference. Zero removes the line.
+func (s *orderService) SetCartItemQuantity(
+	shopIDStr, sessionIDStr, itemIDStr, requestingUserIDStr string,
+	quantity int,
+) (*domain.CartSession, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersManage); err != nil {
+		return nil, err
+	}
+	if quantity < 0 {
+		return nil, domain.ErrInvalidQuantity
+	}
+	if _, err := s.findShopCart(shopIDStr, sessionIDStr); err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(itemIDStr); err != nil {
+		return nil, domain.ErrCartItemNotFound
+	}
+
+	if err := s.carts.SetItemQuantity(sessionIDStr, itemIDStr, quantity, time.Now().Add(config.CartSessionTTL)); err != nil {
+		return nil, err
+	}
+	return s.carts.FindByID(sessionIDStr)
+}
+
+// AbandonCart closes the session and puts everything scanned back in stock.
+func (s *orderService) AbandonCart(shopIDStr, sessionIDStr, requestingUserIDStr string) error {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersManage); err != nil {
+		return err
+	}
+	if _, err := s.findShopCart(shopIDStr, sessionIDStr); err != nil {
+		return err
+	}
+	return s.carts.Release(sessionIDStr, domain.CartAbandoned)
+}
+
+// findShopCart hides sessions of other shops behind ErrCartNotFound.
+func (s *orderService) findShopCart(shopIDStr, sessionIDStr string) (*domain.CartSession, error) {
+	if _, err := uuid.Parse(sessionIDStr); err != nil {
+		return nil, domain.ErrCartNotFound
+	}
+	session, err := s.carts.FindByID(sessionIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.ShopID.String() != shopIDStr {
+		return nil, domain.ErrCartNotFound
+	}
+	return session, nil
+}
+
+// resolveScan finds the active product of the shop a scanned code or QR payload refers to.
+func (s *orderService) resolveScan(shopIDStr, code, qr string) (*domain.Product, error) {
+	var product *domain.Product
+	switch {
+	case qr != "":
+		payload, err := qrpayload.Parse(qr)
+		if err != nil {
+			return nil, err
+		}
+		products, err := s.products.FindByIDs([]uuid.UUID{payload.ProductID})
+		if err != nil {
+			return nil, err
+		}
+		product = products[payload.ProductID]
+	case code != "":
+		found, err := s.products.FindByCode(shopIDStr, code)
+		if err != nil {
+			return nil, err
+		}
+		product = found
+	default:
+		return nil, domain.ErrScanRequired
+	}
+
+	if product == nil || product.ShopID.String() != shopIDStr {
+		return nil, domain.ErrProductNotFound
+	}
+	if !product.IsActive {
+		return nil, domain.ErrProductUnavailable
+	}
+	return product, nil
+}
@@ -0,0 +1,58 @@
+package application
+
+import (
+	"errors"
+	"log"
+	"miniature/order/internal/config"
+	"miniature/order/internal/domain"
+	"time"
+)
+
+const cartSweeperLock = "order:cart-sweeper"
+
+// CartSweeper releases the stock held by cart sessions that expired before checkout.
+type CartSweeper struct {
+	carts domain.CartRepository
+	lock  domain.JobLock
+}
+
+func NewCartSweeper(carts domain.CartRepository, lock domain.JobLock) *CartSweeper {
+	return &CartSweeper{carts: carts, lock: lock}
+}
+
+// Start sweeps every interval in the background.
+func (w *CartSweeper) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := w.RunOnce(); err != nil {
+				log.Printf("sweeping cart sessions: %v", err)
+			}
+		}
+	}()
+}
+
+// RunOnce sweeps unless another instance is already sweeping.
+func (w *CartSweeper) RunOnce() error {
+	_, err := w.lock.TryRun(cartSweeperLock, func() error {
+		for {
+			ids, err := w.carts.FindExpired(time.Now(), config.CartSweepBatchSize)
+			if err != nil {
+				return err
+			}
+
+			for _, id := range ids {
+				// A session checked out or abandoned since it was listed is simply skipped
+				if err := w.carts.Release(id, domain.CartExpired); err != nil && !errors.Is(err, domain.ErrCartClosed) {
+					return err
+				}
+			}
+
+			if len(ids) < config.CartSweepBatchSize {
+				return nil
+			}
+		}
+	})
+	return err
+}
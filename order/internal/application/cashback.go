@@ -0,0 +1,65 @@
+package application
+
+import (
+	"miniature/order/internal/domain"
+	"miniature/pkg/authz"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *orderService) GetCashback(customerIDStr string) (*CashbackSummary, error) {
+	if _, err := uuid.Parse(customerIDStr); err != nil {
+		return nil, domain.ErrForbidden
+	}
+
+	balance, err := s.cashback.Balance(customerIDStr)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.cashback.FindEntries(customerIDStr)
+	if err != nil {
+		return nil, err
+	}
+	return &CashbackSummary{Balance: balance, Entries: entries}, nil
+}
+
+// GetCashbackSettings returns the shop's settings, or a zero percent if it never configured cashback.
+func (s *orderService) GetCashbackSettings(shopIDStr, requestingUserIDStr string) (*domain.CashbackSettings, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersRead); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.cashback.FindSettings(shopIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = &domain.CashbackSettings{ShopID: uuid.MustParse(shopIDStr)}
+	}
+	return settings, nil
+}
+
+func (s *orderService) UpdateCashbackSettings(
+	shopIDStr, requestingUserIDStr string,
+	percent float64,
+	expiryDays int,
+) (*domain.CashbackSettings, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopCashbackManage); err != nil {
+		return nil, err
+	}
+
+	settings := &domain.CashbackSettings{
+		ShopID:     uuid.MustParse(shopIDStr),
+		Percent:    percent,
+		ExpiryDays: expiryDays,
+		UpdatedAt:  time.Now(),
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.cashback.SaveSettings(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
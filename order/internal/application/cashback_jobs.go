@@ -0,0 +1,101 @@
+package application
+
+import (
+	"fmt"
+	"log"
+	"miniature/order/internal/config"
+	"miniature/order/internal/domain"
+	"time"
+)
+
+const cashbackJobsLock = "order:cashback-jobs"
+
+// CashbackJobs expires unspent cashback and warns customers before it expires.
+type CashbackJobs struct {
+	cashback domain.CashbackRepository
+	notifier domain.Notifier
+	lock     domain.JobLock
+}
+
+func NewCashbackJobs(cashback domain.CashbackRepository, notifier domain.Notifier, lock domain.JobLock) *CashbackJobs {
+	return &CashbackJobs{cashback: cashback, notifier: notifier, lock: lock}
+}
+
+// Start runs the jobs every interval in the background.
+func (j *CashbackJobs) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := j.RunOnce(); err != nil {
+				log.Printf("running cashback jobs: %v", err)
+			}
+		}
+	}()
+}
+
+// RunOnce runs the jobs unless another instance is already running them.
+func (j *CashbackJobs) RunOnce() error {
+	_, err := j.lock.TryRun(cashbackJobsLock, func() error {
+		if err := j.expireCredits(); err != nil {
+			return err
+		}
+		return j.warnExpiringCredits()
+	})
+	return err
+}
+
+func (j *CashbackJobs) expireCredits() error {
+	for {
+		credits, err := j.cashback.FindExpiredCredits(time.Now(), config.CashbackJobBatchSize)
+		if err != nil {
+			return err
+		}
+
+		expiredAny := false
+		for _, credit := range credits {
+			expired, err := j.cashback.ExpireCredit(credit.ID.String())
+			if err != nil {
+				return err
+			}
+			if expired != nil {
+				expiredAny = true
+			}
+		}
+
+		// Stop on a short batch, or if nothing in a batch could be expired so it would come back unchanged
+		if len(credits) < config.CashbackJobBatchSize || !expiredAny {
+			return nil
+		}
+	}
+}
+
+func (j *CashbackJobs) warnExpiringCredits() error {
+	for {
+		credits, err := j.cashback.FindExpiringCredits(time.Now().Add(config.CashbackExpiryWarning), config.CashbackJobBatchSize)
+		if err != nil {
+			return err
+		}
+
+		warnedAny := false
+		for _, credit := range credits {
+			message := fmt.Sprintf(
+				"%.0f of your cashback expires on %s. Use it on your next order before then.",
+				credit.Remaining, credit.ExpiresAt.Format("2006-01-02"),
+			)
+			if err := j.notifier.NotifyCustomer(credit.CustomerID, message); err != nil {
+				log.Printf("cannot warn customer %s about expiring cashback: %v", credit.CustomerID, err)
+				continue
+			}
+			if err := j.cashback.MarkExpiryWarned(credit.ID.String()); err != nil {
+				return err
+			}
+			warnedAny = true
+		}
+
+		// Credits whose warning failed stay unmarked and are retried on the next run
+		if len(credits) < config.CashbackJobBatchSize || !warnedAny {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package application
+
+import (
+	"fmt"
+	"miniature/order/internal/domain"
+	"miniature/pkg/phone"
+)
+
+// PlaceChannelOrder places a PENDING order for the customer with the given phone number, as a chat
+// bot does after a conversation with them. Channels are trusted services that must only pass a
+// number the platform confirmed is the customer's own, so the customer is registered if they are new.
+func (s *orderService) PlaceChannelOrder(
+	shopIDStr, customerPhone string,
+	items []CodeItemRequest,
+	deliveryAddress string,
+) (*domain.Order, error) {
+	normalized, err := phone.Normalize(customerPhone)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make([]ItemRequest, 0, len(items))
+	for _, item := range items {
+		product, err := s.products.FindByCode(shopIDStr, item.Code)
+		if err != nil {
+			return nil, err
+		}
+		if product == nil {
+			return nil, fmt.Errorf("%w: %s", domain.ErrProductNotFound, item.Code)
+		}
+		byID = append(byID, ItemRequest{ProductID: product.ID.String(), Quantity: item.Quantity})
+	}
+	if len(byID) == 0 {
+		return nil, domain.ErrEmptyOrder
+	}
+
+	customerID, err := s.customers.FindOrCreateByPhone(normalized)
+	if err != nil {
+		return nil, err
+	}
+	return s.PlaceOrder(customerID.String(), shopIDStr, byID, deliveryAddress, 0)
+}
+
+// GetChannelOrder lets a channel follow the orders it placed.
+func (s *orderService) GetChannelOrder(orderIDStr string) (*domain.Order, error) {
+	return s.findOrder(orderIDStr)
+}
@@ -0,0 +1,272 @@
+package application
+
+import (
+	"miniature/order/internal/domain"
+	"miniature/pkg/authz"
+	"miniature/pkg/orderparse"
+	"miniature/pkg/phone"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DraftLineRequest is a line of a draft as the seller edits it. Code is matched against the shop's
+// products the same way a code in a message is.
+type DraftLineRequest struct {
+	Code     string
+	Quantity int
+	Variants map[string]string
+}
+
+// DraftUpdate is a seller's edit of a draft. Nil fields are left as they are; Lines, if not nil,
+// replaces every line of the draft.
+type DraftUpdate struct {
+	CustomerPhone   *string
+	DeliveryAddress *string
+	Lines           []DraftLineRequest
+}
+
+// CreateChannelDraft reads the products a customer asked for in a chat message into a draft the
+// shop's staff review before it becomes an order. customerPhone may be empty if the channel does not
+// know it yet.
+func (s *orderService) CreateChannelDraft(shopIDStr, channel, customerPhone, deliveryAddress, text string) (*domain.DraftOrder, error) {
+	return s.createDraft(shopIDStr, strings.ToUpper(strings.TrimSpace(channel)), customerPhone, deliveryAddress, text)
+}
+
+// GetChannelDraft lets a channel follow the drafts it created.
+func (s *orderService) GetChannelDraft(draftIDStr string) (*domain.DraftOrder, error) {
+	return s.findDraft(draftIDStr)
+}
+
+// CreateShopDraft builds a draft from text the seller pasted, such as a comment under a post.
+func (s *orderService) CreateShopDraft(
+	shopIDStr, requestingUserIDStr, customerPhone, deliveryAddress, text string,
+) (*domain.DraftOrder, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersManage); err != nil {
+		return nil, err
+	}
+	return s.createDraft(shopIDStr, "", customerPhone, deliveryAddress, text)
+}
+
+func (s *orderService) GetShopDrafts(shopIDStr, requestingUserIDStr string, status domain.DraftStatus) ([]*domain.DraftOrder, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersRead); err != nil {
+		return nil, err
+	}
+	return s.drafts.FindByShopID(shopIDStr, status)
+}
+
+func (s *orderService) GetShopDraft(shopIDStr, draftIDStr, requestingUserIDStr string) (*domain.DraftOrder, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersRead); err != nil {
+		return nil, err
+	}
+	return s.findShopDraft(shopIDStr, draftIDStr)
+}
+
+func (s *orderService) UpdateShopDraft(
+	shopIDStr, draftIDStr, requestingUserIDStr string,
+	update DraftUpdate,
+) (*domain.DraftOrder, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersManage); err != nil {
+		return nil, err
+	}
+	draft, err := s.findShopDraft(shopIDStr, draftIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if err := draft.EnsureOpen(); err != nil {
+		return nil, err
+	}
+
+	if update.CustomerPhone != nil {
+		if draft.CustomerPhone, err = normalizeOptionalPhone(*update.CustomerPhone); err != nil {
+			return nil, err
+		}
+	}
+	if update.DeliveryAddress != nil {
+		draft.DeliveryAddress = strings.TrimSpace(*update.DeliveryAddress)
+	}
+	if update.Lines != nil {
+		products, err := s.products.FindActiveByShopID(shopIDStr)
+		if err != nil {
+			return nil, err
+		}
+		lines := make([]orderparse.Line, 0, len(update.Lines))
+		for _, line := range update.Lines {
+			if line.Quantity <= 0 {
+				return nil, domain.ErrInvalidQuantity
+			}
+			lines = append(lines, orderparse.Line{Code: line.Code, Quantity: line.Quantity, Variants: line.Variants})
+		}
+		draft.Lines = matchDraftLines(draft, products, lines)
+	}
+
+	draft.UpdatedAt = time.Now()
+	if err := s.drafts.Update(draft); err != nil {
+		return nil, err
+	}
+	return s.findDraft(draft.ID.String())
+}
+
+// AcceptShopDraft places the order a draft describes, registering the customer by phone if they are
+// new. The variants the customer asked for are kept in the note of the order's first timeline entry.
+func (s *orderService) AcceptShopDraft(shopIDStr, draftIDStr, requestingUserIDStr string) (*domain.Order, error) {
+	role, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersManage)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.findShopDraft(shopIDStr, draftIDStr); err != nil {
+		return nil, err
+	}
+	actorID, _ := uuid.Parse(requestingUserIDStr)
+
+	build := func(draft *domain.DraftOrder, customers domain.CustomerDirectory) (*domain.Order, *domain.StatusChange, error) {
+		if err := draft.EnsureReady(); err != nil {
+			return nil, nil, err
+		}
+		customerID, err := customers.FindOrCreateByPhone(draft.CustomerPhone)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		items := make([]ItemRequest, 0, len(draft.Lines))
+		for _, line := range draft.Lines {
+			items = append(items, ItemRequest{ProductID: line.ProductID.String(), Quantity: line.Quantity})
+		}
+		order, err := s.newOrder(customerID, draft.ShopID, items, draft.DeliveryAddress)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		created := order.Created(actorID, role)
+		created.Note = draft.VariantNote()
+		return order, created, nil
+	}
+	return s.drafts.Accept(draftIDStr, build)
+}
+
+func (s *orderService) DiscardShopDraft(shopIDStr, draftIDStr, requestingUserIDStr string) error {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersManage); err != nil {
+		return err
+	}
+	if _, err := s.findShopDraft(shopIDStr, draftIDStr); err != nil {
+		return err
+	}
+	return s.drafts.Discard(draftIDStr)
+}
+
+func (s *orderService) createDraft(shopIDStr, channel, customerPhone, deliveryAddress, text string) (*domain.DraftOrder, error) {
+	shopID, err := uuid.Parse(shopIDStr)
+	if err != nil {
+		return nil, domain.ErrShopNotFound
+	}
+	name, err := s.memberships.ShopName(shopIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, domain.ErrShopNotFound
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, domain.ErrDraftTextRequired
+	}
+	normalized, err := normalizeOptionalPhone(customerPhone)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	draft := &domain.DraftOrder{
+		ID:              uuid.New(),
+		ShopID:          shopID,
+		Channel:         channel,
+		SourceText:      text,
+		CustomerPhone:   normalized,
+		DeliveryAddress: strings.TrimSpace(deliveryAddress),
+		Status:          domain.DraftOpen,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	products, err := s.products.FindActiveByShopID(shopIDStr)
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, len(products))
+	for _, product := range products {
+		codes = append(codes, product.Code)
+	}
+	draft.Lines = matchDraftLines(draft, products, orderparse.Parse(text, codes...))
+
+	if err := s.drafts.Create(draft); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// matchDraftLines resolves the codes of parsed lines against the shop's active products. A line
+// whose code matches nothing is kept so the seller can fix it.
+func matchDraftLines(draft *domain.DraftOrder, products []*domain.Product, parsed []orderparse.Line) []*domain.DraftLine {
+	byCode := make(map[string]*domain.Product, len(products))
+	codes := make([]string, 0, len(products))
+	for _, product := range products {
+		byCode[product.Code] = product
+		codes = append(codes, product.Code)
+	}
+
+	lines := make([]*domain.DraftLine, 0, len(parsed))
+	for _, p := range parsed {
+		line := &domain.DraftLine{
+			ID:            uuid.New(),
+			DraftID:       draft.ID,
+			RequestedCode: strings.TrimSpace(p.Code),
+			Quantity:      p.Quantity,
+			Variants:      p.Variants,
+		}
+		var code string
+		code, line.Match = orderparse.Match(p.Code, codes)
+		if product, ok := byCode[code]; ok {
+			line.ProductID = &product.ID
+			line.ProductCode = product.Code
+			line.ProductName = product.Name
+			line.UnitPrice = product.Price
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (s *orderService) findDraft(draftIDStr string) (*domain.DraftOrder, error) {
+	if _, err := uuid.Parse(draftIDStr); err != nil {
+		return nil, domain.ErrDraftNotFound
+	}
+	draft, err := s.drafts.FindByID(draftIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if draft == nil {
+		return nil, domain.ErrDraftNotFound
+	}
+	return draft, nil
+}
+
+// findShopDraft hides drafts of other shops behind ErrDraftNotFound.
+func (s *orderService) findShopDraft(shopIDStr, draftIDStr string) (*domain.DraftOrder, error) {
+	draft, err := s.findDraft(draftIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if draft.ShopID.String() != shopIDStr {
+		return nil, domain.ErrDraftNotFound
+	}
+	return draft, nil
+}
+
+// normalizeOptionalPhone normalizes a phone number that may be left empty.
+func normalizeOptionalPhone(number string) (string, error) {
+	if strings.TrimSpace(number) == "" {
+		return "", nil
+	}
+	return phone.Normalize(number)
+}
@@ -0,0 +1,134 @@
+package application
+
+import (
+	"errors"
+	"miniature/order/internal/domain"
+	"miniature/pkg/authz"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type staffOf map[string]authz.Role // user -> role in the shop
+
+func (s staffOf) MemberRole(userID, _ string) (authz.Role, error) {
+	return s[userID], nil
+}
+
+func (staffOf) ShopName(string) (string, error) {
+	return "Shop", nil
+}
+
+type productsByID map[uuid.UUID]*domain.Product
+
+func (p productsByID) FindByIDs(ids []uuid.UUID) (map[uuid.UUID]*domain.Product, error) {
+	found := map[uuid.UUID]*domain.Product{}
+	for _, id := range ids {
+		if product, ok := p[id]; ok {
+			found[id] = product
+		}
+	}
+	return found, nil
+}
+
+func (productsByID) FindByCode(string, string) (*domain.Product, error) {
+	return nil, nil
+}
+
+func (productsByID) FindActiveByShopID(string) ([]*domain.Product, error) {
+	return nil, nil
+}
+
+// customersByPhone is the customer table.
+type customersByPhone map[string]uuid.UUID
+
+func (c customersByPhone) FindIDByPhone(phone string) (uuid.UUID, error) {
+	return c[phone], nil
+}
+
+func (c customersByPhone) FindOrCreateByPhone(phone string) (uuid.UUID, error) {
+	if _, ok := c[phone]; !ok {
+		c[phone] = uuid.New()
+	}
+	return c[phone], nil
+}
+
+// oneDraft keeps a single draft. Accept runs build on a copy of the customers and keeps what build
+// registered only if the order can take its stock, like the transaction of the postgres repository.
+type oneDraft struct {
+	domain.DraftRepository
+	draft     *domain.DraftOrder
+	products  productsByID
+	customers customersByPhone
+}
+
+func (r *oneDraft) FindByID(id string) (*domain.DraftOrder, error) {
+	if r.draft.ID.String() != id {
+		return nil, nil
+	}
+	return r.draft, nil
+}
+
+func (r *oneDraft) Accept(
+	id string,
+	build func(draft *domain.DraftOrder, customers domain.CustomerDirectory) (*domain.Order, *domain.StatusChange, error),
+) (*domain.Order, error) {
+	tx := customersByPhone{}
+	for phone, customerID := range r.customers {
+		tx[phone] = customerID
+	}
+	order, _, err := build(r.draft, tx)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range order.Items {
+		if r.products[item.ProductID].StockQuantity < item.Quantity {
+			return nil, domain.ErrOutOfStock
+		}
+	}
+
+	r.customers = tx
+	r.draft.Status = domain.DraftAccepted
+	return order, nil
+}
+
+func TestAcceptShopDraftRegistersTheCustomerWithTheOrder(t *testing.T) {
+	shopID, sellerID := uuid.New(), uuid.New()
+	productID := uuid.New()
+	tests := []struct {
+		name          string
+		quantity      int
+		want          error
+		wantCustomers int
+	}{
+		{"out of stock", 3, domain.ErrOutOfStock, 0},
+		{"in stock", 2, nil, 1},
+	}
+	for _, tt := range tests {
+		products := productsByID{productID: {ID: productID, ShopID: shopID, Name: "Scarf", Price: 125000, StockQuantity: 2, IsActive: true}}
+		drafts := &oneDraft{
+			draft: &domain.DraftOrder{
+				ID: uuid.New(), ShopID: shopID, CustomerPhone: "+989121234567", Status: domain.DraftOpen,
+				Lines: []*domain.DraftLine{{ID: uuid.New(), RequestedCode: "SL38", ProductID: &productID, Quantity: tt.quantity}},
+			},
+			products:  products,
+			customers: customersByPhone{},
+		}
+		// Customers may only be registered through the draft's transaction; registering one outside panics.
+		var outside customersByPhone
+		s := NewOrderService(
+			nil, products, staffOf{sellerID.String(): authz.RoleSeller}, nil, nil, outside, drafts, nil, nil, nil, nil,
+		)
+
+		order, err := s.AcceptShopDraft(shopID.String(), drafts.draft.ID.String(), sellerID.String())
+		if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+			t.Fatalf("%s: AcceptShopDraft = %v, want %v", tt.name, err, tt.want)
+		}
+		if len(drafts.customers) != tt.wantCustomers {
+			t.Errorf("%s: %d customers registered, want %d", tt.name, len(drafts.customers), tt.wantCustomers)
+		}
+		if order != nil && order.CustomerID != drafts.customers["+989121234567"] {
+			t.Errorf("%s: order placed for %s, not the registered customer", tt.name, order.CustomerID)
+		}
+	}
+}
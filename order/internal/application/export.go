@@ -0,0 +1,170 @@
+package application
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"miniature/order/internal/config"
+	"miniature/order/internal/domain"
+	"miniature/pkg/authz"
+	"miniature/pkg/xlsx"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// ExportShopData writes the shop's dataset to w as it is read from the database. Exports of more
+// than config.MaxDirectExportRows rows are refused with ErrExportTooLarge and must go through an
+// export job, so long exports do not tie up a request.
+func (s *orderService) ExportShopData(shopIDStr, requestingUserIDStr string, options domain.ExportOptions, w io.Writer) error {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopDataExport); err != nil {
+		return err
+	}
+	count, err := s.exports.Count(shopIDStr, options)
+	if err != nil {
+		return err
+	}
+	if count > config.MaxDirectExportRows {
+		return domain.ErrExportTooLarge
+	}
+	_, err = writeExport(s.exports, shopIDStr, options, w)
+	return err
+}
+
+func (s *orderService) CreateExportJob(shopIDStr, requestingUserIDStr string, options domain.ExportOptions) (*domain.ExportJob, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopDataExport); err != nil {
+		return nil, err
+	}
+	job := domain.NewExportJob(uuid.MustParse(shopIDStr), uuid.MustParse(requestingUserIDStr), options)
+	if err := s.exports.CreateJob(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *orderService) GetExportJobs(shopIDStr, requestingUserIDStr string) ([]*domain.ExportJob, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopDataExport); err != nil {
+		return nil, err
+	}
+	return s.exports.FindJobsByShopID(shopIDStr)
+}
+
+func (s *orderService) GetExportJob(shopIDStr, jobIDStr, requestingUserIDStr string) (*domain.ExportJob, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopDataExport); err != nil {
+		return nil, err
+	}
+	return s.findShopExportJob(shopIDStr, jobIDStr)
+}
+
+// DownloadExport returns the file of a finished job along with the job.
+func (s *orderService) DownloadExport(shopIDStr, jobIDStr, requestingUserIDStr string) (io.ReadCloser, *domain.ExportJob, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopDataExport); err != nil {
+		return nil, nil, err
+	}
+	job, err := s.findShopExportJob(shopIDStr, jobIDStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != domain.ExportDone {
+		return nil, nil, domain.ErrExportNotReady
+	}
+	file, err := s.storage.Open(job.FileKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, job, nil
+}
+
+// findShopExportJob reports a job of another shop as not found, as findShopOrder does for orders.
+func (s *orderService) findShopExportJob(shopIDStr, jobIDStr string) (*domain.ExportJob, error) {
+	if _, err := uuid.Parse(jobIDStr); err != nil {
+		return nil, domain.ErrExportJobNotFound
+	}
+	job, err := s.exports.FindJob(jobIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil || job.ShopID.String() != shopIDStr {
+		return nil, domain.ErrExportJobNotFound
+	}
+	return job, nil
+}
+
+// writeExport streams the dataset into w in the export's format and returns how many rows it wrote.
+func writeExport(exports domain.ExportRepository, shopID string, options domain.ExportOptions, w io.Writer) (int, error) {
+	table, err := newTableWriter(w, options)
+	if err != nil {
+		return 0, err
+	}
+	if err := table.WriteHeader(options.Header()); err != nil {
+		return 0, err
+	}
+	rows := 0
+	err = exports.Stream(shopID, options, func(row []any) error {
+		rows++
+		return table.WriteRow(options.Cells(row))
+	})
+	if err != nil {
+		return rows, err
+	}
+	return rows, table.Close()
+}
+
+// tableWriter is what writeExport needs of a CSV or XLSX writer.
+type tableWriter interface {
+	WriteHeader(names []string) error
+	WriteRow(values []any) error
+	Close() error
+}
+
+func newTableWriter(w io.Writer, options domain.ExportOptions) (tableWriter, error) {
+	if options.Format == domain.ExportXLSX {
+		return xlsx.NewWriter(w, xlsx.Options{
+			SheetName:   string(options.Dataset),
+			RightToLeft: options.Headers == domain.HeadersPersian,
+		})
+	}
+	return newCSVTable(w), nil
+}
+
+// csvTable writes UTF-8 CSV starting with a byte order mark, without which Excel shows Persian
+// text as mojibake.
+type csvTable struct {
+	buf    *bufio.Writer
+	csv    *csv.Writer
+	record []string
+}
+
+func newCSVTable(w io.Writer) *csvTable {
+	buf := bufio.NewWriter(w)
+	buf.WriteString("\ufeff")
+	return &csvTable{buf: buf, csv: csv.NewWriter(buf)}
+}
+
+func (t *csvTable) WriteHeader(names []string) error {
+	return t.csv.Write(names)
+}
+
+func (t *csvTable) WriteRow(values []any) error {
+	t.record = t.record[:0]
+	for _, value := range values {
+		switch v := value.(type) {
+		case nil:
+			t.record = append(t.record, "")
+		case float64:
+			t.record = append(t.record, strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			t.record = append(t.record, fmt.Sprint(v))
+		}
+	}
+	return t.csv.Write(t.record)
+}
+
+func (t *csvTable) Close() error {
+	t.csv.Flush()
+	if err := t.csv.Error(); err != nil {
+		return err
+	}
+	return t.buf.Flush()
+}
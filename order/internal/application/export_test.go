@@ -0,0 +1,28 @@
+package application
+
+import (
+	"bytes"
+	"miniature/order/internal/domain"
+	"testing"
+)
+
+func TestCSVTable(t *testing.T) {
+	var buf bytes.Buffer
+	table := newCSVTable(&buf)
+	options := domain.ExportOptions{Headers: domain.HeadersEnglish}
+	if err := table.WriteHeader([]string{"name", "phone", "spent", "address"}); err != nil {
+		t.Fatal(err)
+	}
+	err := table.WriteRow(options.Cells([]any{"=HYPERLINK(\"http://x\",\"Ali\")", "+989121234567", 1250.5, nil}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "\ufeffname,phone,spent,address\n\"'=HYPERLINK(\"\"http://x\"\",\"\"Ali\"\")\",+989121234567,1250.5,\n"
+	if got := buf.String(); got != want {
+		t.Errorf("CSV = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+package application
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"miniature/order/internal/config"
+	"miniature/order/internal/domain"
+	"os"
+	"time"
+)
+
+// ExportWorker runs export jobs. Every instance runs one, each claiming its own jobs.
+type ExportWorker struct {
+	exports domain.ExportRepository
+	storage domain.FileStorage
+}
+
+func NewExportWorker(exports domain.ExportRepository, storage domain.FileStorage) *ExportWorker {
+	return &ExportWorker{exports: exports, storage: storage}
+}
+
+// Start looks for jobs every interval in the background.
+func (w *ExportWorker) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := w.RunOnce(); err != nil {
+				log.Printf("running export jobs: %v", err)
+			}
+		}
+	}()
+}
+
+// RunOnce runs jobs until none are waiting. A job that fails is recorded as FAILED and does not
+// stop the others.
+func (w *ExportWorker) RunOnce() error {
+	for {
+		job, err := w.exports.ClaimJob(time.Now().Add(-config.ExportJobTimeout))
+		if err != nil {
+			return err
+		}
+		if job == nil {
+			return nil
+		}
+
+		key := fmt.Sprintf("exports/%s/%s.%s", job.ShopID, job.ID, job.Format)
+		rows, err := w.export(job, key)
+		if err != nil {
+			log.Printf("export job %s failed: %v", job.ID, err)
+		}
+		job.Finish(rows, key, err)
+		if err := w.exports.FinishJob(job); err != nil {
+			return err
+		}
+	}
+}
+
+// export writes the job's file to a temporary file first, since storage needs its size up front,
+// then saves it under key.
+func (w *ExportWorker) export(job *domain.ExportJob, key string) (int, error) {
+	tmp, err := os.CreateTemp("", "export-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	rows, err := writeExport(w.exports, job.ShopID.String(), job.ExportOptions, tmp)
+	if err != nil {
+		return rows, err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return rows, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return rows, err
+	}
+	return rows, w.storage.Save(key, job.Format.ContentType(), tmp, size)
+}
@@ -0,0 +1,159 @@
+package application
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"miniature/order/internal/config"
+	"miniature/order/internal/domain"
+	"miniature/pkg/authz"
+	"net/http"
+	"path"
+
+	"github.com/google/uuid"
+)
+
+// paymentProofTypes maps the accepted receipt types, detected from the file content, to the extension they are stored with.
+var paymentProofTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// SubmitPaymentProof stores a receipt uploaded by the customer who placed the order and queues it for the seller.
+func (s *orderService) SubmitPaymentProof(orderIDStr, customerIDStr string, file io.Reader, size int64) (*domain.Order, error) {
+	if size > config.MaxPaymentProofSize {
+		return nil, domain.ErrPaymentProofTooLarge
+	}
+
+	order, err := s.findOrder(orderIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if order.CustomerID.String() != customerIDStr {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	// The declared content type can't be trusted, so detect it from the first bytes
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	ext, ok := paymentProofTypes[contentType]
+	if !ok {
+		return nil, domain.ErrInvalidPaymentProof
+	}
+
+	from := order.PaymentStatus
+	key := fmt.Sprintf("payment-proofs/%s/%s%s", order.ID, uuid.New(), ext)
+	if err := order.SubmitPaymentProof(key); err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Save(key, contentType, io.MultiReader(bytes.NewReader(head), file), size); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdatePayment(order, from, nil); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// GetPaymentProof opens the receipt of an order for the customer who placed it or the staff of its shop.
+func (s *orderService) GetPaymentProof(orderIDStr, requestingUserIDStr string) (io.ReadCloser, string, error) {
+	order, _, err := s.findViewableOrder(orderIDStr, requestingUserIDStr)
+	if err != nil {
+		return nil, "", err
+	}
+	return s.openPaymentProof(order)
+}
+
+func (s *orderService) GetShopPaymentProof(shopIDStr, orderIDStr, requestingUserIDStr string) (io.ReadCloser, string, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersRead); err != nil {
+		return nil, "", err
+	}
+	order, err := s.findShopOrder(shopIDStr, orderIDStr)
+	if err != nil {
+		return nil, "", err
+	}
+	return s.openPaymentProof(order)
+}
+
+// GetPaymentQueue lists the shop's orders whose receipts are waiting for verification.
+func (s *orderService) GetPaymentQueue(shopIDStr, requestingUserIDStr string) ([]*domain.Order, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersManage); err != nil {
+		return nil, err
+	}
+	return s.repo.FindAwaitingPaymentReview(shopIDStr)
+}
+
+func (s *orderService) ApprovePayment(shopIDStr, orderIDStr, requestingUserIDStr string) (*domain.Order, error) {
+	role, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersManage)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.findShopOrder(shopIDStr, orderIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	actorID, _ := uuid.Parse(requestingUserIDStr)
+	change, err := order.ApprovePayment(actorID, role)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdatePayment(order, domain.PaymentSubmitted, change); err != nil {
+		return nil, err
+	}
+
+	s.notify(order.CustomerID, fmt.Sprintf("Your payment for order %s was approved.", order.ID))
+	return order, nil
+}
+
+func (s *orderService) RejectPayment(shopIDStr, orderIDStr, requestingUserIDStr, reason string) (*domain.Order, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersManage); err != nil {
+		return nil, err
+	}
+	order, err := s.findShopOrder(shopIDStr, orderIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := order.RejectPayment(reason); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdatePayment(order, domain.PaymentSubmitted, nil); err != nil {
+		return nil, err
+	}
+
+	s.notify(order.CustomerID, fmt.Sprintf(
+		"Your payment for order %s was rejected: %s. Please upload a new receipt.",
+		order.ID, order.PaymentRejectionReason,
+	))
+	return order, nil
+}
+
+func (s *orderService) openPaymentProof(order *domain.Order) (io.ReadCloser, string, error) {
+	if order.PaymentProofURL == "" {
+		return nil, "", domain.ErrFileNotFound
+	}
+	file, err := s.storage.Open(order.PaymentProofURL)
+	if err != nil {
+		return nil, "", err
+	}
+	return file, mime.TypeByExtension(path.Ext(order.PaymentProofURL)), nil
+}
+
+// notify tells the customer about their order. A failed notification doesn't undo the change it reports.
+func (s *orderService) notify(customerID uuid.UUID, message string) {
+	if err := s.notifier.NotifyCustomer(customerID, message); err != nil {
+		log.Printf("cannot notify customer %s: %v", customerID, err)
+	}
+}
@@ -0,0 +1,125 @@
+package application
+
+import (
+	"miniature/order/internal/domain"
+	"miniature/pkg/authz"
+	"miniature/pkg/phone"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentPart is one part of a split payment at the counter.
+type PaymentPart struct {
+	Method string
+	Amount float64
+}
+
+// ReceiptLine is a printed line of a receipt.
+type ReceiptLine struct {
+	ProductName string  `json:"product_name"`
+	Code        string  `json:"code"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	LineTotal   float64 `json:"line_total"`
+}
+
+// Receipt is what the counter prints after an in-store sale.
+type Receipt struct {
+	OrderID         uuid.UUID         `json:"order_id"`
+	ShopID          uuid.UUID         `json:"shop_id"`
+	ShopName        string            `json:"shop_name"`
+	CashierID       uuid.UUID         `json:"cashier_id"`
+	CustomerPhone   string            `json:"customer_phone,omitempty"` // masked, e.g. +98912***4567
+	Lines           []ReceiptLine     `json:"lines"`
+	Total           float64           `json:"total"`
+	CashbackApplied float64           `json:"cashback_applied"`
+	Payments        []*domain.Payment `json:"payments"`
+	IssuedAt        time.Time         `json:"issued_at"`
+}
+
+// CheckoutCart turns an open cart session into a PAID order. customerPhone may be empty for a
+// walk-in customer; otherwise it links the sale to that customer, who can then pay with cashback.
+func (s *orderService) CheckoutCart(
+	shopIDStr, sessionIDStr, requestingUserIDStr string,
+	customerPhone string,
+	parts []PaymentPart,
+) (*Receipt, error) {
+	role, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersManage)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.findShopCart(shopIDStr, sessionIDStr); err != nil {
+		return nil, err
+	}
+
+	customerID := uuid.Nil
+	normalizedPhone := ""
+	if customerPhone != "" {
+		normalizedPhone, err = phone.Normalize(customerPhone)
+		if err != nil {
+			return nil, err
+		}
+		customerID, err = s.customers.FindIDByPhone(normalizedPhone)
+		if err != nil {
+			return nil, err
+		}
+		if customerID == uuid.Nil {
+			return nil, domain.ErrCustomerNotFound
+		}
+	}
+
+	payments := make([]domain.Payment, 0, len(parts))
+	for _, part := range parts {
+		method, err := domain.ParsePaymentMethod(part.Method)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, domain.Payment{Method: method, Amount: part.Amount})
+	}
+
+	actorID := uuid.MustParse(requestingUserIDStr)
+	var cart *domain.CartSession
+	order, err := s.carts.Checkout(sessionIDStr, func(session *domain.CartSession) (*domain.Order, *domain.StatusChange, error) {
+		cart = session
+		return domain.NewPOSOrder(session, customerID, payments, actorID, role)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	shopName, err := s.memberships.ShopName(shopIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &Receipt{
+		OrderID:         order.ID,
+		ShopID:          order.ShopID,
+		ShopName:        shopName,
+		CashierID:       actorID,
+		CustomerPhone:   maskPhone(normalizedPhone),
+		Total:           order.TotalAmount,
+		CashbackApplied: order.CashbackApplied,
+		Payments:        order.Payments,
+		IssuedAt:        order.CreatedAt,
+	}
+	for _, item := range cart.Items {
+		receipt.Lines = append(receipt.Lines, ReceiptLine{
+			ProductName: item.ProductName,
+			Code:        item.Code,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			LineTotal:   item.UnitPrice * float64(item.Quantity),
+		})
+	}
+	return receipt, nil
+}
+
+// maskPhone hides the middle digits of a normalized phone number so a printed receipt doesn't expose it.
+func maskPhone(p string) string {
+	if len(p) < 10 {
+		return p
+	}
+	return p[:6] + "***" + p[len(p)-4:]
+}
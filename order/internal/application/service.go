@@ -0,0 +1,345 @@
+package application
+
+import (
+	"fmt"
+	"miniature/order/internal/domain"
+	"miniature/pkg/authz"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type orderService struct {
+	repo        domain.Repository
+	products    domain.ProductCatalog
+	memberships domain.ShopMembershipRepository
+	cashback    domain.CashbackRepository
+	carts       domain.CartRepository
+	customers   domain.CustomerDirectory
+	drafts      domain.DraftRepository
+	analytics   domain.AnalyticsRepository
+	exports     domain.ExportRepository
+	storage     domain.FileStorage
+	notifier    domain.Notifier
+}
+
+func NewOrderService(
+	repo domain.Repository,
+	products domain.ProductCatalog,
+	memberships domain.ShopMembershipRepository,
+	cashback domain.CashbackRepository,
+	carts domain.CartRepository,
+	customers domain.CustomerDirectory,
+	drafts domain.DraftRepository,
+	analytics domain.AnalyticsRepository,
+	exports domain.ExportRepository,
+	storage domain.FileStorage,
+	notifier domain.Notifier,
+) Usecase {
+	return &orderService{
+		repo:        repo,
+		products:    products,
+		memberships: memberships,
+		cashback:    cashback,
+		carts:       carts,
+		customers:   customers,
+		drafts:      drafts,
+		analytics:   analytics,
+		exports:     exports,
+		storage:     storage,
+		notifier:    notifier,
+	}
+}
+
+// PlaceOrder creates a PENDING order, spending cashback of the customer's balance on it if cashback is positive.
+func (s *orderService) PlaceOrder(
+	customerIDStr, shopIDStr string,
+	items []ItemRequest,
+	deliveryAddress string,
+	cashback float64,
+) (*domain.Order, error) {
+	customerID, err := uuid.Parse(customerIDStr)
+	if err != nil {
+		return nil, domain.ErrForbidden
+	}
+	shopID, err := uuid.Parse(shopIDStr)
+	if err != nil {
+		return nil, domain.ErrShopNotFound
+	}
+
+	order, err := s.newOrder(customerID, shopID, items, deliveryAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	created := order.Created(customerID, authz.RoleCustomer)
+	created.Cashback, err = order.ApplyCashback(cashback)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(order, created); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// newOrder builds a PENDING online order of the shop's active products at their current prices.
+func (s *orderService) newOrder(customerID, shopID uuid.UUID, items []ItemRequest, deliveryAddress string) (*domain.Order, error) {
+	quantities, productIDs, err := mergeItems(items)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.products.FindByIDs(productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &domain.Order{
+		ID:              uuid.New(),
+		ShopID:          shopID,
+		CustomerID:      customerID,
+		Status:          domain.StatusPending,
+		Source:          domain.SourceOnline,
+		PaymentStatus:   domain.PaymentAwaitingProof,
+		DeliveryAddress: deliveryAddress,
+		CreatedAt:       time.Now(),
+	}
+	for _, productID := range productIDs {
+		product, ok := products[productID]
+		if !ok || product.ShopID != shopID {
+			return nil, fmt.Errorf("%w: %s", domain.ErrProductNotFound, productID)
+		}
+		if !product.IsActive {
+			return nil, fmt.Errorf("%w: %s", domain.ErrProductUnavailable, productID)
+		}
+
+		item := &domain.OrderItem{
+			ID:           uuid.New(),
+			OrderID:      order.ID,
+			ProductID:    productID,
+			ProductName:  product.Name,
+			Quantity:     quantities[productID],
+			PriceAtOrder: product.Price,
+		}
+		order.Items = append(order.Items, item)
+		order.TotalAmount += item.PriceAtOrder * float64(item.Quantity)
+	}
+	return order, nil
+}
+
+// GetOrder returns an order to the customer who placed it or to the staff of its shop.
+func (s *orderService) GetOrder(orderIDStr, requestingUserIDStr string) (*domain.Order, error) {
+	order, _, err := s.findViewableOrder(orderIDStr, requestingUserIDStr)
+	return order, err
+}
+
+func (s *orderService) GetCustomerOrders(customerIDStr string) ([]*domain.Order, error) {
+	return s.repo.FindByCustomerID(customerIDStr)
+}
+
+// ChangeOrderStatus applies a status change requested by the customer who placed the order.
+func (s *orderService) ChangeOrderStatus(orderIDStr, customerIDStr string, status domain.Status, note string) (*domain.Order, error) {
+	order, err := s.findOrder(orderIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if order.CustomerID.String() != customerIDStr {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	if err := s.transition(order, order.CustomerID, authz.RoleCustomer, status, note); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *orderService) GetOrderTimeline(orderIDStr, requestingUserIDStr string) (*Timeline, error) {
+	order, role, err := s.findViewableOrder(orderIDStr, requestingUserIDStr)
+	if err != nil {
+		return nil, err
+	}
+	return s.timeline(order, role)
+}
+
+func (s *orderService) GetShopOrders(shopIDStr, requestingUserIDStr string, status domain.Status) ([]*domain.Order, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersRead); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByShopID(shopIDStr, status)
+}
+
+func (s *orderService) GetShopOrder(shopIDStr, orderIDStr, requestingUserIDStr string) (*domain.Order, error) {
+	if _, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersRead); err != nil {
+		return nil, err
+	}
+	return s.findShopOrder(shopIDStr, orderIDStr)
+}
+
+func (s *orderService) UpdateShopOrder(
+	shopIDStr, orderIDStr, requestingUserIDStr string,
+	status *domain.Status,
+	deliveryEstimate *string,
+	note string,
+) (*domain.Order, error) {
+	role, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersManage)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.findShopOrder(shopIDStr, orderIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the transition before saving anything else so a refused change leaves the order untouched
+	if status != nil {
+		if err := domain.CanTransition(order.Status, *status, role); err != nil {
+			return nil, err
+		}
+	}
+
+	if deliveryEstimate != nil {
+		order.DeliveryEstimate = *deliveryEstimate
+		if err := s.repo.Update(order); err != nil {
+			return nil, err
+		}
+	}
+
+	if status != nil {
+		actorID, _ := uuid.Parse(requestingUserIDStr)
+		if err := s.transition(order, actorID, role, *status, note); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+func (s *orderService) GetShopOrderTimeline(shopIDStr, orderIDStr, requestingUserIDStr string) (*Timeline, error) {
+	role, err := s.authorize(requestingUserIDStr, shopIDStr, authz.PermShopOrdersRead)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.findShopOrder(shopIDStr, orderIDStr)
+	if err != nil {
+		return nil, err
+	}
+	return s.timeline(order, role)
+}
+
+// transition moves the order through the state machine and records the change.
+func (s *orderService) transition(order *domain.Order, actorID uuid.UUID, role authz.Role, status domain.Status, note string) error {
+	change, err := order.Transition(status, actorID, role, note)
+	if err != nil {
+		return err
+	}
+
+	switch change.ToStatus {
+	case domain.StatusDelivered:
+		settings, err := s.cashback.FindSettings(order.ShopID.String())
+		if err != nil {
+			return err
+		}
+		change.Cashback = order.EarnCashback(settings)
+	case domain.StatusCancelled:
+		change.Cashback = order.RefundCashback()
+	}
+
+	return s.repo.UpdateStatus(order, change)
+}
+
+func (s *orderService) timeline(order *domain.Order, role authz.Role) (*Timeline, error) {
+	history, err := s.repo.FindHistory(order.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	return &Timeline{
+		OrderID:      order.ID,
+		Status:       order.Status,
+		NextStatuses: domain.NextStatuses(order.Status, role),
+		History:      history,
+	}, nil
+}
+
+// findViewableOrder returns the order and the role the user views it with: CUSTOMER for the
+// customer who placed it, otherwise their role on the shop's staff.
+func (s *orderService) findViewableOrder(orderIDStr, requestingUserIDStr string) (*domain.Order, authz.Role, error) {
+	order, err := s.findOrder(orderIDStr)
+	if err != nil {
+		return nil, "", err
+	}
+	if order.CustomerID.String() == requestingUserIDStr {
+		return order, authz.RoleCustomer, nil
+	}
+
+	role, err := s.authorize(requestingUserIDStr, order.ShopID.String(), authz.PermShopOrdersRead)
+	if err != nil {
+		return nil, "", err
+	}
+	return order, role, nil
+}
+
+func (s *orderService) findOrder(orderIDStr string) (*domain.Order, error) {
+	if _, err := uuid.Parse(orderIDStr); err != nil {
+		return nil, domain.ErrOrderNotFound
+	}
+	order, err := s.repo.FindByID(orderIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, domain.ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// findShopOrder hides orders of other shops behind ErrOrderNotFound.
+func (s *orderService) findShopOrder(shopIDStr, orderIDStr string) (*domain.Order, error) {
+	order, err := s.findOrder(orderIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if order.ShopID.String() != shopIDStr {
+		return nil, domain.ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// authorize checks perm against the role the user holds within the shop and returns that role.
+func (s *orderService) authorize(userIDStr, shopIDStr string, perm authz.Permission) (authz.Role, error) {
+	if _, err := uuid.Parse(shopIDStr); err != nil {
+		return "", domain.ErrShopNotFound
+	}
+	role, err := s.memberships.MemberRole(userIDStr, shopIDStr)
+	if err != nil {
+		return "", err
+	}
+	if role == "" || !authz.Can(role, perm) {
+		return "", domain.ErrForbidden
+	}
+	return role, nil
+}
+
+// mergeItems validates the requested lines and adds up repeated products.
+func mergeItems(items []ItemRequest) (map[uuid.UUID]int, []uuid.UUID, error) {
+	if len(items) == 0 {
+		return nil, nil, domain.ErrEmptyOrder
+	}
+
+	quantities := make(map[uuid.UUID]int, len(items))
+	var productIDs []uuid.UUID
+	for _, item := range items {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", domain.ErrProductNotFound, item.ProductID)
+		}
+		if item.Quantity <= 0 {
+			return nil, nil, domain.ErrInvalidQuantity
+		}
+		if _, seen := quantities[productID]; !seen {
+			productIDs = append(productIDs, productID)
+		}
+		quantities[productID] += item.Quantity
+	}
+	return quantities, productIDs, nil
+}
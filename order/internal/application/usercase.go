@@ -0,0 +1,88 @@
+package application
+
+import (
+	"io"
+	"miniature/order/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ItemRequest is a product and quantity a customer wants to order.
+type ItemRequest struct {
+	ProductID string
+	Quantity  int
+}
+
+// CodeItemRequest is a product, given by its shop code, and quantity ordered through a chat channel.
+type CodeItemRequest struct {
+	Code     string
+	Quantity int
+}
+
+// Timeline is an order's status history together with the moves the viewer can make next.
+type Timeline struct {
+	OrderID      uuid.UUID              `json:"order_id"`
+	Status       domain.Status          `json:"status"`
+	NextStatuses []domain.Status        `json:"next_statuses"`
+	History      []*domain.StatusChange `json:"history"`
+}
+
+// CashbackSummary is a customer's cashback balance and ledger.
+type CashbackSummary struct {
+	Balance float64                 `json:"balance"`
+	Entries []*domain.CashbackEntry `json:"entries"`
+}
+
+type Usecase interface {
+	PlaceOrder(customerIDStr, shopIDStr string, items []ItemRequest, deliveryAddress string, cashback float64) (*domain.Order, error)
+	GetOrder(orderIDStr, requestingUserIDStr string) (*domain.Order, error)
+	PlaceChannelOrder(shopIDStr, customerPhone string, items []CodeItemRequest, deliveryAddress string) (*domain.Order, error)
+	GetChannelOrder(orderIDStr string) (*domain.Order, error)
+	CreateChannelDraft(shopIDStr, channel, customerPhone, deliveryAddress, text string) (*domain.DraftOrder, error)
+	GetChannelDraft(draftIDStr string) (*domain.DraftOrder, error)
+	GetCustomerOrders(customerIDStr string) ([]*domain.Order, error)
+	ChangeOrderStatus(orderIDStr, customerIDStr string, status domain.Status, note string) (*domain.Order, error)
+	GetOrderTimeline(orderIDStr, requestingUserIDStr string) (*Timeline, error)
+	GetShopOrders(shopIDStr, requestingUserIDStr string, status domain.Status) ([]*domain.Order, error)
+	GetShopOrder(shopIDStr, orderIDStr, requestingUserIDStr string) (*domain.Order, error)
+	UpdateShopOrder(shopIDStr, orderIDStr, requestingUserIDStr string, status *domain.Status, deliveryEstimate *string, note string) (*domain.Order, error)
+	GetShopOrderTimeline(shopIDStr, orderIDStr, requestingUserIDStr string) (*Timeline, error)
+
+	SubmitPaymentProof(orderIDStr, customerIDStr string, file io.Reader, size int64) (*domain.Order, error)
+	// GetPaymentProof and GetShopPaymentProof return the receipt and its content type. The caller closes it.
+	GetPaymentProof(orderIDStr, requestingUserIDStr string) (io.ReadCloser, string, error)
+	GetShopPaymentProof(shopIDStr, orderIDStr, requestingUserIDStr string) (io.ReadCloser, string, error)
+	GetPaymentQueue(shopIDStr, requestingUserIDStr string) ([]*domain.Order, error)
+	ApprovePayment(shopIDStr, orderIDStr, requestingUserIDStr string) (*domain.Order, error)
+	RejectPayment(shopIDStr, orderIDStr, requestingUserIDStr, reason string) (*domain.Order, error)
+
+	GetCashback(customerIDStr string) (*CashbackSummary, error)
+	GetCashbackSettings(shopIDStr, requestingUserIDStr string) (*domain.CashbackSettings, error)
+	UpdateCashbackSettings(shopIDStr, requestingUserIDStr string, percent float64, expiryDays int) (*domain.CashbackSettings, error)
+
+	OpenCart(shopIDStr, requestingUserIDStr string) (*domain.CartSession, error)
+	GetCart(shopIDStr, sessionIDStr, requestingUserIDStr string) (*domain.CartSession, error)
+	ScanCartItem(shopIDStr, sessionIDStr, requestingUserIDStr, code, qr string, quantity int) (*domain.CartSession, error)
+	SetCartItemQuantity(shopIDStr, sessionIDStr, itemIDStr, requestingUserIDStr string, quantity int) (*domain.CartSession, error)
+	AbandonCart(shopIDStr, sessionIDStr, requestingUserIDStr string) error
+	CheckoutCart(shopIDStr, sessionIDStr, requestingUserIDStr, customerPhone string, parts []PaymentPart) (*Receipt, error)
+
+	CreateShopDraft(shopIDStr, requestingUserIDStr, customerPhone, deliveryAddress, text string) (*domain.DraftOrder, error)
+	GetShopDrafts(shopIDStr, requestingUserIDStr string, status domain.DraftStatus) ([]*domain.DraftOrder, error)
+	GetShopDraft(shopIDStr, draftIDStr, requestingUserIDStr string) (*domain.DraftOrder, error)
+	UpdateShopDraft(shopIDStr, draftIDStr, requestingUserIDStr string, update DraftUpdate) (*domain.DraftOrder, error)
+	AcceptShopDraft(shopIDStr, draftIDStr, requestingUserIDStr string) (*domain.Order, error)
+	DiscardShopDraft(shopIDStr, draftIDStr, requestingUserIDStr string) error
+
+	GetSalesReport(shopIDStr, requestingUserIDStr string, r domain.ReportRange) (*domain.SalesReport, error)
+	GetTopProducts(shopIDStr, requestingUserIDStr string, r domain.ReportRange, by domain.ProductRanking, limit int) (*domain.TopProductsReport, error)
+	GetCustomersReport(shopIDStr, requestingUserIDStr string, r domain.ReportRange) (*domain.CustomersReport, error)
+	GetBestSellers(shopIDStr, requestingUserIDStr string, by domain.ProductRanking, limit int) ([]*domain.ProductStats, error)
+
+	ExportShopData(shopIDStr, requestingUserIDStr string, options domain.ExportOptions, w io.Writer) error
+	CreateExportJob(shopIDStr, requestingUserIDStr string, options domain.ExportOptions) (*domain.ExportJob, error)
+	GetExportJobs(shopIDStr, requestingUserIDStr string) ([]*domain.ExportJob, error)
+	GetExportJob(shopIDStr, jobIDStr, requestingUserIDStr string) (*domain.ExportJob, error)
+	// DownloadExport returns the file of a finished export job. The caller closes it.
+	DownloadExport(shopIDStr, jobIDStr, requestingUserIDStr string) (io.ReadCloser, *domain.ExportJob, error)
+}
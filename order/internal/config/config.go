@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// Payment proof uploads.
+var (
+	MaxPaymentProofSize int64 = 5 << 20 // 5 MB
+
+	StorageBackend  = "local"   // "local" or "s3"
+	LocalStorageDir = "uploads" // root directory of the local backend
+
+	// S3-compatible backend (AWS S3, MinIO, ArvanCloud, ...). Objects are addressed path-style.
+	S3Endpoint  = "http://localhost:9000"
+	S3Region    = "us-east-1"
+	S3Bucket    = "miniature"
+	S3AccessKey = os.Getenv("ORDER_S3_ACCESS_KEY")
+	S3SecretKey = os.Getenv("ORDER_S3_SECRET_KEY")
+)
+
+// Cashback background jobs. They run on every instance but only one at a time does the work.
+var (
+	CashbackJobInterval   = time.Hour
+	CashbackExpiryWarning = time.Hour * 24 * 3 // warn customers this long before their cashback expires
+	CashbackJobBatchSize  = 500
+)
+
+// In-store cart sessions. Scanned stock is held for CartSessionTTL after the last change.
+var (
+	CartSessionTTL     = time.Minute * 30
+	CartSweepInterval  = time.Minute
+	CartSweepBatchSize = 100
+)
+
+// Data exports. Larger exports than MaxDirectExportRows rows are only written by background jobs,
+// which every instance polls for.
+var (
+	MaxDirectExportRows = 20000
+	ExportJobInterval   = time.Second * 10
+	ExportJobTimeout    = time.Hour // a job running longer is taken to have died with its instance and is run again
+)
+
+// Chat channels (Telegram and other bots) place orders on behalf of customers with this key in the
+// X-Channel-Key header. Channel endpoints are disabled while it is empty.
+var ChannelAPIKey = os.Getenv("ORDER_CHANNEL_API_KEY")
@@ -0,0 +1,370 @@
+package domain
+
+import (
+	"miniature/pkg/jalali"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SaleStatuses are the statuses an order counts as a sale in: it has been paid and not cancelled.
+var SaleStatuses = []Status{StatusPaid, StatusConfirmed, StatusShipped, StatusDelivered}
+
+func (s Status) IsSale() bool {
+	return slices.Contains(SaleStatuses, s)
+}
+
+// Grouping is the length of the periods a report's series is broken into.
+type Grouping string
+
+const (
+	GroupByDay   Grouping = "day"
+	GroupByWeek  Grouping = "week"
+	GroupByMonth Grouping = "month"
+)
+
+func ParseGrouping(s string) (Grouping, error) {
+	switch grouping := Grouping(s); grouping {
+	case GroupByDay, GroupByWeek, GroupByMonth:
+		return grouping, nil
+	default:
+		return "", ErrInvalidGrouping
+	}
+}
+
+// maxReportRange keeps a daily series to a reasonable length.
+const maxReportRange = 2 * 366 * 24 * time.Hour
+
+// ReportRange is the interval [From, To) a report covers. Periods start at midnight in From's
+// location, and months are those of Calendar.
+type ReportRange struct {
+	From     time.Time
+	To       time.Time
+	GroupBy  Grouping
+	Calendar jalali.Calendar
+}
+
+func NewReportRange(from, to time.Time, groupBy Grouping, calendar jalali.Calendar) (ReportRange, error) {
+	if !to.After(from) {
+		return ReportRange{}, ErrInvalidDateRange
+	}
+	if to.Sub(from) > maxReportRange {
+		return ReportRange{}, ErrDateRangeTooLong
+	}
+	return ReportRange{From: from, To: to, GroupBy: groupBy, Calendar: calendar}, nil
+}
+
+// PeriodStart returns the start of the period t falls in. Weeks start on Saturday, as they do in Iran.
+func (r ReportRange) PeriodStart(t time.Time) time.Time {
+	t = t.In(r.From.Location())
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch r.GroupBy {
+	case GroupByWeek:
+		return jalali.WeekStart(day)
+	case GroupByMonth:
+		if r.Calendar == jalali.Jalali {
+			return jalali.MonthStart(day)
+		}
+		return day.AddDate(0, 0, 1-day.Day())
+	default:
+		return day
+	}
+}
+
+// Periods returns the start of every period the range touches; the first may be before From.
+func (r ReportRange) Periods() []time.Time {
+	var periods []time.Time
+	for start := r.PeriodStart(r.From); start.Before(r.To); start = r.next(start) {
+		periods = append(periods, start)
+	}
+	return periods
+}
+
+func (r ReportRange) next(start time.Time) time.Time {
+	switch r.GroupBy {
+	case GroupByWeek:
+		return start.AddDate(0, 0, 7)
+	case GroupByMonth:
+		if r.Calendar == jalali.Jalali {
+			return jalali.NextMonthStart(start)
+		}
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// Label writes the day a period starts on in the range's calendar, e.g. 2024-03-20 or 1403/01/01.
+func (r ReportRange) Label(start time.Time) string {
+	if r.Calendar == jalali.Jalali {
+		return jalali.FromTime(start).String()
+	}
+	return start.Format("2006-01-02")
+}
+
+// periodIndex maps the start of every period to its position in Periods.
+func (r ReportRange) periodIndex() ([]time.Time, map[int64]int) {
+	periods := r.Periods()
+	index := make(map[int64]int, len(periods))
+	for i, start := range periods {
+		index[start.Unix()] = i
+	}
+	return periods, index
+}
+
+// Sale is an order counted by the reports.
+type Sale struct {
+	OrderID    uuid.UUID
+	CustomerID uuid.UUID // uuid.Nil for a walk-in sale
+	Total      float64
+	CreatedAt  time.Time
+	// FirstSaleAt is when the customer's first sale at the shop was made, whether in the range or not.
+	FirstSaleAt time.Time
+}
+
+// SoldItem is an order line of a Sale.
+type SoldItem struct {
+	ProductID   uuid.UUID
+	ProductName string
+	Quantity    int
+	Revenue     float64
+	SoldAt      time.Time
+}
+
+type SalesPoint struct {
+	PeriodStart       time.Time `json:"period_start"`
+	Period            string    `json:"period"`
+	Revenue           float64   `json:"revenue"`
+	Orders            int       `json:"orders"`
+	AverageOrderValue float64   `json:"average_order_value"`
+}
+
+// SalesReport is a shop's revenue, order count and average order value over a range. Revenue is
+// what the orders were sold for, including any part paid with cashback.
+type SalesReport struct {
+	From              time.Time       `json:"from"`
+	To                time.Time       `json:"to"`
+	GroupBy           Grouping        `json:"group_by"`
+	Calendar          jalali.Calendar `json:"calendar"`
+	Revenue           float64         `json:"revenue"`
+	Orders            int             `json:"orders"`
+	AverageOrderValue float64         `json:"average_order_value"`
+	Series            []*SalesPoint   `json:"series"`
+}
+
+func NewSalesReport(r ReportRange, sales []*Sale) *SalesReport {
+	periods, index := r.periodIndex()
+	report := &SalesReport{
+		From: r.From, To: r.To, GroupBy: r.GroupBy, Calendar: r.Calendar,
+		Series: make([]*SalesPoint, len(periods)),
+	}
+	for i, start := range periods {
+		report.Series[i] = &SalesPoint{PeriodStart: start, Period: r.Label(start)}
+	}
+
+	for _, sale := range sales {
+		point := report.Series[index[r.PeriodStart(sale.CreatedAt).Unix()]]
+		point.Revenue += sale.Total
+		point.Orders++
+		report.Revenue += sale.Total
+		report.Orders++
+	}
+
+	for _, point := range report.Series {
+		point.Revenue = roundMoney(point.Revenue)
+		point.AverageOrderValue = averageOrderValue(point.Revenue, point.Orders)
+	}
+	report.Revenue = roundMoney(report.Revenue)
+	report.AverageOrderValue = averageOrderValue(report.Revenue, report.Orders)
+	return report
+}
+
+func averageOrderValue(revenue float64, orders int) float64 {
+	if orders == 0 {
+		return 0
+	}
+	return roundMoney(revenue / float64(orders))
+}
+
+// ProductRanking is what top products are ranked by.
+type ProductRanking string
+
+const (
+	RankByUnits   ProductRanking = "units"
+	RankByRevenue ProductRanking = "revenue"
+)
+
+func ParseProductRanking(s string) (ProductRanking, error) {
+	switch ranking := ProductRanking(s); ranking {
+	case RankByUnits, RankByRevenue:
+		return ranking, nil
+	default:
+		return "", ErrInvalidRanking
+	}
+}
+
+type ProductPoint struct {
+	PeriodStart time.Time `json:"period_start"`
+	Period      string    `json:"period"`
+	Units       int       `json:"units"`
+	Revenue     float64   `json:"revenue"`
+}
+
+type ProductSales struct {
+	ProductID   uuid.UUID       `json:"product_id"`
+	ProductName string          `json:"product_name"`
+	Units       int             `json:"units"`
+	Revenue     float64         `json:"revenue"`
+	Series      []*ProductPoint `json:"series"`
+}
+
+// TopProductsReport is a shop's best-selling products over a range, best first.
+type TopProductsReport struct {
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	GroupBy  Grouping        `json:"group_by"`
+	Calendar jalali.Calendar `json:"calendar"`
+	RankedBy ProductRanking  `json:"ranked_by"`
+	Products []*ProductSales `json:"products"`
+}
+
+func NewTopProductsReport(r ReportRange, items []*SoldItem, by ProductRanking, limit int) *TopProductsReport {
+	periods, index := r.periodIndex()
+	byProduct := map[uuid.UUID]*ProductSales{}
+	for _, item := range items {
+		product, ok := byProduct[item.ProductID]
+		if !ok {
+			product = &ProductSales{ProductID: item.ProductID, ProductName: item.ProductName}
+			product.Series = make([]*ProductPoint, len(periods))
+			for i, start := range periods {
+				product.Series[i] = &ProductPoint{PeriodStart: start, Period: r.Label(start)}
+			}
+			byProduct[item.ProductID] = product
+		}
+		point := product.Series[index[r.PeriodStart(item.SoldAt).Unix()]]
+		point.Units += item.Quantity
+		point.Revenue += item.Revenue
+		product.Units += item.Quantity
+		product.Revenue += item.Revenue
+	}
+
+	products := make([]*ProductSales, 0, len(byProduct))
+	for _, product := range byProduct {
+		product.Revenue = roundMoney(product.Revenue)
+		for _, point := range product.Series {
+			point.Revenue = roundMoney(point.Revenue)
+		}
+		products = append(products, product)
+	}
+	sort.Slice(products, func(i, j int) bool {
+		a, b := products[i], products[j]
+		if by == RankByRevenue && a.Revenue != b.Revenue {
+			return a.Revenue > b.Revenue
+		}
+		if a.Units != b.Units {
+			return a.Units > b.Units
+		}
+		if a.Revenue != b.Revenue {
+			return a.Revenue > b.Revenue
+		}
+		return a.ProductName < b.ProductName
+	})
+	if len(products) > limit {
+		products = products[:limit]
+	}
+
+	return &TopProductsReport{
+		From: r.From, To: r.To, GroupBy: r.GroupBy, Calendar: r.Calendar,
+		RankedBy: by, Products: products,
+	}
+}
+
+// CustomerPoint counts the distinct customers who bought in a period. A customer is new in the
+// period their first sale at the shop was made in and returning in any later one.
+type CustomerPoint struct {
+	PeriodStart              time.Time `json:"period_start"`
+	Period                   string    `json:"period"`
+	NewCustomers             int       `json:"new_customers"`
+	ReturningCustomers       int       `json:"returning_customers"`
+	NewCustomerRevenue       float64   `json:"new_customer_revenue"`
+	ReturningCustomerRevenue float64   `json:"returning_customer_revenue"`
+	WalkInOrders             int       `json:"walk_in_orders"`
+}
+
+// CustomersReport compares new and returning customers over a range. Over the whole range, a
+// customer is new if their first sale at the shop was made within it. Walk-in sales have no
+// customer and are only counted.
+type CustomersReport struct {
+	From               time.Time        `json:"from"`
+	To                 time.Time        `json:"to"`
+	GroupBy            Grouping         `json:"group_by"`
+	Calendar           jalali.Calendar  `json:"calendar"`
+	NewCustomers       int              `json:"new_customers"`
+	ReturningCustomers int              `json:"returning_customers"`
+	WalkInOrders       int              `json:"walk_in_orders"`
+	Series             []*CustomerPoint `json:"series"`
+}
+
+func NewCustomersReport(r ReportRange, sales []*Sale) *CustomersReport {
+	periods, index := r.periodIndex()
+	report := &CustomersReport{
+		From: r.From, To: r.To, GroupBy: r.GroupBy, Calendar: r.Calendar,
+		Series: make([]*CustomerPoint, len(periods)),
+	}
+	for i, start := range periods {
+		report.Series[i] = &CustomerPoint{PeriodStart: start, Period: r.Label(start)}
+	}
+
+	type seenKey struct {
+		period     int
+		customerID uuid.UUID
+	}
+	seenInPeriod := map[seenKey]bool{}
+	seenInRange := map[uuid.UUID]bool{}
+	for _, sale := range sales {
+		i := index[r.PeriodStart(sale.CreatedAt).Unix()]
+		point := report.Series[i]
+		if sale.CustomerID == uuid.Nil {
+			point.WalkInOrders++
+			report.WalkInOrders++
+			continue
+		}
+
+		// The first period may start before the range; customers are only new within the range
+		start := point.PeriodStart
+		if start.Before(r.From) {
+			start = r.From
+		}
+		isNew := !sale.FirstSaleAt.Before(start)
+		if isNew {
+			point.NewCustomerRevenue += sale.Total
+		} else {
+			point.ReturningCustomerRevenue += sale.Total
+		}
+		if key := (seenKey{i, sale.CustomerID}); !seenInPeriod[key] {
+			seenInPeriod[key] = true
+			if isNew {
+				point.NewCustomers++
+			} else {
+				point.ReturningCustomers++
+			}
+		}
+
+		if !seenInRange[sale.CustomerID] {
+			seenInRange[sale.CustomerID] = true
+			if sale.FirstSaleAt.Before(r.From) {
+				report.ReturningCustomers++
+			} else {
+				report.NewCustomers++
+			}
+		}
+	}
+
+	for _, point := range report.Series {
+		point.NewCustomerRevenue = roundMoney(point.NewCustomerRevenue)
+		point.ReturningCustomerRevenue = roundMoney(point.ReturningCustomerRevenue)
+	}
+	return report
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CartStatus is the state of an in-store cart session.
+type CartStatus string
+
+const (
+	CartOpen       CartStatus = "OPEN"
+	CartCheckedOut CartStatus = "CHECKED_OUT"
+	CartAbandoned  CartStatus = "ABANDONED"
+	CartExpired    CartStatus = "EXPIRED" // released by the sweeper after ExpiresAt passed
+)
+
+// CartSession is a cart built at the counter by scanning products. Scanned quantities are taken out
+// of stock straight away and put back if the session is abandoned or expires before checkout.
+type CartSession struct {
+	ID        uuid.UUID   `json:"id"`
+	ShopID    uuid.UUID   `json:"shop_id"`
+	OpenedBy  uuid.UUID   `json:"opened_by"`
+	Status    CartStatus  `json:"status"`
+	ExpiresAt time.Time   `json:"expires_at"`
+	CreatedAt time.Time   `json:"created_at"`
+	Items     []*CartItem `json:"items"`
+}
+
+// CartItem is a scanned product in a cart session. Code and UnitPrice are read from the product.
+type CartItem struct {
+	ID          uuid.UUID `json:"id"`
+	SessionID   uuid.UUID `json:"session_id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	Code        string    `json:"code"`
+	UnitPrice   float64   `json:"unit_price"`
+	Quantity    int       `json:"quantity"`
+	ScannedAt   time.Time `json:"scanned_at"`
+	IsFinalized bool      `json:"is_finalized"`
+}
+
+// EnsureOpen reports whether items can still be changed at now.
+func (s *CartSession) EnsureOpen(now time.Time) error {
+	if s.Status != CartOpen {
+		return ErrCartClosed
+	}
+	if !now.Before(s.ExpiresAt) {
+		return ErrCartExpired
+	}
+	return nil
+}
+
+// Total is the price of everything in the cart.
+func (s *CartSession) Total() float64 {
+	var total float64
+	for _, item := range s.Items {
+		total += item.UnitPrice * float64(item.Quantity)
+	}
+	return roundMoney(total)
+}
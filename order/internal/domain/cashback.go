@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CashbackEntryType is the kind of movement recorded in the cashback ledger.
+type CashbackEntryType string
+
+const (
+	CashbackEarned   CashbackEntryType = "EARNED"
+	CashbackUsed     CashbackEntryType = "USED"
+	CashbackRefunded CashbackEntryType = "REFUNDED" // cashback spent on an order that was then cancelled
+	CashbackExpired  CashbackEntryType = "EXPIRED"  // the unspent rest of an EARNED credit past its expiry
+)
+
+// CashbackEntry is a row of the append-only cashback ledger. Amount is always positive; Type gives
+// the direction. BalanceAfter is the customer's balance once the entry was applied.
+//
+// Credits (EARNED, REFUNDED) are spent oldest first: every USED or EXPIRED entry records which
+// credits it consumed, and Remaining is what is left of a credit. Only EARNED credits expire.
+type CashbackEntry struct {
+	ID             uuid.UUID         `json:"id"`
+	CustomerID     uuid.UUID         `json:"customer_id"`
+	ShopID         uuid.UUID         `json:"shop_id"`
+	RelatedOrderID *uuid.UUID        `json:"related_order_id"`
+	Type           CashbackEntryType `json:"type"`
+	Amount         float64           `json:"amount"`
+	BalanceAfter   float64           `json:"balance_after"`
+	ExpiresAt      *time.Time        `json:"expires_at,omitempty"`
+	Remaining      float64           `json:"remaining,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+// IsCredit reports whether the entry adds to the balance.
+func (e *CashbackEntry) IsCredit() bool {
+	return e.Type == CashbackEarned || e.Type == CashbackRefunded
+}
+
+// Delta is the change the entry makes to the customer's balance.
+func (e *CashbackEntry) Delta() float64 {
+	if e.IsCredit() {
+		return e.Amount
+	}
+	return -e.Amount
+}
+
+// Expire returns the entry writing off amount of the credit, which the caller has found unspent past its expiry.
+func (e *CashbackEntry) Expire(amount float64) *CashbackEntry {
+	return &CashbackEntry{
+		ID:         uuid.New(),
+		CustomerID: e.CustomerID,
+		ShopID:     e.ShopID,
+		Type:       CashbackExpired,
+		Amount:     roundMoney(amount),
+		CreatedAt:  time.Now(),
+	}
+}
+
+// CashbackSettings is a shop's loyalty configuration. Percent of the amount a customer paid is
+// credited back when the order is delivered, and expires after ExpiryDays unless that is zero.
+type CashbackSettings struct {
+	ShopID     uuid.UUID `json:"shop_id"`
+	Percent    float64   `json:"percent"`
+	ExpiryDays int       `json:"expiry_days"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (s *CashbackSettings) Validate() error {
+	if s.Percent < 0 || s.Percent > 100 {
+		return ErrInvalidCashbackPercent
+	}
+	if s.ExpiryDays < 0 {
+		return ErrInvalidCashbackExpiry
+	}
+	return nil
+}
+
+// AmountDue is what the customer pays once their cashback is deducted.
+func (o *Order) AmountDue() float64 {
+	return roundMoney(o.TotalAmount - o.CashbackApplied)
+}
+
+// ApplyCashback spends amount of the customer's balance on the order and returns the ledger entry.
+func (o *Order) ApplyCashback(amount float64) (*CashbackEntry, error) {
+	amount = roundMoney(amount)
+	if amount < 0 {
+		return nil, ErrInvalidCashbackAmount
+	}
+	if amount > o.TotalAmount {
+		return nil, ErrCashbackExceedsTotal
+	}
+	if amount == 0 {
+		return nil, nil
+	}
+
+	o.CashbackApplied = amount
+	return o.cashbackEntry(CashbackUsed, amount), nil
+}
+
+// EarnCashback returns the credit for a delivered order under the shop's settings, or nil if there is none.
+func (o *Order) EarnCashback(settings *CashbackSettings) *CashbackEntry {
+	if settings == nil || o.CustomerID == uuid.Nil {
+		return nil
+	}
+	amount := roundMoney(o.AmountDue() * settings.Percent / 100)
+	if amount <= 0 {
+		return nil
+	}
+
+	entry := o.cashbackEntry(CashbackEarned, amount)
+	if settings.ExpiryDays > 0 {
+		expiresAt := entry.CreatedAt.AddDate(0, 0, settings.ExpiryDays)
+		entry.ExpiresAt = &expiresAt
+	}
+	return entry
+}
+
+// RefundCashback returns the entry giving back the cashback spent on a cancelled order, or nil if none was spent.
+func (o *Order) RefundCashback() *CashbackEntry {
+	if o.CashbackApplied <= 0 {
+		return nil
+	}
+	return o.cashbackEntry(CashbackRefunded, o.CashbackApplied)
+}
+
+func (o *Order) cashbackEntry(entryType CashbackEntryType, amount float64) *CashbackEntry {
+	orderID := o.ID
+	return &CashbackEntry{
+		ID:             uuid.New(),
+		CustomerID:     o.CustomerID,
+		ShopID:         o.ShopID,
+		RelatedOrderID: &orderID,
+		Type:           entryType,
+		Amount:         amount,
+		CreatedAt:      time.Now(),
+	}
+}
+
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"fmt"
+	"miniature/pkg/orderparse"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DraftStatus is the state of a draft order.
+type DraftStatus string
+
+const (
+	DraftOpen      DraftStatus = "OPEN"
+	DraftAccepted  DraftStatus = "ACCEPTED" // turned into the order in OrderID
+	DraftDiscarded DraftStatus = "DISCARDED"
+)
+
+func ParseDraftStatus(s string) (DraftStatus, error) {
+	switch status := DraftStatus(s); status {
+	case DraftOpen, DraftAccepted, DraftDiscarded:
+		return status, nil
+	default:
+		return "", ErrInvalidDraftStatus
+	}
+}
+
+// DraftOrder is an order read out of a customer's free-text message. Nothing is taken from stock
+// until the seller has reviewed the lines and accepts it.
+type DraftOrder struct {
+	ID              uuid.UUID    `json:"id"`
+	ShopID          uuid.UUID    `json:"shop_id"`
+	Channel         string       `json:"channel"` // where the text came from, e.g. TELEGRAM; empty if a seller pasted it
+	SourceText      string       `json:"source_text"`
+	CustomerPhone   string       `json:"customer_phone"`
+	DeliveryAddress string       `json:"delivery_address"`
+	Status          DraftStatus  `json:"status"`
+	OrderID         *uuid.UUID   `json:"order_id,omitempty"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+	Lines           []*DraftLine `json:"lines"`
+}
+
+// DraftLine is one product asked for in a draft. RequestedCode is the code as the customer wrote
+// it; the product fields are empty if Match is NONE.
+type DraftLine struct {
+	ID            uuid.UUID            `json:"id"`
+	DraftID       uuid.UUID            `json:"draft_id"`
+	RequestedCode string               `json:"requested_code"`
+	Match         orderparse.MatchKind `json:"match"`
+	ProductID     *uuid.UUID           `json:"product_id"`
+	ProductCode   string               `json:"product_code"`
+	ProductName   string               `json:"product_name"`
+	UnitPrice     float64              `json:"unit_price"`
+	Quantity      int                  `json:"quantity"`
+	Variants      map[string]string    `json:"variants,omitempty"`
+}
+
+func (d *DraftOrder) EnsureOpen() error {
+	if d.Status != DraftOpen {
+		return ErrDraftClosed
+	}
+	return nil
+}
+
+// EnsureReady reports whether the draft can be accepted as it is.
+func (d *DraftOrder) EnsureReady() error {
+	if err := d.EnsureOpen(); err != nil {
+		return err
+	}
+	if len(d.Lines) == 0 {
+		return ErrEmptyOrder
+	}
+	for _, line := range d.Lines {
+		if line.ProductID == nil {
+			return fmt.Errorf("%w: %s", ErrDraftUnmatched, line.RequestedCode)
+		}
+	}
+	if d.CustomerPhone == "" {
+		return ErrDraftPhoneRequired
+	}
+	return nil
+}
+
+// VariantNote lists the variants the customer asked for, e.g. "SL-38 ×2: size 38", since order
+// items have no variants of their own. It is empty if no line has any.
+func (d *DraftOrder) VariantNote() string {
+	var parts []string
+	for _, line := range d.Lines {
+		if len(line.Variants) == 0 {
+			continue
+		}
+		keys := make([]string, 0, len(line.Variants))
+		for key := range line.Variants {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		variants := make([]string, 0, len(keys))
+		for _, key := range keys {
+			variants = append(variants, key+" "+line.Variants[key])
+		}
+		parts = append(parts, fmt.Sprintf("%s ×%d: %s", line.ProductCode, line.Quantity, strings.Join(variants, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,62 @@
+package domain
+
+import "errors"
+
+var (
+	ErrOrderNotFound        = errors.New("order not found")
+	ErrShopNotFound         = errors.New("shop not found")
+	ErrForbidden            = errors.New("user is not authorized to access this order")
+	ErrEmptyOrder           = errors.New("order must contain at least one item")
+	ErrInvalidQuantity      = errors.New("quantity must be greater than zero")
+	ErrProductNotFound      = errors.New("product not found in this shop")
+	ErrProductUnavailable   = errors.New("product is not available")
+	ErrOutOfStock           = errors.New("not enough stock")
+	ErrInvalidStatus        = errors.New("invalid order status")
+	ErrInvalidTransition    = errors.New("invalid order status transition")
+	ErrTransitionNotAllowed = errors.New("status change not allowed for this role")
+	ErrStatusConflict       = errors.New("order status was changed by another request")
+
+	ErrPaymentNotExpected      = errors.New("order is not waiting for a payment proof")
+	ErrNoPaymentToReview       = errors.New("order has no submitted payment proof to review")
+	ErrRejectionReasonRequired = errors.New("a reason is required to reject a payment")
+	ErrInvalidPaymentProof     = errors.New("payment proof must be a JPEG, PNG or WebP image or a PDF")
+	ErrPaymentProofTooLarge    = errors.New("payment proof is too large")
+	ErrFileNotFound            = errors.New("file not found")
+
+	ErrInvalidCashbackAmount  = errors.New("cashback amount cannot be negative")
+	ErrCashbackExceedsTotal   = errors.New("cashback amount exceeds the order total")
+	ErrInsufficientCashback   = errors.New("not enough cashback balance")
+	ErrInvalidCashbackPercent = errors.New("cashback percent must be between 0 and 100")
+	ErrInvalidCashbackExpiry  = errors.New("cashback expiry days cannot be negative")
+
+	ErrCartNotFound     = errors.New("cart session not found")
+	ErrCartItemNotFound = errors.New("cart item not found")
+	ErrCartClosed       = errors.New("cart session is no longer open")
+	ErrCartExpired      = errors.New("cart session has expired")
+	ErrScanRequired     = errors.New("either a product code or a QR payload is required")
+
+	ErrInvalidPaymentMethod  = errors.New("payment method must be CASH, CARD or CASHBACK")
+	ErrInvalidPaymentAmount  = errors.New("payment amounts must be greater than zero")
+	ErrPaymentMismatch       = errors.New("payments do not add up to the cart total")
+	ErrCashbackNeedsCustomer = errors.New("paying with cashback requires a customer")
+	ErrCustomerNotFound      = errors.New("no customer with this phone number")
+
+	ErrDraftNotFound      = errors.New("draft order not found")
+	ErrDraftClosed        = errors.New("draft order was already accepted or discarded")
+	ErrDraftUnmatched     = errors.New("draft order has a line that matches no product")
+	ErrDraftPhoneRequired = errors.New("a customer phone number is required to accept a draft order")
+	ErrInvalidDraftStatus = errors.New("invalid draft order status")
+	ErrDraftTextRequired  = errors.New("text is required")
+
+	ErrInvalidGrouping  = errors.New("group_by must be day, week or month")
+	ErrInvalidRanking   = errors.New("by must be units or revenue")
+	ErrInvalidDateRange = errors.New("from must be a date before to")
+	ErrDateRangeTooLong = errors.New("date range cannot be longer than two years")
+
+	ErrInvalidExportDataset = errors.New("dataset must be products, orders, customers or cashback")
+	ErrInvalidExportFormat  = errors.New("format must be csv or xlsx")
+	ErrInvalidExportHeaders = errors.New("headers must be en or fa")
+	ErrExportTooLarge       = errors.New("export is too large to download directly, create an export job instead")
+	ErrExportJobNotFound    = errors.New("export job not found")
+	ErrExportNotReady       = errors.New("export job has not finished")
+)
@@ -0,0 +1,297 @@
+package domain
+
+import (
+	"fmt"
+	"miniature/pkg/jalali"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportDataset is the part of a shop's data an export holds.
+type ExportDataset string
+
+const (
+	ExportProducts  ExportDataset = "products"
+	ExportOrders    ExportDataset = "orders" // one row per order item, with the order repeated on each
+	ExportCustomers ExportDataset = "customers"
+	ExportCashback  ExportDataset = "cashback"
+)
+
+func ParseExportDataset(s string) (ExportDataset, error) {
+	switch dataset := ExportDataset(s); dataset {
+	case ExportProducts, ExportOrders, ExportCustomers, ExportCashback:
+		return dataset, nil
+	default:
+		return "", ErrInvalidExportDataset
+	}
+}
+
+// ExportFormat is the file format of an export.
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportXLSX ExportFormat = "xlsx"
+)
+
+// ParseExportFormat reads a format name. An empty name means CSV.
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch format := ExportFormat(s); format {
+	case "":
+		return ExportCSV, nil
+	case ExportCSV, ExportXLSX:
+		return format, nil
+	default:
+		return "", ErrInvalidExportFormat
+	}
+}
+
+func (f ExportFormat) ContentType() string {
+	if f == ExportXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// HeaderLanguage is the language of an export's column names.
+type HeaderLanguage string
+
+const (
+	HeadersEnglish HeaderLanguage = "en"
+	HeadersPersian HeaderLanguage = "fa"
+)
+
+// ParseHeaderLanguage reads a language code. An empty code means English.
+func ParseHeaderLanguage(s string) (HeaderLanguage, error) {
+	switch language := HeaderLanguage(s); language {
+	case "":
+		return HeadersEnglish, nil
+	case HeadersEnglish, HeadersPersian:
+		return language, nil
+	default:
+		return "", ErrInvalidExportHeaders
+	}
+}
+
+// ExportOptions say what an export holds and how it is written. From and To, when set, bound when
+// the exported orders were placed and ledger entries made; customers are those who bought in the
+// range, and products are always exported whole.
+type ExportOptions struct {
+	Dataset  ExportDataset   `json:"dataset"`
+	Format   ExportFormat    `json:"format"`
+	Headers  HeaderLanguage  `json:"headers"`
+	Calendar jalali.Calendar `json:"calendar"`
+	From     *time.Time      `json:"from,omitempty"`
+	To       *time.Time      `json:"to,omitempty"`
+}
+
+func NewExportOptions(
+	dataset ExportDataset,
+	format ExportFormat,
+	headers HeaderLanguage,
+	calendar jalali.Calendar,
+	from, to *time.Time,
+) (ExportOptions, error) {
+	if from != nil && to != nil && !to.After(*from) {
+		return ExportOptions{}, ErrInvalidDateRange
+	}
+	return ExportOptions{Dataset: dataset, Format: format, Headers: headers, Calendar: calendar, From: from, To: to}, nil
+}
+
+type exportColumn struct {
+	english string
+	persian string
+}
+
+// exportColumns are the columns of each dataset, in the order ExportRepository.Stream returns them.
+var exportColumns = map[ExportDataset][]exportColumn{
+	ExportProducts: {
+		{"Code", "کد"},
+		{"Name", "نام"},
+		{"Category", "دسته‌بندی"},
+		{"Description", "توضیحات"},
+		{"Price", "قیمت"},
+		{"Stock", "موجودی"},
+		{"Active", "فعال"},
+		{"Units sold", "تعداد فروش"},
+		{"Revenue", "درآمد"},
+		{"Last sold at", "آخرین فروش"},
+		{"Created at", "تاریخ ایجاد"},
+	},
+	ExportOrders: {
+		{"Order ID", "شناسه سفارش"},
+		{"Placed at", "تاریخ ثبت"},
+		{"Status", "وضعیت"},
+		{"Source", "منبع"},
+		{"Payment status", "وضعیت پرداخت"},
+		{"Customer name", "نام مشتری"},
+		{"Customer phone", "تلفن مشتری"},
+		{"Delivery address", "آدرس تحویل"},
+		{"Order total", "مبلغ سفارش"},
+		{"Cashback applied", "کش‌بک استفاده‌شده"},
+		{"Product code", "کد محصول"},
+		{"Product name", "نام محصول"},
+		{"Quantity", "تعداد"},
+		{"Unit price", "قیمت واحد"},
+		{"Line total", "جمع ردیف"},
+	},
+	ExportCustomers: {
+		{"Name", "نام"},
+		{"Phone", "تلفن"},
+		{"Orders", "تعداد سفارش"},
+		{"Total spent", "مجموع خرید"},
+		{"First order at", "اولین سفارش"},
+		{"Last order at", "آخرین سفارش"},
+		{"Registered at", "تاریخ عضویت"},
+	},
+	ExportCashback: {
+		{"Entry ID", "شناسه"},
+		{"Created at", "تاریخ"},
+		{"Customer name", "نام مشتری"},
+		{"Customer phone", "تلفن مشتری"},
+		{"Type", "نوع"},
+		{"Amount", "مبلغ"},
+		{"Order ID", "شناسه سفارش"},
+		{"Expires at", "تاریخ انقضا"},
+	},
+}
+
+// Header returns the column names of the export in its header language.
+func (o ExportOptions) Header() []string {
+	columns := exportColumns[o.Dataset]
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.english
+		if o.Headers == HeadersPersian {
+			names[i] = column.persian
+		}
+	}
+	return names
+}
+
+// Cells prepares a row read by ExportRepository.Stream for writing: times are written in Tehran in
+// the export's calendar and booleans as yes or no in its header language. Numbers are kept as they
+// are so spreadsheets can sum them. Text is passed through plainText, since names, addresses and
+// descriptions are typed by customers and sellers.
+func (o ExportOptions) Cells(row []any) []any {
+	for i, value := range row {
+		switch v := value.(type) {
+		case string:
+			row[i] = plainText(v)
+		case time.Time:
+			if o.Calendar == jalali.Jalali {
+				row[i] = jalali.Format(v)
+			} else {
+				row[i] = v.In(jalali.Tehran).Format("2006-01-02 15:04:05")
+			}
+		case bool:
+			switch {
+			case o.Headers == HeadersPersian && v:
+				row[i] = "بله"
+			case o.Headers == HeadersPersian:
+				row[i] = "خیر"
+			case v:
+				row[i] = "yes"
+			default:
+				row[i] = "no"
+			}
+		}
+	}
+	return row
+}
+
+// plainText keeps a spreadsheet from running text as a formula. Excel reads a cell starting with =,
+// +, - or @ (or a tab or carriage return before one) as a formula, which can fetch URLs or run
+// commands, so such text is prefixed with an apostrophe. Signed numbers such as phone numbers in
+// E.164 are left alone, as they cannot do anything.
+func plainText(s string) string {
+	if s == "" || !strings.ContainsRune("=+-@\t\r", rune(s[0])) || isSignedNumber(s) {
+		return s
+	}
+	return "'" + s
+}
+
+func isSignedNumber(s string) bool {
+	if s[0] != '+' && s[0] != '-' {
+		return false
+	}
+	digits, points := 0, 0
+	for _, r := range s[1:] {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '.':
+			points++
+		default:
+			return false
+		}
+	}
+	return digits > 0 && points <= 1
+}
+
+// FileName names the export file after its dataset and the day it was made, e.g. orders-2024-04-03.xlsx.
+func (o ExportOptions) FileName(madeAt time.Time) string {
+	day := madeAt.In(jalali.Tehran).Format("2006-01-02")
+	if o.Calendar == jalali.Jalali {
+		d := jalali.FromTime(madeAt.In(jalali.Tehran))
+		day = fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+	}
+	return fmt.Sprintf("%s-%s.%s", o.Dataset, day, o.Format)
+}
+
+// ExportJobStatus is where an export job is in its life.
+type ExportJobStatus string
+
+const (
+	ExportPending ExportJobStatus = "PENDING"
+	ExportRunning ExportJobStatus = "RUNNING"
+	ExportDone    ExportJobStatus = "DONE"
+	ExportFailed  ExportJobStatus = "FAILED"
+)
+
+// ExportJob is an export written in the background and kept in file storage for download.
+type ExportJob struct {
+	ID          uuid.UUID `json:"id"`
+	ShopID      uuid.UUID `json:"shop_id"`
+	RequestedBy uuid.UUID `json:"requested_by"`
+	ExportOptions
+	Status     ExportJobStatus `json:"status"`
+	Rows       int             `json:"rows"`
+	FileKey    string          `json:"-"`
+	Error      string          `json:"error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+}
+
+func NewExportJob(shopID, requestedBy uuid.UUID, options ExportOptions) *ExportJob {
+	return &ExportJob{
+		ID:            uuid.New(),
+		ShopID:        shopID,
+		RequestedBy:   requestedBy,
+		ExportOptions: options,
+		Status:        ExportPending,
+		CreatedAt:     time.Now(),
+	}
+}
+
+// Finish records how the job ended: with rows written to the file under key, or with err.
+func (j *ExportJob) Finish(rows int, key string, err error) {
+	now := time.Now()
+	j.FinishedAt = &now
+	j.Rows = rows
+	if err != nil {
+		j.Status = ExportFailed
+		j.Error = err.Error()
+		return
+	}
+	j.Status = ExportDone
+	j.FileKey = key
+}
+
+// FileName is the name the job's file is downloaded under.
+func (j *ExportJob) FileName() string {
+	return j.ExportOptions.FileName(j.CreatedAt)
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"miniature/pkg/jalali"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCells(t *testing.T) {
+	at := time.Date(2024, 3, 19, 21, 0, 0, 0, time.UTC)
+	row := func() []any {
+		return []any{"SL-38", 125000.0, 3, true, at, nil, "+989121234567", "=HYPERLINK(\"http://x\")"}
+	}
+	tests := []struct {
+		name    string
+		options ExportOptions
+		want    []any
+	}{
+		{
+			name:    "english and gregorian",
+			options: ExportOptions{Headers: HeadersEnglish, Calendar: jalali.Gregorian},
+			want:    []any{"SL-38", 125000.0, 3, "yes", "2024-03-20 00:30:00", nil, "+989121234567", "'=HYPERLINK(\"http://x\")"},
+		},
+		{
+			name:    "persian and jalali",
+			options: ExportOptions{Headers: HeadersPersian, Calendar: jalali.Jalali},
+			want:    []any{"SL-38", 125000.0, 3, "بله", "1403/01/01 00:30:00", nil, "+989121234567", "'=HYPERLINK(\"http://x\")"},
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.options.Cells(row()); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Cells = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	tests := map[string]string{
+		"":                        "",
+		"Ali":                     "Ali",
+		"علی":                     "علی",
+		"a=b":                     "a=b",
+		"=1+2":                    "'=1+2",
+		"+cmd|' /C calc'!A0":      "'+cmd|' /C calc'!A0",
+		"-2+3+cmd|' /C calc'!A0":  "'-2+3+cmd|' /C calc'!A0",
+		"@SUM(A1:A2)":             "'@SUM(A1:A2)",
+		"\t=1+2":                  "'\t=1+2",
+		"\r=1+2":                  "'\r=1+2",
+		"+989121234567":           "+989121234567",
+		"-12.5":                   "-12.5",
+		"-":                       "'-",
+		"- not a list, a formula": "'- not a list, a formula",
+	}
+	for in, want := range tests {
+		if got := plainText(in); got != want {
+			t.Errorf("plainText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
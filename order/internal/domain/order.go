@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status is the lifecycle state of an order.
+type Status string
+
+const (
+	StatusPending   Status = "PENDING"
+	StatusPaid      Status = "PAID"
+	StatusConfirmed Status = "CONFIRMED"
+	StatusShipped   Status = "SHIPPED"
+	StatusDelivered Status = "DELIVERED"
+	StatusCancelled Status = "CANCELLED"
+)
+
+func ParseStatus(s string) (Status, error) {
+	switch status := Status(s); status {
+	case StatusPending, StatusPaid, StatusConfirmed, StatusShipped, StatusDelivered, StatusCancelled:
+		return status, nil
+	default:
+		return "", ErrInvalidStatus
+	}
+}
+
+// Order is a customer's purchase from one shop. PaymentProofURL holds the storage key of the uploaded
+// receipt, which is served through the payment-proof endpoints. CustomerID is uuid.Nil for an
+// in-store sale to a walk-in customer, and Payments is only set for in-store sales.
+type Order struct {
+	ID                     uuid.UUID     `json:"id"`
+	ShopID                 uuid.UUID     `json:"shop_id"`
+	CustomerID             uuid.UUID     `json:"customer_id"`
+	Status                 Status        `json:"status"`
+	Source                 Source        `json:"source"`
+	TotalAmount            float64       `json:"total_amount"`
+	DeliveryEstimate       string        `json:"delivery_estimate"`
+	DeliveryAddress        string        `json:"delivery_address"`
+	PaymentProofURL        string        `json:"payment_proof_url"`
+	PaymentStatus          PaymentStatus `json:"payment_status"`
+	PaymentRejectionReason string        `json:"payment_rejection_reason,omitempty"`
+	PaymentSubmittedAt     *time.Time    `json:"payment_submitted_at"`
+	CashbackApplied        float64       `json:"cashback_applied"`
+	CreatedAt              time.Time     `json:"created_at"`
+	ConfirmedAt            *time.Time    `json:"confirmed_at"`
+	Items                  []*OrderItem  `json:"items"`
+	Payments               []*Payment    `json:"payments,omitempty"`
+}
+
+// OrderItem is a line of an order. PriceAtOrder freezes the product price when the order was placed.
+type OrderItem struct {
+	ID           uuid.UUID `json:"id"`
+	OrderID      uuid.UUID `json:"order_id"`
+	ProductID    uuid.UUID `json:"product_id"`
+	ProductName  string    `json:"product_name"`
+	Quantity     int       `json:"quantity"`
+	PriceAtOrder float64   `json:"price_at_order"`
+}
+
+// Product is the part of a product the order service needs, read from the products table.
+type Product struct {
+	ID            uuid.UUID
+	ShopID        uuid.UUID
+	Name          string
+	Code          string
+	Price         float64
+	StockQuantity int
+	IsActive      bool
+}
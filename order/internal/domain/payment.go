@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"io"
+	"miniature/pkg/authz"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentStatus tracks the card-to-card payment of a PENDING order until the seller verifies it.
+type PaymentStatus string
+
+const (
+	PaymentAwaitingProof PaymentStatus = "AWAITING_PROOF"
+	PaymentSubmitted     PaymentStatus = "SUBMITTED"
+	PaymentApproved      PaymentStatus = "APPROVED"
+	PaymentRejected      PaymentStatus = "REJECTED"
+)
+
+// SubmitPaymentProof attaches an uploaded receipt, replacing one the seller rejected.
+func (o *Order) SubmitPaymentProof(key string) error {
+	if o.Status != StatusPending {
+		return ErrPaymentNotExpected
+	}
+	if o.PaymentStatus != PaymentAwaitingProof && o.PaymentStatus != PaymentRejected {
+		return ErrPaymentNotExpected
+	}
+
+	now := time.Now()
+	o.PaymentStatus = PaymentSubmitted
+	o.PaymentProofURL = key
+	o.PaymentRejectionReason = ""
+	o.PaymentSubmittedAt = &now
+	return nil
+}
+
+// ApprovePayment accepts the submitted receipt and moves the order to PAID.
+func (o *Order) ApprovePayment(actorID uuid.UUID, role authz.Role) (*StatusChange, error) {
+	if o.PaymentStatus != PaymentSubmitted {
+		return nil, ErrNoPaymentToReview
+	}
+
+	change, err := o.Transition(StatusPaid, actorID, role, "payment approved")
+	if err != nil {
+		return nil, err
+	}
+	o.PaymentStatus = PaymentApproved
+	return change, nil
+}
+
+// RejectPayment refuses the submitted receipt. The order stays PENDING so the customer can upload another.
+func (o *Order) RejectPayment(reason string) error {
+	if o.PaymentStatus != PaymentSubmitted {
+		return ErrNoPaymentToReview
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrRejectionReasonRequired
+	}
+
+	o.PaymentStatus = PaymentRejected
+	o.PaymentRejectionReason = reason
+	return nil
+}
+
+// FileStorage keeps uploaded files such as payment receipts under a key.
+type FileStorage interface {
+	Save(key, contentType string, body io.Reader, size int64) error
+	// Open returns ErrFileNotFound if nothing is stored under key.
+	Open(key string) (io.ReadCloser, error)
+}
+
+// Notifier tells a customer about something that happened to their order.
+type Notifier interface {
+	NotifyCustomer(customerID uuid.UUID, message string) error
+}
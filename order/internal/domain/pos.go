@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"math"
+	"miniature/pkg/authz"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Source is the channel an order was placed through.
+type Source string
+
+const (
+	SourceOnline Source = "ONLINE"
+	SourcePOS    Source = "POS" // an in-store sale checked out from a cart session
+)
+
+// PaymentMethod is how one part of an in-store sale was paid.
+type PaymentMethod string
+
+const (
+	PaymentCash     PaymentMethod = "CASH"
+	PaymentCard     PaymentMethod = "CARD"
+	PaymentCashback PaymentMethod = "CASHBACK"
+)
+
+func ParsePaymentMethod(s string) (PaymentMethod, error) {
+	switch method := PaymentMethod(s); method {
+	case PaymentCash, PaymentCard, PaymentCashback:
+		return method, nil
+	default:
+		return "", ErrInvalidPaymentMethod
+	}
+}
+
+// Payment is one part of a split payment.
+type Payment struct {
+	ID        uuid.UUID     `json:"id"`
+	OrderID   uuid.UUID     `json:"order_id"`
+	Method    PaymentMethod `json:"method"`
+	Amount    float64       `json:"amount"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// NewPOSOrder turns an open cart session into a PAID order. customerID is uuid.Nil for a walk-in
+// customer. The payment parts must add up to the cart total; a CASHBACK part is spent from the
+// linked customer's balance through the returned status change.
+func NewPOSOrder(
+	session *CartSession,
+	customerID uuid.UUID,
+	parts []Payment,
+	actorID uuid.UUID,
+	role authz.Role,
+) (*Order, *StatusChange, error) {
+	now := time.Now()
+	if err := session.EnsureOpen(now); err != nil {
+		return nil, nil, err
+	}
+	if len(session.Items) == 0 {
+		return nil, nil, ErrEmptyOrder
+	}
+
+	order := &Order{
+		ID:            uuid.New(),
+		ShopID:        session.ShopID,
+		CustomerID:    customerID,
+		Status:        StatusPaid,
+		PaymentStatus: PaymentApproved,
+		Source:        SourcePOS,
+		CreatedAt:     now,
+	}
+	for _, cartItem := range session.Items {
+		order.Items = append(order.Items, &OrderItem{
+			ID:           uuid.New(),
+			OrderID:      order.ID,
+			ProductID:    cartItem.ProductID,
+			ProductName:  cartItem.ProductName,
+			Quantity:     cartItem.Quantity,
+			PriceAtOrder: cartItem.UnitPrice,
+		})
+	}
+	order.TotalAmount = session.Total()
+
+	var paid, cashback float64
+	for _, part := range parts {
+		if part.Amount <= 0 {
+			return nil, nil, ErrInvalidPaymentAmount
+		}
+		if part.Method == PaymentCashback {
+			if customerID == uuid.Nil {
+				return nil, nil, ErrCashbackNeedsCustomer
+			}
+			cashback += part.Amount
+		}
+		order.Payments = append(order.Payments, &Payment{
+			ID:        uuid.New(),
+			OrderID:   order.ID,
+			Method:    part.Method,
+			Amount:    roundMoney(part.Amount),
+			CreatedAt: now,
+		})
+		paid += part.Amount
+	}
+	if math.Abs(roundMoney(paid)-order.TotalAmount) >= 0.01 {
+		return nil, nil, ErrPaymentMismatch
+	}
+
+	created := &StatusChange{
+		ID:        uuid.New(),
+		OrderID:   order.ID,
+		ToStatus:  StatusPaid,
+		ActorID:   actorID,
+		ActorRole: role,
+		Note:      "in-store sale",
+		CreatedAt: now,
+	}
+	entry, err := order.ApplyCashback(cashback)
+	if err != nil {
+		return nil, nil, err
+	}
+	created.Cashback = entry
+
+	return order, created, nil
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductStats is a product's all-time sales, counting the orders in SaleStatuses. Repositories
+// keep it up to date in the transaction that moves an order into a sale status or cancels it.
+type ProductStats struct {
+	ProductID       uuid.UUID  `json:"product_id"`
+	ProductName     string     `json:"product_name"`
+	TotalSold       int        `json:"total_sold"`
+	TotalRevenue    float64    `json:"total_revenue"`
+	LastPurchasedAt *time.Time `json:"last_purchased_at"`
+}
+
+// Matches reports whether two stats of the same product agree, ignoring rounding below a cent.
+func (s *ProductStats) Matches(other *ProductStats) bool {
+	if s.TotalSold != other.TotalSold || roundMoney(s.TotalRevenue) != roundMoney(other.TotalRevenue) {
+		return false
+	}
+	if s.LastPurchasedAt == nil || other.LastPurchasedAt == nil {
+		return s.LastPurchasedAt == other.LastPurchasedAt
+	}
+	return s.LastPurchasedAt.Equal(*other.LastPurchasedAt)
+}
+
+// StatsDrift is a product whose recorded stats differ from those recomputed from its orders. A
+// side is nil if the product has no row there.
+type StatsDrift struct {
+	ProductID uuid.UUID
+	Recorded  *ProductStats
+	Expected  *ProductStats
+}
+
+// StatsRebuild is what recomputing product_stats from the order history found.
+type StatsRebuild struct {
+	// FlaggedOrders is the number of orders whose stats_applied flag disagreed with their status.
+	FlaggedOrders int
+	Drift         []*StatsDrift
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"miniature/pkg/authz"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// Create stores the order together with its items and the first entry of its timeline, taking
+	// each item's quantity out of stock and any cashback spent in the same transaction. It returns a
+	// *StockError listing every short line if any product does not have enough left, and
+	// ErrInsufficientCashback if the customer's balance is too low.
+	Create(order *Order, created *StatusChange) error
+	FindByID(id string) (*Order, error)
+	FindByCustomerID(customerID string) ([]*Order, error)
+	// FindByShopID lists a shop's orders, newest first. An empty status returns every status.
+	FindByShopID(shopID string, status Status) ([]*Order, error)
+	// Update saves everything but the status, which only changes through UpdateStatus.
+	Update(order *Order) error
+	// UpdateStatus saves a transition, its history entry and its cashback movement atomically,
+	// restocking the items if the change releases stock and updating product_stats if the order
+	// becomes a sale or stops being one. It returns ErrStatusConflict if the order is no longer in change.FromStatus.
+	UpdateStatus(order *Order, change *StatusChange) error
+	// UpdatePayment saves the payment fields, and change if it is not nil, atomically. It returns
+	// ErrStatusConflict if the payment is no longer in status from.
+	UpdatePayment(order *Order, from PaymentStatus, change *StatusChange) error
+	// FindAwaitingPaymentReview lists a shop's PENDING orders with a submitted proof, oldest submission first.
+	FindAwaitingPaymentReview(shopID string) ([]*Order, error)
+	// FindHistory returns an order's timeline, oldest first.
+	FindHistory(orderID string) ([]*StatusChange, error)
+}
+
+// ProductCatalog reads the products an order refers to.
+type ProductCatalog interface {
+	FindByIDs(ids []uuid.UUID) (map[uuid.UUID]*Product, error)
+	// FindByCode returns the shop's product with the given code, ignoring case, or nil.
+	FindByCode(shopID, code string) (*Product, error)
+	// FindActiveByShopID returns the shop's products that can be ordered.
+	FindActiveByShopID(shopID string) ([]*Product, error)
+}
+
+// ShopMembershipRepository looks up shops and a user's role on a shop's staff.
+type ShopMembershipRepository interface {
+	// MemberRole returns the role userID holds in shopID, or "" if they are not on its staff.
+	MemberRole(userID, shopID string) (authz.Role, error)
+	// ShopName returns "" if the shop does not exist.
+	ShopName(shopID string) (string, error)
+}
+
+// CustomerDirectory finds customers of the platform.
+type CustomerDirectory interface {
+	// FindIDByPhone takes a normalized phone number and returns uuid.Nil if no customer has it.
+	FindIDByPhone(phone string) (uuid.UUID, error)
+	// FindOrCreateByPhone returns the customer with a normalized phone number, registering them
+	// first if they never signed up. Customers are identified by phone only, so an order placed
+	// through a chat channel shows up once they log in with that number.
+	FindOrCreateByPhone(phone string) (uuid.UUID, error)
+}
+
+// CashbackRepository reads the cashback ledger and the shops' cashback settings. Ledger entries are
+// written by Repository, in the same transaction as the order change that causes them.
+type CashbackRepository interface {
+	// FindSettings returns nil if the shop has not configured cashback.
+	FindSettings(shopID string) (*CashbackSettings, error)
+	SaveSettings(settings *CashbackSettings) error
+	Balance(customerID string) (float64, error)
+	// FindEntries returns a customer's ledger, newest first.
+	FindEntries(customerID string) ([]*CashbackEntry, error)
+
+	// FindExpiredCredits returns up to limit EARNED credits past their expiry that still have something left.
+	FindExpiredCredits(now time.Time, limit int) ([]*CashbackEntry, error)
+	// ExpireCredit writes off what is left of an expired credit and returns the EXPIRED entry, or nil
+	// if nothing was left by the time the customer's balance was locked.
+	ExpireCredit(creditID string) (*CashbackEntry, error)
+	// FindExpiringCredits returns up to limit credits expiring before until whose customers have not been warned yet.
+	FindExpiringCredits(until time.Time, limit int) ([]*CashbackEntry, error)
+	MarkExpiryWarned(creditID string) error
+}
+
+// CartRepository stores in-store cart sessions. Every item change reserves or releases stock in the
+// same transaction, with the session row locked and checked with CartSession.EnsureOpen first.
+type CartRepository interface {
+	Create(session *CartSession) error
+	// FindByID returns the session with its items, or nil.
+	FindByID(id string) (*CartSession, error)
+	// AddItem adds quantity of a product, merging it into the product's line if it is already in the
+	// cart, and pushes the session's expiry to expiresAt. It returns a *StockError if stock is short.
+	AddItem(sessionID string, product *Product, quantity int, expiresAt time.Time) error
+	// SetItemQuantity changes a line's quantity, removing it at zero, and pushes the session's expiry to expiresAt.
+	SetItemQuantity(sessionID, itemID string, quantity int, expiresAt time.Time) error
+	// Release puts every item back in stock and closes the session with status.
+	Release(sessionID string, status CartStatus) error
+	// FindExpired returns up to limit OPEN sessions whose expiry has passed.
+	FindExpired(now time.Time, limit int) ([]string, error)
+	// Checkout locks the session, passes it with its items to build, and stores the order build
+	// returns together with its payments, history entry and cashback movement. The cart's items are
+	// marked finalized and the session CHECKED_OUT in the same transaction; their stock stays taken.
+	Checkout(sessionID string, build func(session *CartSession) (*Order, *StatusChange, error)) (*Order, error)
+}
+
+// DraftRepository stores draft orders with their lines.
+type DraftRepository interface {
+	Create(draft *DraftOrder) error
+	// FindByID returns the draft with its lines, or nil.
+	FindByID(id string) (*DraftOrder, error)
+	// FindByShopID lists a shop's drafts, newest first. An empty status returns every status.
+	FindByShopID(shopID string, status DraftStatus) ([]*DraftOrder, error)
+	// Update saves the draft's customer details and replaces its lines. It returns ErrDraftClosed if
+	// the draft is no longer OPEN.
+	Update(draft *DraftOrder) error
+	// Discard closes an OPEN draft without an order, or returns ErrDraftClosed.
+	Discard(id string) error
+	// Accept locks the draft, passes it with its lines to build, and stores the order build returns
+	// the way Repository.Create does, taking its items out of stock. The draft is marked ACCEPTED
+	// with the order's ID in the same transaction. customers works inside that transaction too, so a
+	// customer build registers is rolled back with the order.
+	Accept(id string, build func(draft *DraftOrder, customers CustomerDirectory) (*Order, *StatusChange, error)) (*Order, error)
+}
+
+// AnalyticsRepository reads a shop's sales for reports. FindSales and FindSoldItems return the
+// orders in SaleStatuses placed within [from, to), oldest first.
+type AnalyticsRepository interface {
+	FindSales(shopID string, from, to time.Time) ([]*Sale, error)
+	FindSoldItems(shopID string, from, to time.Time) ([]*SoldItem, error)
+	// FindBestSellers reads up to limit of the shop's products from product_stats, best first.
+	FindBestSellers(shopID string, by ProductRanking, limit int) ([]*ProductStats, error)
+}
+
+// ExportRepository reads a shop's data for exports and keeps track of export jobs.
+type ExportRepository interface {
+	// Count returns how many rows Stream would pass to fn.
+	Count(shopID string, options ExportOptions) (int, error)
+	// Stream passes the rows of the dataset to fn one at a time as they are read, with values in the
+	// order of the dataset's columns. It stops at the first error fn returns.
+	Stream(shopID string, options ExportOptions, fn func(row []any) error) error
+
+	CreateJob(job *ExportJob) error
+	// FindJob returns nil if there is no job with that ID.
+	FindJob(id string) (*ExportJob, error)
+	FindJobsByShopID(shopID string) ([]*ExportJob, error)
+	// ClaimJob marks the oldest PENDING job RUNNING and returns it, or nil if there is none. A job
+	// still RUNNING since before staleBefore is taken to have died with its instance and is claimed
+	// again.
+	ClaimJob(staleBefore time.Time) (*ExportJob, error)
+	// FinishJob saves how a RUNNING job ended.
+	FinishJob(job *ExportJob) error
+}
+
+// ProductStatsRepository checks product_stats against the order history.
+type ProductStatsRepository interface {
+	// Rebuild recomputes every product's stats from the orders in SaleStatuses and reports where they
+	// drifted. Nothing is changed unless apply is true. Orders cannot be written while it runs.
+	Rebuild(apply bool) (*StatsRebuild, error)
+}
+
+// JobLock makes sure a background job runs on only one instance at a time.
+type JobLock interface {
+	// TryRun runs fn if no other instance holds the lock named name and reports whether it ran.
+	TryRun(name string, fn func() error) (bool, error)
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// StockShortage describes an order line that asks for more than is in stock.
+type StockShortage struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	Requested   int       `json:"requested"`
+	Available   int       `json:"available"`
+}
+
+// StockError rejects an order with every line that could not be reserved, so the customer can fix them all at once.
+type StockError struct {
+	Shortages []StockShortage
+}
+
+func (e *StockError) Error() string {
+	parts := make([]string, 0, len(e.Shortages))
+	for _, s := range e.Shortages {
+		parts = append(parts, fmt.Sprintf("%s: requested %d, available %d", s.ProductName, s.Requested, s.Available))
+	}
+	return ErrOutOfStock.Error() + ": " + strings.Join(parts, "; ")
+}
+
+func (e *StockError) Is(target error) bool {
+	return target == ErrOutOfStock
+}
+
+// ReleasesStock reports whether the change puts the order's items back in stock.
+// Stock is taken when an order is placed, so only cancelling gives it back.
+func (c *StatusChange) ReleasesStock() bool {
+	return c.ToStatus == StatusCancelled
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"fmt"
+	"miniature/pkg/authz"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// transitions lists, for each status, the statuses it may move to and the roles allowed to make the move.
+// CUSTOMER here means the customer who placed the order; SELLER and OWNER are roles within the order's shop.
+var transitions = map[Status]map[Status][]authz.Role{
+	StatusPending: {
+		StatusPaid:      {authz.RoleSeller, authz.RoleOwner},
+		StatusCancelled: {authz.RoleCustomer, authz.RoleSeller, authz.RoleOwner},
+	},
+	StatusPaid: {
+		StatusConfirmed: {authz.RoleOwner},
+		StatusCancelled: {authz.RoleOwner},
+	},
+	StatusConfirmed: {
+		StatusShipped:   {authz.RoleSeller, authz.RoleOwner},
+		StatusCancelled: {authz.RoleOwner},
+	},
+	StatusShipped: {
+		StatusDelivered: {authz.RoleCustomer, authz.RoleSeller, authz.RoleOwner},
+	},
+}
+
+// CanTransition checks a status change against the transition graph and the roles allowed to make it.
+// ADMIN may make any change the graph allows.
+func CanTransition(from, to Status, role authz.Role) error {
+	roles, ok := transitions[from][to]
+	if !ok {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+	}
+	if role == authz.RoleAdmin {
+		return nil
+	}
+	for _, allowed := range roles {
+		if allowed == role {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s may not move an order from %s to %s", ErrTransitionNotAllowed, role, from, to)
+}
+
+// NextStatuses returns the statuses role may move an order in status from to.
+func NextStatuses(from Status, role authz.Role) []Status {
+	var next []Status
+	for _, to := range []Status{StatusPaid, StatusConfirmed, StatusShipped, StatusDelivered, StatusCancelled} {
+		if CanTransition(from, to, role) == nil {
+			next = append(next, to)
+		}
+	}
+	return next
+}
+
+// StatusChange is an entry of an order's timeline. FromStatus is empty for the entry recorded at creation.
+// Cashback, if set, is the ledger movement the change causes and is saved in the same transaction.
+type StatusChange struct {
+	ID         uuid.UUID  `json:"id"`
+	OrderID    uuid.UUID  `json:"order_id"`
+	FromStatus Status     `json:"from_status,omitempty"`
+	ToStatus   Status     `json:"to_status"`
+	ActorID    uuid.UUID  `json:"actor_id"`
+	ActorRole  authz.Role `json:"actor_role"`
+	Note       string     `json:"note,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	Cashback *CashbackEntry `json:"-"`
+}
+
+// Transition moves the order to status to on behalf of actorID and returns the change to record.
+// confirmed_at is set here, when the order is confirmed, and nowhere else.
+func (o *Order) Transition(to Status, actorID uuid.UUID, role authz.Role, note string) (*StatusChange, error) {
+	if err := CanTransition(o.Status, to, role); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	change := &StatusChange{
+		ID:         uuid.New(),
+		OrderID:    o.ID,
+		FromStatus: o.Status,
+		ToStatus:   to,
+		ActorID:    actorID,
+		ActorRole:  role,
+		Note:       note,
+		CreatedAt:  now,
+	}
+
+	o.Status = to
+	if to == StatusConfirmed {
+		o.ConfirmedAt = &now
+	}
+	return change, nil
+}
+
+// Created returns the first timeline entry of a newly placed order.
+func (o *Order) Created(actorID uuid.UUID, role authz.Role) *StatusChange {
+	return &StatusChange{
+		ID:        uuid.New(),
+		OrderID:   o.ID,
+		ToStatus:  o.Status,
+		ActorID:   actorID,
+		ActorRole: role,
+		CreatedAt: o.CreatedAt,
+	}
+}
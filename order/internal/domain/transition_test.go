@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"errors"
+	"miniature/pkg/authz"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to Status
+		role     authz.Role
+		want     error
+	}{
+		{StatusPending, StatusPaid, authz.RoleSeller, nil},
+		{StatusPending, StatusPaid, authz.RoleOwner, nil},
+		{StatusPending, StatusPaid, authz.RoleCustomer, ErrTransitionNotAllowed},
+		{StatusPending, StatusCancelled, authz.RoleCustomer, nil},
+		{StatusPending, StatusConfirmed, authz.RoleOwner, ErrInvalidTransition},
+		{StatusPaid, StatusConfirmed, authz.RoleOwner, nil},
+		{StatusPaid, StatusConfirmed, authz.RoleSeller, ErrTransitionNotAllowed},
+		{StatusPaid, StatusCancelled, authz.RoleCustomer, ErrTransitionNotAllowed},
+		{StatusConfirmed, StatusShipped, authz.RoleSeller, nil},
+		{StatusConfirmed, StatusCancelled, authz.RoleSeller, ErrTransitionNotAllowed},
+		{StatusShipped, StatusDelivered, authz.RoleCustomer, nil},
+		{StatusShipped, StatusCancelled, authz.RoleOwner, ErrInvalidTransition},
+		{StatusDelivered, StatusCancelled, authz.RoleOwner, ErrInvalidTransition},
+		{StatusCancelled, StatusPending, authz.RoleOwner, ErrInvalidTransition},
+		{StatusPending, StatusPending, authz.RoleOwner, ErrInvalidTransition},
+		{StatusPaid, StatusConfirmed, authz.RoleAdmin, nil},
+		{StatusDelivered, StatusShipped, authz.RoleAdmin, ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		err := CanTransition(tt.from, tt.to, tt.role)
+		if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("CanTransition(%s, %s, %s) = %v, want %v", tt.from, tt.to, tt.role, err, tt.want)
+		}
+	}
+}
+
+func TestNextStatuses(t *testing.T) {
+	tests := []struct {
+		from Status
+		role authz.Role
+		want []Status
+	}{
+		{StatusPending, authz.RoleCustomer, []Status{StatusCancelled}},
+		{StatusPending, authz.RoleSeller, []Status{StatusPaid, StatusCancelled}},
+		{StatusPaid, authz.RoleOwner, []Status{StatusConfirmed, StatusCancelled}},
+		{StatusPaid, authz.RoleSeller, nil},
+		{StatusShipped, authz.RoleCustomer, []Status{StatusDelivered}},
+		{StatusDelivered, authz.RoleAdmin, nil},
+	}
+	for _, tt := range tests {
+		if got := NextStatuses(tt.from, tt.role); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("NextStatuses(%s, %s) = %v, want %v", tt.from, tt.role, got, tt.want)
+		}
+	}
+}
+
+func TestTransition(t *testing.T) {
+	actorID := uuid.New()
+	order := &Order{ID: uuid.New(), Status: StatusPaid}
+
+	if _, err := order.Transition(StatusConfirmed, actorID, authz.RoleSeller, ""); !errors.Is(err, ErrTransitionNotAllowed) {
+		t.Fatalf("seller confirmed a paid order: %v", err)
+	}
+	if order.Status != StatusPaid || order.ConfirmedAt != nil {
+		t.Fatalf("refused transition changed the order: %s, %v", order.Status, order.ConfirmedAt)
+	}
+
+	change, err := order.Transition(StatusConfirmed, actorID, authz.RoleOwner, "checked the receipt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != StatusConfirmed || order.ConfirmedAt == nil {
+		t.Errorf("order after confirming: %s, confirmed at %v", order.Status, order.ConfirmedAt)
+	}
+	want := StatusChange{
+		ID: change.ID, OrderID: order.ID, FromStatus: StatusPaid, ToStatus: StatusConfirmed,
+		ActorID: actorID, ActorRole: authz.RoleOwner, Note: "checked the receipt", CreatedAt: *order.ConfirmedAt,
+	}
+	if *change != want {
+		t.Errorf("change = %+v, want %+v", *change, want)
+	}
+}
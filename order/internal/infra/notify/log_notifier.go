@@ -0,0 +1,21 @@
+package notify
+
+import (
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// LogNotifier writes customer notifications to the application log instead of delivering them.
+// It is meant for local development and tests.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) NotifyCustomer(customerID uuid.UUID, message string) error {
+	log.Printf("[notify] customer=%s message=%q", customerID, message)
+
+	return nil
+}
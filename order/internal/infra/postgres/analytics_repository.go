@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"database/sql"
+	"miniature/order/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type analyticsRepository struct {
+	db *sql.DB
+}
+
+func NewAnalyticsRepository(db *sql.DB) *analyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+func saleStatuses() any {
+	statuses := make([]string, 0, len(domain.SaleStatuses))
+	for _, status := range domain.SaleStatuses {
+		statuses = append(statuses, string(status))
+	}
+	return pq.Array(statuses)
+}
+
+func (r *analyticsRepository) FindSales(shopID string, from, to time.Time) ([]*domain.Sale, error) {
+	// A customer's first sale is looked up over the shop's whole history to tell new customers from returning ones
+	query := `WITH first_sales AS (
+                  SELECT customer_id, MIN(created_at) AS first_sale_at
+                  FROM orders
+                  WHERE shop_id = $1 AND status = ANY($2) AND customer_id IS NOT NULL
+                  GROUP BY customer_id
+              )
+              SELECT o.id, o.customer_id, o.total_amount, o.created_at, COALESCE(f.first_sale_at, o.created_at)
+              FROM orders o
+              LEFT JOIN first_sales f ON f.customer_id = o.customer_id
+              WHERE o.shop_id = $1 AND o.status = ANY($2) AND o.created_at >= $3 AND o.created_at < $4
+              ORDER BY o.created_at`
+	rows, err := r.db.Query(query, shopID, saleStatuses(), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sales []*domain.Sale
+	for rows.Next() {
+		sale := &domain.Sale{}
+		var customerID uuid.NullUUID
+		if err := rows.Scan(&sale.OrderID, &customerID, &sale.Total, &sale.CreatedAt, &sale.FirstSaleAt); err != nil {
+			return nil, err
+		}
+		sale.CustomerID = customerID.UUID
+		sales = append(sales, sale)
+	}
+	return sales, rows.Err()
+}
+
+func (r *analyticsRepository) FindSoldItems(shopID string, from, to time.Time) ([]*domain.SoldItem, error) {
+	query := `SELECT oi.product_id, COALESCE(p.name, ''), oi.quantity, oi.quantity * oi.price_at_order, o.created_at
+              FROM order_items oi
+              JOIN orders o ON o.id = oi.order_id
+              LEFT JOIN products p ON p.id = oi.product_id
+              WHERE o.shop_id = $1 AND o.status = ANY($2) AND o.created_at >= $3 AND o.created_at < $4
+              ORDER BY o.created_at`
+	rows, err := r.db.Query(query, shopID, saleStatuses(), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*domain.SoldItem
+	for rows.Next() {
+		item := &domain.SoldItem{}
+		if err := rows.Scan(&item.ProductID, &item.ProductName, &item.Quantity, &item.Revenue, &item.SoldAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *analyticsRepository) FindBestSellers(shopID string, by domain.ProductRanking, limit int) ([]*domain.ProductStats, error) {
+	order := `s.total_sold DESC, s.total_revenue DESC`
+	if by == domain.RankByRevenue {
+		order = `s.total_revenue DESC, s.total_sold DESC`
+	}
+	query := `SELECT s.product_id, p.name, COALESCE(s.total_sold, 0), COALESCE(s.total_revenue, 0), s.last_purchased_at
+              FROM product_stats s
+              JOIN products p ON p.id = s.product_id
+              WHERE p.shop_id = $1 AND s.total_sold > 0
+              ORDER BY ` + order + `, p.name
+              LIMIT $2`
+	rows, err := r.db.Query(query, shopID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*domain.ProductStats
+	for rows.Next() {
+		s := &domain.ProductStats{}
+		var lastPurchasedAt sql.NullTime
+		if err := rows.Scan(&s.ProductID, &s.ProductName, &s.TotalSold, &s.TotalRevenue, &lastPurchasedAt); err != nil {
+			return nil, err
+		}
+		if lastPurchasedAt.Valid {
+			s.LastPurchasedAt = &lastPurchasedAt.Time
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
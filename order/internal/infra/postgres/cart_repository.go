@@ -0,0 +1,318 @@
+package postgres
+
+import (
+	"database/sql"
+	"miniature/order/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type cartRepository struct {
+	db *sql.DB
+}
+
+func NewCartRepository(db *sql.DB) *cartRepository {
+	return &cartRepository{db: db}
+}
+
+func (r *cartRepository) Create(session *domain.CartSession) error {
+	query := `INSERT INTO cart_sessions (id, shop_id, opened_by, status, expires_at, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := r.db.Exec(query,
+		session.ID, session.ShopID, session.OpenedBy, session.Status, session.ExpiresAt, session.CreatedAt,
+	)
+	return err
+}
+
+func (r *cartRepository) FindByID(id string) (*domain.CartSession, error) {
+	session, err := findSession(r.db.QueryRow(sessionQuery, id))
+	if err != nil || session == nil {
+		return nil, err
+	}
+
+	if err := loadCartItems(r.db, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (r *cartRepository) AddItem(sessionID string, product *domain.Product, quantity int, expiresAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockOpenSession(tx, sessionID); err != nil {
+		return err
+	}
+
+	shortage, err := reserveStock(tx, product.ID, product.Name, quantity)
+	if err != nil {
+		return err
+	}
+	if shortage != nil {
+		return &domain.StockError{Shortages: []domain.StockShortage{*shortage}}
+	}
+
+	// A product scanned again is merged into its existing line
+	result, err := tx.Exec(
+		`UPDATE cart_items SET quantity = quantity + $1, scanned_at = NOW()
+         WHERE session_id = $2 AND product_id = $3 AND NOT is_finalized`,
+		quantity, sessionID, product.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		_, err = tx.Exec(
+			`INSERT INTO cart_items (id, session_id, product_id, quantity, scanned_at, is_finalized)
+             VALUES ($1, $2, $3, $4, NOW(), FALSE)`,
+			uuid.New(), sessionID, product.ID, quantity,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := extendSession(tx, sessionID, expiresAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *cartRepository) SetItemQuantity(sessionID, itemID string, quantity int, expiresAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockOpenSession(tx, sessionID); err != nil {
+		return err
+	}
+
+	var productID uuid.UUID
+	var productName string
+	var current int
+	err = tx.QueryRow(
+		`SELECT ci.product_id, COALESCE(p.name, ''), ci.quantity
+         FROM cart_items ci LEFT JOIN products p ON p.id = ci.product_id
+         WHERE ci.id = $1 AND ci.session_id = $2 AND NOT ci.is_finalized
+         FOR UPDATE OF ci`,
+		itemID, sessionID,
+	).Scan(&productID, &productName, &current)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrCartItemNotFound
+		}
+		return err
+	}
+
+	switch delta := quantity - current; {
+	case delta > 0:
+		shortage, err := reserveStock(tx, productID, productName, delta)
+		if err != nil {
+			return err
+		}
+		if shortage != nil {
+			return &domain.StockError{Shortages: []domain.StockShortage{*shortage}}
+		}
+	case delta < 0:
+		if err := releaseStock(tx, productID, -delta); err != nil {
+			return err
+		}
+	}
+
+	if quantity == 0 {
+		_, err = tx.Exec(`DELETE FROM cart_items WHERE id = $1`, itemID)
+	} else {
+		_, err = tx.Exec(`UPDATE cart_items SET quantity = $1 WHERE id = $2`, quantity, itemID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := extendSession(tx, sessionID, expiresAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *cartRepository) Release(sessionID string, status domain.CartStatus) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Expired sessions are released too; that is how the sweeper closes them
+	session, err := findSession(tx.QueryRow(sessionQuery+` FOR UPDATE`, sessionID))
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return domain.ErrCartNotFound
+	}
+	if session.Status != domain.CartOpen {
+		return domain.ErrCartClosed
+	}
+
+	restock := `UPDATE products p SET stock_quantity = p.stock_quantity + ci.quantity
+                FROM cart_items ci
+                WHERE ci.session_id = $1 AND NOT ci.is_finalized AND p.id = ci.product_id`
+	if _, err := tx.Exec(restock, sessionID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM cart_items WHERE session_id = $1 AND NOT is_finalized`, sessionID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE cart_sessions SET status = $1 WHERE id = $2`, status, sessionID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *cartRepository) Checkout(
+	sessionID string,
+	build func(session *domain.CartSession) (*domain.Order, *domain.StatusChange, error),
+) (*domain.Order, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	session, err := findSession(tx.QueryRow(sessionQuery+` FOR UPDATE`, sessionID))
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, domain.ErrCartNotFound
+	}
+	if err := loadCartItems(tx, session); err != nil {
+		return nil, err
+	}
+
+	order, created, err := build(session)
+	if err != nil {
+		return nil, err
+	}
+
+	// The stock was taken when the items were scanned, so only the order itself is written
+	if err := insertOrder(tx, order); err != nil {
+		return nil, err
+	}
+	if err := insertStatusChange(tx, created); err != nil {
+		return nil, err
+	}
+	if created.Cashback != nil {
+		if err := applyCashbackEntry(tx, created.Cashback); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE cart_items SET is_finalized = TRUE WHERE session_id = $1`, sessionID); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`UPDATE cart_sessions SET status = $1 WHERE id = $2`, domain.CartCheckedOut, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := syncProductStats(tx, order.ID); err != nil {
+		return nil, err
+	}
+
+	return order, tx.Commit()
+}
+
+func (r *cartRepository) FindExpired(now time.Time, limit int) ([]string, error) {
+	query := `SELECT id FROM cart_sessions WHERE status = $1 AND expires_at <= $2 ORDER BY expires_at LIMIT $3`
+	rows, err := r.db.Query(query, domain.CartOpen, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+const sessionQuery = `SELECT id, shop_id, opened_by, status, expires_at, created_at FROM cart_sessions WHERE id = $1`
+
+func findSession(row scanner) (*domain.CartSession, error) {
+	session := &domain.CartSession{}
+	err := row.Scan(&session.ID, &session.ShopID, &session.OpenedBy, &session.Status, &session.ExpiresAt, &session.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+func loadCartItems(q querier, session *domain.CartSession) error {
+	query := `SELECT ci.id, ci.product_id, COALESCE(p.name, ''), COALESCE(p.code, ''), COALESCE(p.price, 0),
+                     ci.quantity, ci.scanned_at, ci.is_finalized
+              FROM cart_items ci
+              LEFT JOIN products p ON p.id = ci.product_id
+              WHERE ci.session_id = $1
+              ORDER BY ci.scanned_at, ci.id`
+	rows, err := q.Query(query, session.ID.String())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	session.Items = []*domain.CartItem{}
+	for rows.Next() {
+		item := &domain.CartItem{SessionID: session.ID}
+		err := rows.Scan(
+			&item.ID, &item.ProductID, &item.ProductName, &item.Code, &item.UnitPrice,
+			&item.Quantity, &item.ScannedAt, &item.IsFinalized,
+		)
+		if err != nil {
+			return err
+		}
+		session.Items = append(session.Items, item)
+	}
+	return rows.Err()
+}
+
+// lockOpenSession locks the session row for the rest of tx and checks it can still be changed.
+func lockOpenSession(tx *sql.Tx, sessionID string) (*domain.CartSession, error) {
+	session, err := findSession(tx.QueryRow(sessionQuery+` FOR UPDATE`, sessionID))
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, domain.ErrCartNotFound
+	}
+	if err := session.EnsureOpen(time.Now()); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func extendSession(tx *sql.Tx, sessionID string, expiresAt time.Time) error {
+	_, err := tx.Exec(`UPDATE cart_sessions SET expires_at = $1 WHERE id = $2`, expiresAt, sessionID)
+	return err
+}
@@ -0,0 +1,261 @@
+package postgres
+
+import (
+	"database/sql"
+	"math"
+	"miniature/order/internal/domain"
+	"time"
+)
+
+type cashbackRepository struct {
+	db *sql.DB
+}
+
+func NewCashbackRepository(db *sql.DB) *cashbackRepository {
+	return &cashbackRepository{db: db}
+}
+
+// entryColumns selects a ledger entry of alias l; remaining is what no USED or EXPIRED entry has consumed yet.
+const entryColumns = `l.id, l.customer_id, l.shop_id, l.related_order_id, l.type, l.amount, l.balance_after, l.expires_at,
+                      CASE WHEN l.type IN ('EARNED', 'REFUNDED')
+                           THEN l.amount - COALESCE((SELECT SUM(c.amount) FROM cashback_consumptions c WHERE c.credit_id = l.id), 0)
+                           ELSE 0 END AS remaining,
+                      l.created_at`
+
+func scanEntry(row scanner) (*domain.CashbackEntry, error) {
+	entry := &domain.CashbackEntry{}
+	var expiresAt sql.NullTime
+	err := row.Scan(
+		&entry.ID, &entry.CustomerID, &entry.ShopID, &entry.RelatedOrderID, &entry.Type,
+		&entry.Amount, &entry.BalanceAfter, &expiresAt, &entry.Remaining, &entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		entry.ExpiresAt = &expiresAt.Time
+	}
+	return entry, nil
+}
+
+func (r *cashbackRepository) FindSettings(shopID string) (*domain.CashbackSettings, error) {
+	settings := &domain.CashbackSettings{}
+	query := `SELECT shop_id, percent, expiry_days, updated_at FROM shop_cashback_settings WHERE shop_id = $1`
+	err := r.db.QueryRow(query, shopID).Scan(&settings.ShopID, &settings.Percent, &settings.ExpiryDays, &settings.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (r *cashbackRepository) SaveSettings(settings *domain.CashbackSettings) error {
+	query := `INSERT INTO shop_cashback_settings (shop_id, percent, expiry_days, updated_at)
+              VALUES ($1, $2, $3, $4)
+              ON CONFLICT (shop_id) DO UPDATE
+              SET percent = EXCLUDED.percent, expiry_days = EXCLUDED.expiry_days, updated_at = EXCLUDED.updated_at`
+	_, err := r.db.Exec(query, settings.ShopID, settings.Percent, settings.ExpiryDays, settings.UpdatedAt)
+	return err
+}
+
+func (r *cashbackRepository) Balance(customerID string) (float64, error) {
+	var balance float64
+	err := r.db.QueryRow(`SELECT COALESCE(cashback_balance, 0) FROM customer WHERE id = $1`, customerID).Scan(&balance)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+	return balance, nil
+}
+
+func (r *cashbackRepository) FindEntries(customerID string) ([]*domain.CashbackEntry, error) {
+	query := `SELECT ` + entryColumns + ` FROM cashback_logs l
+              WHERE l.customer_id = $1
+              ORDER BY l.created_at DESC, l.id`
+	return r.findEntries(query, customerID)
+}
+
+func (r *cashbackRepository) FindExpiredCredits(now time.Time, limit int) ([]*domain.CashbackEntry, error) {
+	query := `SELECT * FROM (
+                  SELECT ` + entryColumns + ` FROM cashback_logs l
+                  WHERE l.type = 'EARNED' AND l.expires_at <= $1
+              ) credits
+              WHERE credits.remaining > 0
+              ORDER BY credits.expires_at
+              LIMIT $2`
+	return r.findEntries(query, now, limit)
+}
+
+func (r *cashbackRepository) FindExpiringCredits(until time.Time, limit int) ([]*domain.CashbackEntry, error) {
+	query := `SELECT * FROM (
+                  SELECT ` + entryColumns + ` FROM cashback_logs l
+                  WHERE l.type = 'EARNED' AND l.expires_at > NOW() AND l.expires_at <= $1
+                    AND NOT EXISTS (SELECT 1 FROM cashback_expiry_warnings w WHERE w.credit_id = l.id)
+              ) credits
+              WHERE credits.remaining > 0
+              ORDER BY credits.expires_at
+              LIMIT $2`
+	return r.findEntries(query, until, limit)
+}
+
+func (r *cashbackRepository) MarkExpiryWarned(creditID string) error {
+	query := `INSERT INTO cashback_expiry_warnings (credit_id, warned_at) VALUES ($1, NOW())
+              ON CONFLICT (credit_id) DO NOTHING`
+	_, err := r.db.Exec(query, creditID)
+	return err
+}
+
+func (r *cashbackRepository) ExpireCredit(creditID string) (*domain.CashbackEntry, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the customer first, as every balance change does, so a concurrent spend can't consume
+	// the same remainder between reading it and writing it off
+	var customerID string
+	err = tx.QueryRow(`SELECT customer_id FROM cashback_logs WHERE id = $1`, creditID).Scan(&customerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var balance float64
+	err = tx.QueryRow(`SELECT COALESCE(cashback_balance, 0) FROM customer WHERE id = $1 FOR UPDATE`, customerID).Scan(&balance)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	query := `SELECT ` + entryColumns + ` FROM cashback_logs l WHERE l.id = $1`
+	credit, err := scanEntry(tx.QueryRow(query, creditID))
+	if err != nil {
+		return nil, err
+	}
+
+	amount := math.Min(credit.Remaining, balance)
+	if amount <= 0 {
+		return nil, nil
+	}
+
+	expired := credit.Expire(amount)
+	if err := applyCashbackEntry(tx, expired); err != nil {
+		return nil, err
+	}
+	if err := insertConsumption(tx, credit.ID.String(), expired.ID.String(), expired.Amount); err != nil {
+		return nil, err
+	}
+
+	return expired, tx.Commit()
+}
+
+func (r *cashbackRepository) findEntries(query string, args ...any) ([]*domain.CashbackEntry, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*domain.CashbackEntry{}
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// applyCashbackEntry moves the customer's balance and appends the entry to the ledger in tx.
+// The balance never goes below zero; a debit that would do so fails with ErrInsufficientCashback.
+// A USED entry consumes the customer's oldest credits first.
+func applyCashbackEntry(tx *sql.Tx, entry *domain.CashbackEntry) error {
+	query := `UPDATE customer SET cashback_balance = COALESCE(cashback_balance, 0) + $1
+              WHERE id = $2 AND COALESCE(cashback_balance, 0) + $1 >= 0
+              RETURNING cashback_balance`
+	err := tx.QueryRow(query, entry.Delta(), entry.CustomerID).Scan(&entry.BalanceAfter)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrInsufficientCashback
+		}
+		return err
+	}
+
+	insert := `INSERT INTO cashback_logs
+               (id, customer_id, shop_id, related_order_id, type, amount, balance_after, expires_at, created_at)
+               VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err = tx.Exec(insert,
+		entry.ID, entry.CustomerID, entry.ShopID, entry.RelatedOrderID,
+		entry.Type, entry.Amount, entry.BalanceAfter, entry.ExpiresAt, entry.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if entry.Type == domain.CashbackUsed {
+		return consumeOldestCredits(tx, entry)
+	}
+	return nil
+}
+
+// consumeOldestCredits spreads a USED entry over the customer's credits, oldest first. Balance that
+// predates the ledger is not backed by any credit, so part of a spend may stay unallocated.
+func consumeOldestCredits(tx *sql.Tx, used *domain.CashbackEntry) error {
+	query := `SELECT * FROM (
+                  SELECT l.id, l.amount - COALESCE((SELECT SUM(c.amount) FROM cashback_consumptions c WHERE c.credit_id = l.id), 0) AS remaining
+                  FROM cashback_logs l
+                  WHERE l.customer_id = $1 AND l.type IN ('EARNED', 'REFUNDED')
+                    AND (l.expires_at IS NULL OR l.expires_at > NOW())
+                  ORDER BY l.created_at, l.id
+              ) credits
+              WHERE credits.remaining > 0`
+	rows, err := tx.Query(query, used.CustomerID)
+	if err != nil {
+		return err
+	}
+
+	type credit struct {
+		id        string
+		remaining float64
+	}
+	var credits []credit
+	for rows.Next() {
+		var c credit
+		if err := rows.Scan(&c.id, &c.remaining); err != nil {
+			rows.Close()
+			return err
+		}
+		credits = append(credits, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	left := used.Amount
+	for _, c := range credits {
+		if left <= 0 {
+			break
+		}
+		take := math.Min(c.remaining, left)
+		if err := insertConsumption(tx, c.id, used.ID.String(), take); err != nil {
+			return err
+		}
+		left -= take
+	}
+	return nil
+}
+
+// insertConsumption records that amount of a credit was taken by a USED or EXPIRED entry.
+func insertConsumption(tx *sql.Tx, creditID, debitID string, amount float64) error {
+	query := `INSERT INTO cashback_consumptions (id, credit_id, debit_id, amount, created_at)
+              VALUES (gen_random_uuid(), $1, $2, $3, NOW())`
+	_, err := tx.Exec(query, creditID, debitID, amount)
+	return err
+}
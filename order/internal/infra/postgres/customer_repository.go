@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"database/sql"
+	"miniature/pkg/authz"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// customerRepository runs on the database or, inside another repository's transaction, on the tx.
+type customerRepository struct {
+	db interface {
+		Exec(query string, args ...any) (sql.Result, error)
+		QueryRow(query string, args ...any) *sql.Row
+	}
+}
+
+func NewCustomerRepository(db *sql.DB) *customerRepository {
+	return &customerRepository{db: db}
+}
+
+func (r *customerRepository) FindIDByPhone(phone string) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.db.QueryRow(`SELECT id FROM customer WHERE phone = $1`, phone).Scan(&id)
+	if err != nil && err != sql.ErrNoRows {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+func (r *customerRepository) FindOrCreateByPhone(phone string) (uuid.UUID, error) {
+	query := `INSERT INTO customer (id, name, phone, role, total_spent, cashback_balance, created_at)
+              VALUES ($1, '', $2, $3, 0, 0, $4)
+              ON CONFLICT (phone) DO NOTHING`
+	if _, err := r.db.Exec(query, uuid.New(), phone, authz.RoleCustomer, time.Now()); err != nil {
+		return uuid.Nil, err
+	}
+
+	var id uuid.UUID
+	err := r.db.QueryRow(`SELECT id FROM customer WHERE phone = $1`, phone).Scan(&id)
+	return id, err
+}
@@ -0,0 +1,277 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"miniature/order/internal/domain"
+	"miniature/pkg/orderparse"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type draftRepository struct {
+	db *sql.DB
+}
+
+func NewDraftRepository(db *sql.DB) *draftRepository {
+	return &draftRepository{db: db}
+}
+
+const draftColumns = `id, shop_id, channel, source_text, customer_phone, delivery_address, status, order_id, created_at, updated_at`
+
+func scanDraft(row scanner) (*domain.DraftOrder, error) {
+	draft := &domain.DraftOrder{}
+	var orderID uuid.NullUUID
+	err := row.Scan(
+		&draft.ID, &draft.ShopID, &draft.Channel, &draft.SourceText, &draft.CustomerPhone, &draft.DeliveryAddress,
+		&draft.Status, &orderID, &draft.CreatedAt, &draft.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if orderID.Valid {
+		draft.OrderID = &orderID.UUID
+	}
+	return draft, nil
+}
+
+func (r *draftRepository) Create(draft *domain.DraftOrder) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO draft_orders
+              (id, shop_id, channel, source_text, customer_phone, delivery_address, status, created_at, updated_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err = tx.Exec(query,
+		draft.ID, draft.ShopID, draft.Channel, draft.SourceText, draft.CustomerPhone, draft.DeliveryAddress,
+		draft.Status, draft.CreatedAt, draft.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if err := insertDraftLines(tx, draft); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *draftRepository) FindByID(id string) (*domain.DraftOrder, error) {
+	draft, err := scanDraft(r.db.QueryRow(`SELECT `+draftColumns+` FROM draft_orders WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := loadDraftLines(r.db, []*domain.DraftOrder{draft}); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+func (r *draftRepository) FindByShopID(shopID string, status domain.DraftStatus) ([]*domain.DraftOrder, error) {
+	query := `SELECT ` + draftColumns + ` FROM draft_orders
+              WHERE shop_id = $1 AND ($2 = '' OR status = $2)
+              ORDER BY created_at DESC`
+	rows, err := r.db.Query(query, shopID, string(status))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drafts []*domain.DraftOrder
+	for rows.Next() {
+		draft, err := scanDraft(rows)
+		if err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, draft)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadDraftLines(r.db, drafts); err != nil {
+		return nil, err
+	}
+	return drafts, nil
+}
+
+func (r *draftRepository) Update(draft *domain.DraftOrder) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE draft_orders SET customer_phone = $1, delivery_address = $2, updated_at = $3
+              WHERE id = $4 AND status = $5`
+	result, err := tx.Exec(query, draft.CustomerPhone, draft.DeliveryAddress, draft.UpdatedAt, draft.ID, domain.DraftOpen)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrDraftClosed
+	}
+
+	if _, err := tx.Exec(`DELETE FROM draft_order_lines WHERE draft_id = $1`, draft.ID); err != nil {
+		return err
+	}
+	if err := insertDraftLines(tx, draft); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *draftRepository) Discard(id string) error {
+	result, err := r.db.Exec(
+		`UPDATE draft_orders SET status = $1, updated_at = NOW() WHERE id = $2 AND status = $3`,
+		domain.DraftDiscarded, id, domain.DraftOpen,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrDraftClosed
+	}
+	return nil
+}
+
+func (r *draftRepository) Accept(
+	id string,
+	build func(draft *domain.DraftOrder, customers domain.CustomerDirectory) (*domain.Order, *domain.StatusChange, error),
+) (*domain.Order, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	draft, err := scanDraft(tx.QueryRow(`SELECT `+draftColumns+` FROM draft_orders WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrDraftNotFound
+		}
+		return nil, err
+	}
+	if err := loadDraftLines(tx, []*domain.DraftOrder{draft}); err != nil {
+		return nil, err
+	}
+
+	order, created, err := build(draft, &customerRepository{db: tx})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertOrder(tx, order); err != nil {
+		return nil, err
+	}
+	if err := takeStock(tx, order.Items); err != nil {
+		return nil, err
+	}
+	if err := insertStatusChange(tx, created); err != nil {
+		return nil, err
+	}
+	if err := syncProductStats(tx, order.ID); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(
+		`UPDATE draft_orders SET status = $1, order_id = $2, updated_at = NOW() WHERE id = $3`,
+		domain.DraftAccepted, order.ID, draft.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, tx.Commit()
+}
+
+func insertDraftLines(tx *sql.Tx, draft *domain.DraftOrder) error {
+	query := `INSERT INTO draft_order_lines
+              (id, draft_id, position, requested_code, match_kind, product_id, quantity, variants)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	for i, line := range draft.Lines {
+		variants, err := json.Marshal(line.Variants)
+		if err != nil {
+			return err
+		}
+		var productID uuid.NullUUID
+		if line.ProductID != nil {
+			productID = uuid.NullUUID{UUID: *line.ProductID, Valid: true}
+		}
+		_, err = tx.Exec(query,
+			line.ID, draft.ID, i, line.RequestedCode, line.Match, productID, line.Quantity, variants,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadDraftLines fills in the lines of every draft with a single query. Names, codes and prices are
+// read from the products, so the seller reviews what the order would be placed with.
+func loadDraftLines(q querier, drafts []*domain.DraftOrder) error {
+	if len(drafts) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*domain.DraftOrder, len(drafts))
+	ids := make([]string, 0, len(drafts))
+	for _, draft := range drafts {
+		draft.Lines = []*domain.DraftLine{}
+		byID[draft.ID] = draft
+		ids = append(ids, draft.ID.String())
+	}
+
+	query := `SELECT l.id, l.draft_id, l.requested_code, l.match_kind, p.id, COALESCE(p.code, ''),
+                     COALESCE(p.name, ''), COALESCE(p.price, 0), l.quantity, l.variants
+              FROM draft_order_lines l
+              LEFT JOIN products p ON p.id = l.product_id
+              WHERE l.draft_id = ANY($1::uuid[])
+              ORDER BY l.draft_id, l.position`
+	rows, err := q.Query(query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		line := &domain.DraftLine{}
+		var productID uuid.NullUUID
+		var variants []byte
+		err := rows.Scan(
+			&line.ID, &line.DraftID, &line.RequestedCode, &line.Match, &productID, &line.ProductCode,
+			&line.ProductName, &line.UnitPrice, &line.Quantity, &variants,
+		)
+		if err != nil {
+			return err
+		}
+		if productID.Valid {
+			line.ProductID = &productID.UUID
+		} else {
+			// The matched product has been deleted since
+			line.Match = orderparse.MatchNone
+		}
+		if err := json.Unmarshal(variants, &line.Variants); err != nil {
+			return err
+		}
+		if draft, ok := byID[line.DraftID]; ok {
+			draft.Lines = append(draft.Lines, line)
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,288 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"miniature/order/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type exportRepository struct {
+	db *sql.DB
+}
+
+func NewExportRepository(db *sql.DB) *exportRepository {
+	return &exportRepository{db: db}
+}
+
+// exportQuery returns the query reading a dataset and its arguments. Its columns follow the
+// dataset's columns in the domain. Range bounds are passed as NULL when unset.
+func exportQuery(shopID string, options domain.ExportOptions) (string, []any, error) {
+	from, to := nullableTime(options.From), nullableTime(options.To)
+	switch options.Dataset {
+	case domain.ExportProducts:
+		query := `SELECT p.code, p.name, COALESCE(p.category, ''), COALESCE(p.description, ''), p.price, p.stock_quantity,
+                         COALESCE(p.is_active, FALSE), COALESCE(s.total_sold, 0), COALESCE(s.total_revenue, 0),
+                         s.last_purchased_at, p.created_at
+                  FROM products p
+                  LEFT JOIN product_stats s ON s.product_id = p.id
+                  WHERE p.shop_id = $1
+                  ORDER BY p.code`
+		return query, []any{shopID}, nil
+	case domain.ExportOrders:
+		query := `SELECT o.id, o.created_at, o.status, o.source, o.payment_status, COALESCE(c.name, ''), COALESCE(c.phone, ''),
+                         COALESCE(o.delivery_address, ''), o.total_amount, COALESCE(o.cashback_applied, 0),
+                         COALESCE(p.code, ''), COALESCE(p.name, ''), oi.quantity, oi.price_at_order, oi.quantity * oi.price_at_order
+                  FROM orders o
+                  JOIN order_items oi ON oi.order_id = o.id
+                  LEFT JOIN customer c ON c.id = o.customer_id
+                  LEFT JOIN products p ON p.id = oi.product_id
+                  WHERE o.shop_id = $1
+                    AND ($2::timestamptz IS NULL OR o.created_at >= $2)
+                    AND ($3::timestamptz IS NULL OR o.created_at < $3)
+                  ORDER BY o.created_at, o.id, oi.id`
+		return query, []any{shopID, from, to}, nil
+	case domain.ExportCustomers:
+		query := `SELECT COALESCE(c.name, ''), c.phone, COUNT(*), SUM(o.total_amount), MIN(o.created_at), MAX(o.created_at), c.created_at
+                  FROM orders o
+                  JOIN customer c ON c.id = o.customer_id
+                  WHERE o.shop_id = $1 AND o.status = ANY($2)
+                    AND ($3::timestamptz IS NULL OR o.created_at >= $3)
+                    AND ($4::timestamptz IS NULL OR o.created_at < $4)
+                  GROUP BY c.id
+                  ORDER BY MIN(o.created_at), c.id`
+		return query, []any{shopID, saleStatuses(), from, to}, nil
+	case domain.ExportCashback:
+		// balance_after is left out: it is the customer's balance across every shop
+		query := `SELECT l.id, l.created_at, COALESCE(c.name, ''), COALESCE(c.phone, ''), l.type, l.amount,
+                         l.related_order_id, l.expires_at
+                  FROM cashback_logs l
+                  LEFT JOIN customer c ON c.id = l.customer_id
+                  WHERE l.shop_id = $1
+                    AND ($2::timestamptz IS NULL OR l.created_at >= $2)
+                    AND ($3::timestamptz IS NULL OR l.created_at < $3)
+                  ORDER BY l.created_at, l.id`
+		return query, []any{shopID, from, to}, nil
+	default:
+		return "", nil, domain.ErrInvalidExportDataset
+	}
+}
+
+func nullableTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+// scanExportRow reads a row of exportQuery. NULL times are returned as nil so they export as empty cells.
+func scanExportRow(dataset domain.ExportDataset, rows *sql.Rows) ([]any, error) {
+	switch dataset {
+	case domain.ExportProducts:
+		var code, name, category, description string
+		var price, revenue float64
+		var stock, sold int
+		var active bool
+		var lastSoldAt sql.NullTime
+		var createdAt time.Time
+		err := rows.Scan(&code, &name, &category, &description, &price, &stock, &active, &sold, &revenue, &lastSoldAt, &createdAt)
+		if err != nil {
+			return nil, err
+		}
+		return []any{code, name, category, description, price, stock, active, sold, revenue, timeOrNil(lastSoldAt), createdAt}, nil
+	case domain.ExportOrders:
+		var orderID uuid.UUID
+		var placedAt time.Time
+		var status, source, paymentStatus, customerName, customerPhone, address, productCode, productName string
+		var total, cashback, unitPrice, lineTotal float64
+		var quantity int
+		err := rows.Scan(
+			&orderID, &placedAt, &status, &source, &paymentStatus, &customerName, &customerPhone, &address,
+			&total, &cashback, &productCode, &productName, &quantity, &unitPrice, &lineTotal,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return []any{
+			orderID.String(), placedAt, status, source, paymentStatus, customerName, customerPhone, address,
+			total, cashback, productCode, productName, quantity, unitPrice, lineTotal,
+		}, nil
+	case domain.ExportCustomers:
+		var name, phone string
+		var orders int
+		var spent float64
+		var firstOrderAt, lastOrderAt time.Time
+		var registeredAt sql.NullTime
+		if err := rows.Scan(&name, &phone, &orders, &spent, &firstOrderAt, &lastOrderAt, &registeredAt); err != nil {
+			return nil, err
+		}
+		return []any{name, phone, orders, spent, firstOrderAt, lastOrderAt, timeOrNil(registeredAt)}, nil
+	case domain.ExportCashback:
+		var entryID uuid.UUID
+		var createdAt time.Time
+		var customerName, customerPhone, entryType string
+		var amount float64
+		var orderID uuid.NullUUID
+		var expiresAt sql.NullTime
+		err := rows.Scan(&entryID, &createdAt, &customerName, &customerPhone, &entryType, &amount, &orderID, &expiresAt)
+		if err != nil {
+			return nil, err
+		}
+		var order any
+		if orderID.Valid {
+			order = orderID.UUID.String()
+		}
+		return []any{entryID.String(), createdAt, customerName, customerPhone, entryType, amount, order, timeOrNil(expiresAt)}, nil
+	default:
+		return nil, domain.ErrInvalidExportDataset
+	}
+}
+
+func timeOrNil(t sql.NullTime) any {
+	if !t.Valid {
+		return nil
+	}
+	return t.Time
+}
+
+func (r *exportRepository) Count(shopID string, options domain.ExportOptions) (int, error) {
+	query, args, err := exportQuery(shopID, options)
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	err = r.db.QueryRow(`SELECT COUNT(*) FROM (`+query+`) dataset`, args...).Scan(&count)
+	return count, err
+}
+
+func (r *exportRepository) Stream(shopID string, options domain.ExportOptions, fn func(row []any) error) error {
+	query, args, err := exportQuery(shopID, options)
+	if err != nil {
+		return err
+	}
+	// Rows are read off the connection as they are scanned, so only one is held at a time
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row, err := scanExportRow(options.Dataset, rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+const exportJobColumns = `id, shop_id, requested_by, dataset, format, headers, calendar, range_from, range_to,
+                          status, row_count, file_key, error, created_at, started_at, finished_at`
+
+func scanExportJob(row scanner) (*domain.ExportJob, error) {
+	job := &domain.ExportJob{}
+	var from, to, startedAt, finishedAt sql.NullTime
+	err := row.Scan(
+		&job.ID, &job.ShopID, &job.RequestedBy, &job.Dataset, &job.Format, &job.Headers, &job.Calendar, &from, &to,
+		&job.Status, &job.Rows, &job.FileKey, &job.Error, &job.CreatedAt, &startedAt, &finishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if from.Valid {
+		job.From = &from.Time
+	}
+	if to.Valid {
+		job.To = &to.Time
+	}
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	return job, nil
+}
+
+func (r *exportRepository) CreateJob(job *domain.ExportJob) error {
+	query := `INSERT INTO export_jobs (id, shop_id, requested_by, dataset, format, headers, calendar, range_from, range_to, status, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err := r.db.Exec(query,
+		job.ID, job.ShopID, job.RequestedBy, job.Dataset, job.Format, job.Headers, job.Calendar,
+		nullableTime(job.From), nullableTime(job.To), job.Status, job.CreatedAt,
+	)
+	return err
+}
+
+func (r *exportRepository) FindJob(id string) (*domain.ExportJob, error) {
+	job, err := scanExportJob(r.db.QueryRow(`SELECT `+exportJobColumns+` FROM export_jobs WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *exportRepository) FindJobsByShopID(shopID string) ([]*domain.ExportJob, error) {
+	query := `SELECT ` + exportJobColumns + ` FROM export_jobs WHERE shop_id = $1 ORDER BY created_at DESC`
+	rows, err := r.db.Query(query, shopID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*domain.ExportJob
+	for rows.Next() {
+		job, err := scanExportJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func (r *exportRepository) ClaimJob(staleBefore time.Time) (*domain.ExportJob, error) {
+	// SKIP LOCKED lets every instance claim a different job at the same time
+	query := `UPDATE export_jobs SET status = $1, started_at = NOW()
+              WHERE id = (
+                  SELECT id FROM export_jobs
+                  WHERE status = $2 OR (status = $1 AND started_at < $3)
+                  ORDER BY created_at
+                  LIMIT 1
+                  FOR UPDATE SKIP LOCKED
+              )
+              RETURNING ` + exportJobColumns
+	job, err := scanExportJob(r.db.QueryRow(query, domain.ExportRunning, domain.ExportPending, staleBefore))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *exportRepository) FinishJob(job *domain.ExportJob) error {
+	query := `UPDATE export_jobs SET status = $1, row_count = $2, file_key = $3, error = $4, finished_at = $5
+              WHERE id = $6 AND status = $7`
+	result, err := r.db.Exec(query, job.Status, job.Rows, job.FileKey, job.Error, job.FinishedAt, job.ID, domain.ExportRunning)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("export job %s is no longer running", job.ID)
+	}
+	return nil
+}
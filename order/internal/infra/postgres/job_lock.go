@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"hash/fnv"
+)
+
+type jobLock struct {
+	db *sql.DB
+}
+
+// NewJobLock returns a lock backed by Postgres session-level advisory locks, shared by every
+// instance connected to the same database.
+func NewJobLock(db *sql.DB) *jobLock {
+	return &jobLock{db: db}
+}
+
+func (l *jobLock) TryRun(name string, fn func() error) (bool, error) {
+	ctx := context.Background()
+
+	// Advisory locks belong to a session, so take and release it on one dedicated connection
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	key := lockKey(name)
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&acquired); err != nil {
+		return false, err
+	}
+	if !acquired {
+		return false, nil
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, key)
+
+	return true, fn()
+}
+
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,391 @@
+package postgres
+
+import (
+	"database/sql"
+	"miniature/order/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *repository {
+	return &repository{db: db}
+}
+
+const orderColumns = `id, shop_id, customer_id, status, source, total_amount, COALESCE(delivery_estimate, ''),
+                      COALESCE(delivery_address, ''), COALESCE(payment_proof_url, ''), payment_status,
+                      COALESCE(payment_rejection_reason, ''), payment_submitted_at, cashback_applied,
+                      created_at, confirmed_at`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanOrder(row scanner) (*domain.Order, error) {
+	order := &domain.Order{}
+	var customerID uuid.NullUUID
+	var paymentSubmittedAt, confirmedAt sql.NullTime
+	err := row.Scan(
+		&order.ID, &order.ShopID, &customerID, &order.Status, &order.Source, &order.TotalAmount, &order.DeliveryEstimate,
+		&order.DeliveryAddress, &order.PaymentProofURL, &order.PaymentStatus,
+		&order.PaymentRejectionReason, &paymentSubmittedAt, &order.CashbackApplied,
+		&order.CreatedAt, &confirmedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	order.CustomerID = customerID.UUID
+	if paymentSubmittedAt.Valid {
+		order.PaymentSubmittedAt = &paymentSubmittedAt.Time
+	}
+	if confirmedAt.Valid {
+		order.ConfirmedAt = &confirmedAt.Time
+	}
+	return order, nil
+}
+
+func (r *repository) Create(order *domain.Order, created *domain.StatusChange) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertOrder(tx, order); err != nil {
+		return err
+	}
+
+	if err := takeStock(tx, order.Items); err != nil {
+		return err
+	}
+
+	if err := insertStatusChange(tx, created); err != nil {
+		return err
+	}
+
+	if created.Cashback != nil {
+		if err := applyCashbackEntry(tx, created.Cashback); err != nil {
+			return err
+		}
+	}
+
+	if err := syncProductStats(tx, order.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertOrder writes the order row with its items and payments.
+func insertOrder(tx *sql.Tx, order *domain.Order) error {
+	// A walk-in sale has no customer
+	customerID := uuid.NullUUID{UUID: order.CustomerID, Valid: order.CustomerID != uuid.Nil}
+
+	query := `INSERT INTO orders
+              (id, shop_id, customer_id, status, source, total_amount, delivery_estimate, delivery_address,
+               payment_status, cashback_applied, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err := tx.Exec(query,
+		order.ID, order.ShopID, customerID, order.Status, order.Source, order.TotalAmount,
+		order.DeliveryEstimate, order.DeliveryAddress, order.PaymentStatus, order.CashbackApplied, order.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	itemQuery := `INSERT INTO order_items (id, order_id, product_id, quantity, price_at_order)
+                  VALUES ($1, $2, $3, $4, $5)`
+	for _, item := range order.Items {
+		_, err = tx.Exec(itemQuery, item.ID, item.OrderID, item.ProductID, item.Quantity, item.PriceAtOrder)
+		if err != nil {
+			return err
+		}
+	}
+
+	paymentQuery := `INSERT INTO order_payments (id, order_id, method, amount, created_at)
+                     VALUES ($1, $2, $3, $4, $5)`
+	for _, payment := range order.Payments {
+		_, err = tx.Exec(paymentQuery, payment.ID, payment.OrderID, payment.Method, payment.Amount, payment.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *repository) FindByID(id string) (*domain.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE id = $1`
+	order, err := scanOrder(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := r.loadItems([]*domain.Order{order}); err != nil {
+		return nil, err
+	}
+	if err := r.loadPayments([]*domain.Order{order}); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (r *repository) FindByCustomerID(customerID string) ([]*domain.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE customer_id = $1 ORDER BY created_at DESC`
+	return r.findMany(query, customerID)
+}
+
+func (r *repository) FindByShopID(shopID string, status domain.Status) ([]*domain.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders
+              WHERE shop_id = $1 AND ($2 = '' OR status = $2)
+              ORDER BY created_at DESC`
+	return r.findMany(query, shopID, string(status))
+}
+
+func (r *repository) FindAwaitingPaymentReview(shopID string) ([]*domain.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders
+              WHERE shop_id = $1 AND status = $2 AND payment_status = $3
+              ORDER BY payment_submitted_at`
+	return r.findMany(query, shopID, domain.StatusPending, domain.PaymentSubmitted)
+}
+
+func (r *repository) Update(order *domain.Order) error {
+	query := `UPDATE orders SET
+                delivery_estimate = $1,
+                delivery_address = $2
+              WHERE id = $3`
+	_, err := r.db.Exec(query, order.DeliveryEstimate, order.DeliveryAddress, order.ID)
+	return err
+}
+
+func (r *repository) UpdateStatus(order *domain.Order, change *domain.StatusChange) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := applyStatusChange(tx, order, change); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) UpdatePayment(order *domain.Order, from domain.PaymentStatus, change *domain.StatusChange) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE orders SET
+                payment_status = $1,
+                payment_rejection_reason = NULLIF($2, ''),
+                payment_proof_url = NULLIF($3, ''),
+                payment_submitted_at = $4
+              WHERE id = $5 AND payment_status = $6`
+	result, err := tx.Exec(query,
+		order.PaymentStatus, order.PaymentRejectionReason, order.PaymentProofURL, order.PaymentSubmittedAt,
+		order.ID, from,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrStatusConflict
+	}
+
+	if change != nil {
+		if err := applyStatusChange(tx, order, change); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// applyStatusChange saves a transition, its side effects on stock and product_stats and its history entry.
+func applyStatusChange(tx *sql.Tx, order *domain.Order, change *domain.StatusChange) error {
+	// Only move the order if nobody else has moved it since it was read
+	query := `UPDATE orders SET status = $1, confirmed_at = $2 WHERE id = $3 AND status = $4`
+	result, err := tx.Exec(query, order.Status, order.ConfirmedAt, order.ID, change.FromStatus)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrStatusConflict
+	}
+
+	if change.ReleasesStock() {
+		restockQuery := `UPDATE products p SET stock_quantity = p.stock_quantity + oi.quantity
+                         FROM order_items oi
+                         WHERE oi.order_id = $1 AND p.id = oi.product_id`
+		if _, err := tx.Exec(restockQuery, order.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := insertStatusChange(tx, change); err != nil {
+		return err
+	}
+
+	if change.Cashback != nil {
+		if err := applyCashbackEntry(tx, change.Cashback); err != nil {
+			return err
+		}
+	}
+	return syncProductStats(tx, order.ID)
+}
+
+func (r *repository) FindHistory(orderID string) ([]*domain.StatusChange, error) {
+	query := `SELECT id, order_id, COALESCE(from_status, ''), to_status, actor_id, actor_role, COALESCE(note, ''), created_at
+              FROM order_status_history
+              WHERE order_id = $1
+              ORDER BY created_at, id`
+	rows, err := r.db.Query(query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []*domain.StatusChange{}
+	for rows.Next() {
+		change := &domain.StatusChange{}
+		err := rows.Scan(
+			&change.ID, &change.OrderID, &change.FromStatus, &change.ToStatus,
+			&change.ActorID, &change.ActorRole, &change.Note, &change.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, change)
+	}
+	return history, rows.Err()
+}
+
+func insertStatusChange(tx *sql.Tx, change *domain.StatusChange) error {
+	query := `INSERT INTO order_status_history
+              (id, order_id, from_status, to_status, actor_id, actor_role, note, created_at)
+              VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, NULLIF($7, ''), $8)`
+	_, err := tx.Exec(query,
+		change.ID, change.OrderID, string(change.FromStatus), change.ToStatus,
+		change.ActorID, change.ActorRole, change.Note, change.CreatedAt,
+	)
+	return err
+}
+
+func (r *repository) findMany(query string, args ...any) ([]*domain.Order, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]*domain.Order, 0)
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadItems(orders); err != nil {
+		return nil, err
+	}
+	if err := r.loadPayments(orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// loadItems fills in the items of every order with a single query.
+func (r *repository) loadItems(orders []*domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*domain.Order, len(orders))
+	ids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		order.Items = []*domain.OrderItem{}
+		byID[order.ID] = order
+		ids = append(ids, order.ID.String())
+	}
+
+	query := `SELECT oi.id, oi.order_id, oi.product_id, COALESCE(p.name, ''), oi.quantity, oi.price_at_order
+              FROM order_items oi
+              LEFT JOIN products p ON p.id = oi.product_id
+              WHERE oi.order_id = ANY($1::uuid[])
+              ORDER BY oi.order_id, p.name`
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := &domain.OrderItem{}
+		err := rows.Scan(&item.ID, &item.OrderID, &item.ProductID, &item.ProductName, &item.Quantity, &item.PriceAtOrder)
+		if err != nil {
+			return err
+		}
+		if order, ok := byID[item.OrderID]; ok {
+			order.Items = append(order.Items, item)
+		}
+	}
+	return rows.Err()
+}
+
+// loadPayments fills in the payment parts of every order with a single query.
+func (r *repository) loadPayments(orders []*domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*domain.Order, len(orders))
+	ids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		byID[order.ID] = order
+		ids = append(ids, order.ID.String())
+	}
+
+	query := `SELECT id, order_id, method, amount, created_at
+              FROM order_payments
+              WHERE order_id = ANY($1::uuid[])
+              ORDER BY order_id, created_at, id`
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		payment := &domain.Payment{}
+		if err := rows.Scan(&payment.ID, &payment.OrderID, &payment.Method, &payment.Amount, &payment.CreatedAt); err != nil {
+			return err
+		}
+		if order, ok := byID[payment.OrderID]; ok {
+			order.Payments = append(order.Payments, payment)
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"database/sql"
+	"miniature/order/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type productRepository struct {
+	db *sql.DB
+}
+
+func NewProductRepository(db *sql.DB) *productRepository {
+	return &productRepository{db: db}
+}
+
+func (r *productRepository) FindByIDs(ids []uuid.UUID) (map[uuid.UUID]*domain.Product, error) {
+	idStrs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		idStrs = append(idStrs, id.String())
+	}
+
+	query := `SELECT id, shop_id, name, code, price, stock_quantity, is_active
+              FROM products WHERE id = ANY($1::uuid[])`
+	rows, err := r.db.Query(query, pq.Array(idStrs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make(map[uuid.UUID]*domain.Product, len(ids))
+	for rows.Next() {
+		product := &domain.Product{}
+		err := rows.Scan(
+			&product.ID, &product.ShopID, &product.Name, &product.Code, &product.Price, &product.StockQuantity, &product.IsActive,
+		)
+		if err != nil {
+			return nil, err
+		}
+		products[product.ID] = product
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *productRepository) FindByCode(shopID, code string) (*domain.Product, error) {
+	product := &domain.Product{}
+	query := `SELECT id, shop_id, name, code, price, stock_quantity, is_active
+              FROM products WHERE shop_id = $1 AND code = UPPER($2)`
+	err := r.db.QueryRow(query, shopID, code).Scan(
+		&product.ID, &product.ShopID, &product.Name, &product.Code, &product.Price, &product.StockQuantity, &product.IsActive,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return product, nil
+}
+
+func (r *productRepository) FindActiveByShopID(shopID string) ([]*domain.Product, error) {
+	query := `SELECT id, shop_id, name, code, price, stock_quantity, is_active
+              FROM products WHERE shop_id = $1 AND is_active ORDER BY code`
+	rows, err := r.db.Query(query, shopID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []*domain.Product
+	for rows.Next() {
+		product := &domain.Product{}
+		err := rows.Scan(
+			&product.ID, &product.ShopID, &product.Name, &product.Code, &product.Price, &product.StockQuantity, &product.IsActive,
+		)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	return products, rows.Err()
+}
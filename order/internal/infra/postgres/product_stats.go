@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"database/sql"
+	"miniature/order/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type productSales struct {
+	productID uuid.UUID
+	quantity  int
+	revenue   float64
+}
+
+// syncProductStats counts an order's items in product_stats once it is in a sale status and takes
+// them back out if it leaves them, which only happens when it is cancelled. orders.stats_applied
+// records whether they are counted, so moving between sale statuses changes nothing. Products are
+// updated in id order, as in takeStock, so orders sharing products cannot deadlock.
+func syncProductStats(tx *sql.Tx, orderID uuid.UUID) error {
+	var status domain.Status
+	var applied bool
+	var createdAt time.Time
+	err := tx.QueryRow(
+		`SELECT status, stats_applied, created_at FROM orders WHERE id = $1 FOR UPDATE`, orderID,
+	).Scan(&status, &applied, &createdAt)
+	if err != nil {
+		return err
+	}
+	if status.IsSale() == applied {
+		return nil
+	}
+
+	rows, err := tx.Query(
+		`SELECT product_id, SUM(quantity), SUM(quantity * price_at_order)
+         FROM order_items
+         WHERE order_id = $1 AND product_id IS NOT NULL
+         GROUP BY product_id
+         ORDER BY product_id`,
+		orderID,
+	)
+	if err != nil {
+		return err
+	}
+	var sales []productSales
+	for rows.Next() {
+		var sale productSales
+		if err := rows.Scan(&sale.productID, &sale.quantity, &sale.revenue); err != nil {
+			rows.Close()
+			return err
+		}
+		sales = append(sales, sale)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, sale := range sales {
+		if status.IsSale() {
+			_, err = tx.Exec(
+				`INSERT INTO product_stats (product_id, total_sold, total_revenue, last_purchased_at)
+                 VALUES ($1, $2, $3, $4)
+                 ON CONFLICT (product_id) DO UPDATE SET
+                     total_sold = COALESCE(product_stats.total_sold, 0) + EXCLUDED.total_sold,
+                     total_revenue = COALESCE(product_stats.total_revenue, 0) + EXCLUDED.total_revenue,
+                     last_purchased_at = GREATEST(product_stats.last_purchased_at, EXCLUDED.last_purchased_at)`,
+				sale.productID, sale.quantity, sale.revenue, createdAt,
+			)
+		} else {
+			// The product was last sold in whichever of its other counted orders is newest
+			_, err = tx.Exec(
+				`UPDATE product_stats SET
+                     total_sold = total_sold - $2,
+                     total_revenue = total_revenue - $3,
+                     last_purchased_at = (
+                         SELECT MAX(o.created_at)
+                         FROM order_items oi JOIN orders o ON o.id = oi.order_id
+                         WHERE oi.product_id = $1 AND o.stats_applied AND o.id <> $4
+                     )
+                 WHERE product_id = $1`,
+				sale.productID, sale.quantity, sale.revenue, orderID,
+			)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE orders SET stats_applied = $1 WHERE id = $2`, status.IsSale(), orderID)
+	return err
+}
+
+type productStatsRepository struct {
+	db *sql.DB
+}
+
+func NewProductStatsRepository(db *sql.DB) *productStatsRepository {
+	return &productStatsRepository{db: db}
+}
+
+func (r *productStatsRepository) Rebuild(apply bool) (*domain.StatsRebuild, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Every order write takes its lock on orders before touching product_stats, so holding this one
+	// keeps the history still without deadlocking against them
+	if _, err := tx.Exec(`LOCK TABLE orders IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return nil, err
+	}
+
+	statuses := saleStatuses()
+	result, err := tx.Exec(
+		`UPDATE orders SET stats_applied = (status = ANY($1)) WHERE stats_applied <> (status = ANY($1))`, statuses,
+	)
+	if err != nil {
+		return nil, err
+	}
+	flagged, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	expected, err := readStats(tx,
+		`SELECT oi.product_id, COALESCE(p.name, ''), SUM(oi.quantity), SUM(oi.quantity * oi.price_at_order), MAX(o.created_at)
+         FROM order_items oi
+         JOIN orders o ON o.id = oi.order_id
+         LEFT JOIN products p ON p.id = oi.product_id
+         WHERE o.stats_applied AND oi.product_id IS NOT NULL
+         GROUP BY oi.product_id, p.name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	recorded, err := readStats(tx,
+		`SELECT s.product_id, COALESCE(p.name, ''), COALESCE(s.total_sold, 0), COALESCE(s.total_revenue, 0), s.last_purchased_at
+         FROM product_stats s
+         LEFT JOIN products p ON p.id = s.product_id`,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rebuild := &domain.StatsRebuild{FlaggedOrders: int(flagged)}
+	for productID, want := range expected {
+		if got, ok := recorded[productID]; !ok || !got.Matches(want) {
+			rebuild.Drift = append(rebuild.Drift, &domain.StatsDrift{ProductID: productID, Recorded: got, Expected: want})
+		}
+	}
+	for productID, got := range recorded {
+		// A product that is no longer sold anywhere should have nothing recorded
+		if _, ok := expected[productID]; !ok && !got.Matches(&domain.ProductStats{}) {
+			rebuild.Drift = append(rebuild.Drift, &domain.StatsDrift{ProductID: productID, Recorded: got})
+		}
+	}
+
+	if !apply {
+		return rebuild, nil
+	}
+	if _, err := tx.Exec(`DELETE FROM product_stats`); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(
+		`INSERT INTO product_stats (product_id, total_sold, total_revenue, last_purchased_at)
+         SELECT oi.product_id, SUM(oi.quantity), SUM(oi.quantity * oi.price_at_order), MAX(o.created_at)
+         FROM order_items oi
+         JOIN orders o ON o.id = oi.order_id
+         WHERE o.stats_applied AND oi.product_id IS NOT NULL
+         GROUP BY oi.product_id`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rebuild, tx.Commit()
+}
+
+func readStats(tx *sql.Tx, query string) (map[uuid.UUID]*domain.ProductStats, error) {
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := map[uuid.UUID]*domain.ProductStats{}
+	for rows.Next() {
+		s := &domain.ProductStats{}
+		var lastPurchasedAt sql.NullTime
+		if err := rows.Scan(&s.ProductID, &s.ProductName, &s.TotalSold, &s.TotalRevenue, &lastPurchasedAt); err != nil {
+			return nil, err
+		}
+		if lastPurchasedAt.Valid {
+			s.LastPurchasedAt = &lastPurchasedAt.Time
+		}
+		stats[s.ProductID] = s
+	}
+	return stats, rows.Err()
+}
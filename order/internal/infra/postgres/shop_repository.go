@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"database/sql"
+	"miniature/pkg/authz"
+)
+
+type shopRepository struct {
+	db *sql.DB
+}
+
+func NewShopRepository(db *sql.DB) *shopRepository {
+	return &shopRepository{db: db}
+}
+
+// MemberRole returns the role of userID on the staff of shopID (shop_users table).
+// The owner recorded on the shop itself is always treated as OWNER.
+func (r *shopRepository) MemberRole(userIDStr, shopIDStr string) (authz.Role, error) {
+	var role sql.NullString
+
+	query := `SELECT CASE WHEN s.owner_id = $2 THEN 'OWNER' ELSE su.role END
+              FROM shops s
+              LEFT JOIN shop_users su ON su.shop_id = s.id AND su.user_id = $2
+              WHERE s.id = $1`
+	err := r.db.QueryRow(query, shopIDStr, userIDStr).Scan(&role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// Shop not found, so user cannot be a member
+			return "", nil
+		}
+		return "", err
+	}
+
+	return authz.Role(role.String), nil
+}
+
+func (r *shopRepository) ShopName(shopIDStr string) (string, error) {
+	var name string
+	err := r.db.QueryRow(`SELECT name FROM shops WHERE id = $1`, shopIDStr).Scan(&name)
+	if err != nil && err != sql.ErrNoRows {
+		return "", err
+	}
+	return name, nil
+}
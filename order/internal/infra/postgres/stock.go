@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"database/sql"
+	"miniature/order/internal/domain"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// takeStock decrements stock for every item with a conditional update, so concurrent checkouts
+// can never take the same units twice. Products are updated in id order to avoid deadlocks between
+// orders that share products.
+func takeStock(tx *sql.Tx, items []*domain.OrderItem) error {
+	sorted := make([]*domain.OrderItem, len(items))
+	copy(sorted, items)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ProductID.String() < sorted[j].ProductID.String()
+	})
+
+	var shortages []domain.StockShortage
+	for _, item := range sorted {
+		shortage, err := reserveStock(tx, item.ProductID, item.ProductName, item.Quantity)
+		if err != nil {
+			return err
+		}
+		if shortage != nil {
+			shortages = append(shortages, *shortage)
+		}
+	}
+
+	if len(shortages) > 0 {
+		return &domain.StockError{Shortages: shortages}
+	}
+	return nil
+}
+
+// reserveStock takes quantity units of a product out of stock, or returns the shortage if there aren't enough.
+func reserveStock(tx *sql.Tx, productID uuid.UUID, productName string, quantity int) (*domain.StockShortage, error) {
+	result, err := tx.Exec(
+		`UPDATE products SET stock_quantity = stock_quantity - $1 WHERE id = $2 AND stock_quantity >= $1`,
+		quantity, productID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 1 {
+		return nil, nil
+	}
+
+	var available int
+	err = tx.QueryRow(`SELECT stock_quantity FROM products WHERE id = $1`, productID).Scan(&available)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return &domain.StockShortage{
+		ProductID:   productID,
+		ProductName: productName,
+		Requested:   quantity,
+		Available:   available,
+	}, nil
+}
+
+func releaseStock(tx *sql.Tx, productID uuid.UUID, quantity int) error {
+	_, err := tx.Exec(`UPDATE products SET stock_quantity = stock_quantity + $1 WHERE id = $2`, quantity, productID)
+	return err
+}
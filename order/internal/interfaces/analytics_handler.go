@@ -0,0 +1,185 @@
+package interfaces
+
+import (
+	"errors"
+	"miniature/order/internal/domain"
+	"miniature/pkg/jalali"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	// defaultReportDays is the range reports cover when from is not given, ending with to.
+	defaultReportDays = 30
+	maxTopProducts    = 100
+)
+
+// reportRangeFromQuery reads the from and to dates, both inclusive, group_by and calendar of a
+// report request. Dates are days in Tehran, written in the requested calendar. It writes the error
+// response and returns false if they are invalid.
+func reportRangeFromQuery(c *gin.Context) (domain.ReportRange, bool) {
+	calendar, err := jalali.ParseCalendar(c.Query("calendar"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return domain.ReportRange{}, false
+	}
+
+	now := time.Now().In(jalali.Tehran)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, jalali.Tehran)
+	if raw := c.Query("to"); raw != "" {
+		if to, err = parseDate(calendar, raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to: " + err.Error()})
+			return domain.ReportRange{}, false
+		}
+	}
+	from := to.AddDate(0, 0, 1-defaultReportDays)
+	if raw := c.Query("from"); raw != "" {
+		if from, err = parseDate(calendar, raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from: " + err.Error()})
+			return domain.ReportRange{}, false
+		}
+	}
+
+	groupBy := domain.GroupByDay
+	if raw := c.Query("group_by"); raw != "" {
+		parsed, err := domain.ParseGrouping(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return domain.ReportRange{}, false
+		}
+		groupBy = parsed
+	}
+
+	r, err := domain.NewReportRange(from, to.AddDate(0, 0, 1), groupBy, calendar)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return domain.ReportRange{}, false
+	}
+	return r, true
+}
+
+// parseDate reads a day written in calendar and returns midnight at its start in Tehran.
+func parseDate(calendar jalali.Calendar, raw string) (time.Time, error) {
+	if calendar == jalali.Jalali {
+		date, err := jalali.ParseDate(raw)
+		if err != nil {
+			return time.Time{}, errors.New("must be a Jalali date like 1403/01/15")
+		}
+		return date.Time(jalali.Tehran), nil
+	}
+	date, err := time.ParseInLocation(dateLayout, raw, jalali.Tehran)
+	if err != nil {
+		return time.Time{}, errors.New("must be a date like 2024-04-03")
+	}
+	return date, nil
+}
+
+func (h *Handler) GetSalesReport(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	r, ok := reportRangeFromQuery(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.usecase.GetSalesReport(c.Param("shop_id"), userIDStr, r)
+	if err != nil {
+		respondOrderError(c, "could not build sales report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *Handler) GetTopProducts(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	r, ok := reportRangeFromQuery(c)
+	if !ok {
+		return
+	}
+
+	by, limit, ok := rankingFromQuery(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.usecase.GetTopProducts(c.Param("shop_id"), userIDStr, r, by, limit)
+	if err != nil {
+		respondOrderError(c, "could not build top products report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *Handler) GetCustomersReport(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	r, ok := reportRangeFromQuery(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.usecase.GetCustomersReport(c.Param("shop_id"), userIDStr, r)
+	if err != nil {
+		respondOrderError(c, "could not build customers report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *Handler) GetBestSellers(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	by, limit, ok := rankingFromQuery(c)
+	if !ok {
+		return
+	}
+
+	products, err := h.usecase.GetBestSellers(c.Param("shop_id"), userIDStr, by, limit)
+	if err != nil {
+		respondOrderError(c, "could not retrieve best sellers", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, products)
+}
+
+// rankingFromQuery reads the by and limit of a product ranking request. It writes the error
+// response and returns false if they are invalid; a missing limit is returned as zero.
+func rankingFromQuery(c *gin.Context) (domain.ProductRanking, int, bool) {
+	by := domain.RankByUnits
+	if raw := c.Query("by"); raw != "" {
+		parsed, err := domain.ParseProductRanking(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return "", 0, false
+		}
+		by = parsed
+	}
+
+	var limit int
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxTopProducts {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number from 1 to " + strconv.Itoa(maxTopProducts)})
+			return "", 0, false
+		}
+		limit = parsed
+	}
+	return by, limit, true
+}
@@ -0,0 +1,140 @@
+package interfaces
+
+import (
+	"miniature/order/internal/application"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) OpenCart(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.usecase.OpenCart(c.Param("shop_id"), userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not open cart", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newCartResponse(session))
+}
+
+func (h *Handler) GetCart(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.usecase.GetCart(c.Param("shop_id"), c.Param("session_id"), userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not retrieve cart", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCartResponse(session))
+}
+
+func (h *Handler) ScanCartItem(c *gin.Context) {
+	var req ScanItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.usecase.ScanCartItem(c.Param("shop_id"), c.Param("session_id"), userIDStr, req.Code, req.QR, req.Quantity)
+	if err != nil {
+		respondOrderError(c, "could not add item to cart", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCartResponse(session))
+}
+
+func (h *Handler) SetCartItemQuantity(c *gin.Context) {
+	var req SetCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.usecase.SetCartItemQuantity(
+		c.Param("shop_id"), c.Param("session_id"), c.Param("item_id"), userIDStr, *req.Quantity,
+	)
+	if err != nil {
+		respondOrderError(c, "could not update cart item", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCartResponse(session))
+}
+
+func (h *Handler) RemoveCartItem(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.usecase.SetCartItemQuantity(c.Param("shop_id"), c.Param("session_id"), c.Param("item_id"), userIDStr, 0)
+	if err != nil {
+		respondOrderError(c, "could not remove cart item", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCartResponse(session))
+}
+
+func (h *Handler) AbandonCart(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.AbandonCart(c.Param("shop_id"), c.Param("session_id"), userIDStr); err != nil {
+		respondOrderError(c, "could not abandon cart", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "cart abandoned, reserved stock released"})
+}
+
+func (h *Handler) CheckoutCart(c *gin.Context) {
+	var req CheckoutCartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	parts := make([]application.PaymentPart, 0, len(req.Payments))
+	for _, payment := range req.Payments {
+		parts = append(parts, application.PaymentPart{Method: payment.Method, Amount: payment.Amount})
+	}
+
+	receipt, err := h.usecase.CheckoutCart(c.Param("shop_id"), c.Param("session_id"), userIDStr, req.CustomerPhone, parts)
+	if err != nil {
+		respondOrderError(c, "could not check out cart", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, receipt)
+}
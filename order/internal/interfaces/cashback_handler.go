@@ -0,0 +1,58 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetMyCashback(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.usecase.GetCashback(userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not retrieve cashback", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+func (h *Handler) GetCashbackSettings(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.usecase.GetCashbackSettings(c.Param("shop_id"), userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not retrieve cashback settings", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *Handler) UpdateCashbackSettings(c *gin.Context) {
+	var req UpdateCashbackSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.usecase.UpdateCashbackSettings(c.Param("shop_id"), userIDStr, *req.Percent, req.ExpiryDays)
+	if err != nil {
+		respondOrderError(c, "could not update cashback settings", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
@@ -0,0 +1,53 @@
+package interfaces
+
+import (
+	"crypto/subtle"
+	"miniature/order/internal/application"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChannelKeyMiddleware lets trusted chat channels in by the shared key in X-Channel-Key.
+// Every request is refused while no key is configured.
+func ChannelKeyMiddleware(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		given := c.GetHeader("X-Channel-Key")
+		if key == "" || subtle.ConstantTimeCompare([]byte(given), []byte(key)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid channel key"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func (h *Handler) CreateChannelOrder(c *gin.Context) {
+	var req CreateChannelOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	items := make([]application.CodeItemRequest, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, application.CodeItemRequest{Code: item.Code, Quantity: item.Quantity})
+	}
+
+	order, err := h.usecase.PlaceChannelOrder(req.ShopID, req.CustomerPhone, items, req.DeliveryAddress)
+	if err != nil {
+		respondOrderError(c, "could not create order", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+func (h *Handler) GetChannelOrder(c *gin.Context) {
+	order, err := h.usecase.GetChannelOrder(c.Param("order_id"))
+	if err != nil {
+		respondOrderError(c, "could not retrieve order", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
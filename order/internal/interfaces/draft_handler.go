@@ -0,0 +1,158 @@
+package interfaces
+
+import (
+	"miniature/order/internal/application"
+	"miniature/order/internal/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) CreateChannelDraft(c *gin.Context) {
+	var req CreateChannelDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	draft, err := h.usecase.CreateChannelDraft(req.ShopID, req.Channel, req.CustomerPhone, req.DeliveryAddress, req.Text)
+	if err != nil {
+		respondOrderError(c, "could not create draft order", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, draft)
+}
+
+func (h *Handler) GetChannelDraft(c *gin.Context) {
+	draft, err := h.usecase.GetChannelDraft(c.Param("draft_id"))
+	if err != nil {
+		respondOrderError(c, "could not retrieve draft order", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, draft)
+}
+
+func (h *Handler) CreateShopDraft(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req CreateDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	draft, err := h.usecase.CreateShopDraft(c.Param("shop_id"), userIDStr, req.CustomerPhone, req.DeliveryAddress, req.Text)
+	if err != nil {
+		respondOrderError(c, "could not create draft order", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, draft)
+}
+
+func (h *Handler) GetShopDrafts(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var status domain.DraftStatus
+	if raw := c.Query("status"); raw != "" {
+		parsed, err := domain.ParseDraftStatus(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		status = parsed
+	}
+
+	drafts, err := h.usecase.GetShopDrafts(c.Param("shop_id"), userIDStr, status)
+	if err != nil {
+		respondOrderError(c, "could not retrieve draft orders", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, drafts)
+}
+
+func (h *Handler) GetShopDraft(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	draft, err := h.usecase.GetShopDraft(c.Param("shop_id"), c.Param("draft_id"), userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not retrieve draft order", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, draft)
+}
+
+func (h *Handler) UpdateShopDraft(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	update := application.DraftUpdate{CustomerPhone: req.CustomerPhone, DeliveryAddress: req.DeliveryAddress}
+	if req.Lines != nil {
+		update.Lines = make([]application.DraftLineRequest, 0, len(req.Lines))
+		for _, line := range req.Lines {
+			update.Lines = append(update.Lines, application.DraftLineRequest{
+				Code:     line.Code,
+				Quantity: line.Quantity,
+				Variants: line.Variants,
+			})
+		}
+	}
+
+	draft, err := h.usecase.UpdateShopDraft(c.Param("shop_id"), c.Param("draft_id"), userIDStr, update)
+	if err != nil {
+		respondOrderError(c, "could not update draft order", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, draft)
+}
+
+func (h *Handler) AcceptShopDraft(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.usecase.AcceptShopDraft(c.Param("shop_id"), c.Param("draft_id"), userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not accept draft order", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+func (h *Handler) DiscardShopDraft(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.DiscardShopDraft(c.Param("shop_id"), c.Param("draft_id"), userIDStr); err != nil {
+		respondOrderError(c, "could not discard draft order", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "draft order discarded"})
+}
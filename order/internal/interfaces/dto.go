@@ -0,0 +1,138 @@
+package interfaces
+
+import (
+	"fmt"
+	"miniature/order/internal/domain"
+)
+
+type OrderItemRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"required,gt=0"`
+}
+
+type CreateOrderRequest struct {
+	ShopID          string             `json:"shop_id" binding:"required"`
+	Items           []OrderItemRequest `json:"items" binding:"required,min=1,dive"`
+	DeliveryAddress string             `json:"delivery_address" binding:"required"`
+	// Cashback is how much of the customer's cashback balance to spend on this order.
+	Cashback float64 `json:"cashback" binding:"gte=0"`
+}
+
+// UpdateOrderRequest is used by shop staff. Nil fields are left unchanged.
+type UpdateOrderRequest struct {
+	Status           *string `json:"status"`
+	DeliveryEstimate *string `json:"delivery_estimate"`
+	Note             string  `json:"note"`
+}
+
+// ChangeStatusRequest is used by customers, e.g. to cancel an order or confirm it arrived.
+type ChangeStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+	Note   string `json:"note"`
+}
+
+type RejectPaymentRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type UpdateCashbackSettingsRequest struct {
+	Percent *float64 `json:"percent" binding:"required,gte=0,lte=100"`
+	// ExpiryDays is how long earned cashback stays spendable. Zero means it never expires.
+	ExpiryDays int `json:"expiry_days" binding:"gte=0"`
+}
+
+// ScanItemRequest adds a product to a cart by its code or by the payload read from its QR code.
+type ScanItemRequest struct {
+	Code     string `json:"code"`
+	QR       string `json:"qr"`
+	Quantity int    `json:"quantity" binding:"omitempty,gt=0"`
+}
+
+type SetCartItemRequest struct {
+	Quantity *int `json:"quantity" binding:"required,gte=0"`
+}
+
+type CartResponse struct {
+	*domain.CartSession
+	Total float64 `json:"total"`
+}
+
+func newCartResponse(session *domain.CartSession) CartResponse {
+	return CartResponse{CartSession: session, Total: session.Total()}
+}
+
+type PaymentPartRequest struct {
+	Method string  `json:"method" binding:"required"`
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+}
+
+// CheckoutCartRequest finalizes a cart. CustomerPhone is optional; Payments must add up to the cart total.
+type CheckoutCartRequest struct {
+	CustomerPhone string               `json:"customer_phone"`
+	Payments      []PaymentPartRequest `json:"payments" binding:"required,min=1,dive"`
+}
+
+type ChannelItemRequest struct {
+	Code     string `json:"code" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required,gt=0"`
+}
+
+// CreateChannelOrderRequest is an order a chat bot collected from a customer.
+type CreateChannelOrderRequest struct {
+	ShopID          string               `json:"shop_id" binding:"required,uuid"`
+	CustomerPhone   string               `json:"customer_phone" binding:"required"`
+	DeliveryAddress string               `json:"delivery_address" binding:"required"`
+	Items           []ChannelItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// CreateDraftRequest is a customer's free-text message to read into a draft order.
+type CreateDraftRequest struct {
+	Text            string `json:"text" binding:"required"`
+	CustomerPhone   string `json:"customer_phone"`
+	DeliveryAddress string `json:"delivery_address"`
+}
+
+// CreateChannelDraftRequest is a message a chat channel received for a shop.
+type CreateChannelDraftRequest struct {
+	CreateDraftRequest
+	ShopID  string `json:"shop_id" binding:"required,uuid"`
+	Channel string `json:"channel" binding:"required"`
+}
+
+type DraftLineRequest struct {
+	Code     string            `json:"code" binding:"required"`
+	Quantity int               `json:"quantity" binding:"required,gt=0"`
+	Variants map[string]string `json:"variants"`
+}
+
+// UpdateDraftRequest edits a draft. Omitted fields are left as they are; lines replaces every line.
+type UpdateDraftRequest struct {
+	CustomerPhone   *string            `json:"customer_phone"`
+	DeliveryAddress *string            `json:"delivery_address"`
+	Lines           []DraftLineRequest `json:"lines" binding:"omitempty,dive"`
+}
+
+// CreateExportJobRequest asks for an export to be written in the background. Format, headers and
+// calendar default to csv, en and gregorian; from and to are optional inclusive dates.
+type CreateExportJobRequest struct {
+	Dataset  string `json:"dataset" binding:"required"`
+	Format   string `json:"format"`
+	Headers  string `json:"headers"`
+	Calendar string `json:"calendar"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+}
+
+// ExportJobResponse is an export job with the link its file is downloaded from once it is DONE.
+type ExportJobResponse struct {
+	*domain.ExportJob
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+func newExportJobResponse(job *domain.ExportJob) *ExportJobResponse {
+	response := &ExportJobResponse{ExportJob: job}
+	if job.Status == domain.ExportDone {
+		response.DownloadURL = fmt.Sprintf("/v1/shops/%s/export-jobs/%s/download", job.ShopID, job.ID)
+	}
+	return response
+}
@@ -0,0 +1,184 @@
+package interfaces
+
+import (
+	"fmt"
+	"log"
+	"mime"
+	"miniature/order/internal/domain"
+	"miniature/pkg/jalali"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportOptions reads what a client asked to export. Dates are days in Tehran written in the
+// requested calendar; both ends are inclusive and either may be left out.
+func exportOptions(dataset, format, headers, calendar, from, to string) (domain.ExportOptions, error) {
+	parsedDataset, err := domain.ParseExportDataset(dataset)
+	if err != nil {
+		return domain.ExportOptions{}, err
+	}
+	parsedFormat, err := domain.ParseExportFormat(format)
+	if err != nil {
+		return domain.ExportOptions{}, err
+	}
+	parsedHeaders, err := domain.ParseHeaderLanguage(headers)
+	if err != nil {
+		return domain.ExportOptions{}, err
+	}
+	parsedCalendar, err := jalali.ParseCalendar(calendar)
+	if err != nil {
+		return domain.ExportOptions{}, err
+	}
+
+	var fromTime, toTime *time.Time
+	if from != "" {
+		day, err := parseDate(parsedCalendar, from)
+		if err != nil {
+			return domain.ExportOptions{}, fmt.Errorf("from: %w", err)
+		}
+		fromTime = &day
+	}
+	if to != "" {
+		day, err := parseDate(parsedCalendar, to)
+		if err != nil {
+			return domain.ExportOptions{}, fmt.Errorf("to: %w", err)
+		}
+		end := day.AddDate(0, 0, 1)
+		toTime = &end
+	}
+	return domain.NewExportOptions(parsedDataset, parsedFormat, parsedHeaders, parsedCalendar, fromTime, toTime)
+}
+
+func attachment(fileName string) string {
+	return mime.FormatMediaType("attachment", map[string]string{"filename": fileName})
+}
+
+// exportResponse sends the file's headers with the first bytes of the export, so an error found
+// before anything was written can still be answered with JSON.
+type exportResponse struct {
+	c       *gin.Context
+	options domain.ExportOptions
+	started bool
+}
+
+func (r *exportResponse) Write(p []byte) (int, error) {
+	if !r.started {
+		r.started = true
+		r.c.Header("Content-Type", r.options.Format.ContentType())
+		r.c.Header("Content-Disposition", attachment(r.options.FileName(time.Now())))
+		r.c.Status(http.StatusOK)
+	}
+	return r.c.Writer.Write(p)
+}
+
+func (h *Handler) ExportShopData(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	options, err := exportOptions(
+		c.Param("dataset"), c.Query("format"), c.Query("headers"), c.Query("calendar"), c.Query("from"), c.Query("to"),
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := &exportResponse{c: c, options: options}
+	err = h.usecase.ExportShopData(c.Param("shop_id"), userIDStr, options, response)
+	if err == nil {
+		return
+	}
+	if !response.started {
+		respondOrderError(c, "could not export shop data", err)
+		return
+	}
+
+	// Part of the file is already sent with a 200. Ending the response normally would pass the cut
+	// file off as complete, so the connection is dropped instead.
+	log.Printf("export of %s for shop %s failed after it started: %v", options.Dataset, c.Param("shop_id"), err)
+	if conn, _, hijackErr := c.Writer.Hijack(); hijackErr == nil {
+		conn.Close()
+	}
+	c.Abort()
+}
+
+func (h *Handler) CreateExportJob(c *gin.Context) {
+	var req CreateExportJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	options, err := exportOptions(req.Dataset, req.Format, req.Headers, req.Calendar, req.From, req.To)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := h.usecase.CreateExportJob(c.Param("shop_id"), userIDStr, options)
+	if err != nil {
+		respondOrderError(c, "could not create export job", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, newExportJobResponse(job))
+}
+
+func (h *Handler) GetExportJobs(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	jobs, err := h.usecase.GetExportJobs(c.Param("shop_id"), userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not retrieve export jobs", err)
+		return
+	}
+
+	responses := make([]*ExportJobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		responses = append(responses, newExportJobResponse(job))
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+func (h *Handler) GetExportJob(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.usecase.GetExportJob(c.Param("shop_id"), c.Param("job_id"), userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not retrieve export job", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newExportJobResponse(job))
+}
+
+func (h *Handler) DownloadExport(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	file, job, err := h.usecase.DownloadExport(c.Param("shop_id"), c.Param("job_id"), userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not download export", err)
+		return
+	}
+	defer file.Close()
+
+	c.DataFromReader(http.StatusOK, -1, job.Format.ContentType(), file, map[string]string{
+		"Content-Disposition": attachment(job.FileName()),
+	})
+}
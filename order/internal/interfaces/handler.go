@@ -0,0 +1,276 @@
+package interfaces
+
+import (
+	"errors"
+	"miniature/order/internal/application"
+	"miniature/order/internal/domain"
+	"miniature/pkg/phone"
+	"miniature/pkg/qrpayload"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	usecase application.Usecase
+}
+
+func NewHandler(u application.Usecase) *Handler {
+	return &Handler{usecase: u}
+}
+
+func (h *Handler) CreateOrder(c *gin.Context) {
+	var req CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	items := make([]application.ItemRequest, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, application.ItemRequest{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+
+	order, err := h.usecase.PlaceOrder(userIDStr, req.ShopID, items, req.DeliveryAddress, req.Cashback)
+	if err != nil {
+		respondOrderError(c, "could not place order", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+func (h *Handler) GetMyOrders(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	orders, err := h.usecase.GetCustomerOrders(userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not retrieve orders", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+func (h *Handler) GetOrder(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.usecase.GetOrder(c.Param("order_id"), userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not retrieve order", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *Handler) ChangeOrderStatus(c *gin.Context) {
+	var req ChangeStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	status, err := domain.ParseStatus(req.Status)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.usecase.ChangeOrderStatus(c.Param("order_id"), userIDStr, status, req.Note)
+	if err != nil {
+		respondOrderError(c, "could not change order status", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *Handler) GetOrderTimeline(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	timeline, err := h.usecase.GetOrderTimeline(c.Param("order_id"), userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not retrieve order timeline", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
+
+func (h *Handler) GetShopOrders(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var status domain.Status
+	if raw := c.Query("status"); raw != "" {
+		parsed, err := domain.ParseStatus(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		status = parsed
+	}
+
+	orders, err := h.usecase.GetShopOrders(c.Param("shop_id"), userIDStr, status)
+	if err != nil {
+		respondOrderError(c, "could not retrieve shop orders", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+func (h *Handler) GetShopOrder(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.usecase.GetShopOrder(c.Param("shop_id"), c.Param("order_id"), userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not retrieve order", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *Handler) UpdateShopOrder(c *gin.Context) {
+	var req UpdateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var status *domain.Status
+	if req.Status != nil {
+		parsed, err := domain.ParseStatus(*req.Status)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		status = &parsed
+	}
+
+	order, err := h.usecase.UpdateShopOrder(c.Param("shop_id"), c.Param("order_id"), userIDStr, status, req.DeliveryEstimate, req.Note)
+	if err != nil {
+		respondOrderError(c, "could not update order", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *Handler) GetShopOrderTimeline(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	timeline, err := h.usecase.GetShopOrderTimeline(c.Param("shop_id"), c.Param("order_id"), userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not retrieve order timeline", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
+
+func userIDFromContext(c *gin.Context) (string, bool) {
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found in context"})
+		return "", false
+	}
+	userIDStr, ok := userIDRaw.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "user_id is not of type string"})
+		return "", false
+	}
+	return userIDStr, true
+}
+
+func respondOrderError(c *gin.Context, message string, err error) {
+	var stockErr *domain.StockError
+	switch {
+	case errors.As(err, &stockErr):
+		c.JSON(http.StatusConflict, gin.H{"error": domain.ErrOutOfStock.Error(), "items": stockErr.Shortages})
+	case errors.Is(err, domain.ErrOrderNotFound),
+		errors.Is(err, domain.ErrShopNotFound),
+		errors.Is(err, domain.ErrFileNotFound),
+		errors.Is(err, domain.ErrCartNotFound),
+		errors.Is(err, domain.ErrCartItemNotFound),
+		errors.Is(err, domain.ErrCustomerNotFound),
+		errors.Is(err, domain.ErrDraftNotFound),
+		errors.Is(err, domain.ErrExportJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrForbidden),
+		errors.Is(err, domain.ErrTransitionNotAllowed):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrEmptyOrder),
+		errors.Is(err, domain.ErrInvalidQuantity),
+		errors.Is(err, domain.ErrProductNotFound),
+		errors.Is(err, domain.ErrInvalidStatus),
+		errors.Is(err, domain.ErrInvalidPaymentProof),
+		errors.Is(err, domain.ErrRejectionReasonRequired),
+		errors.Is(err, domain.ErrInvalidCashbackAmount),
+		errors.Is(err, domain.ErrCashbackExceedsTotal),
+		errors.Is(err, domain.ErrInvalidCashbackPercent),
+		errors.Is(err, domain.ErrScanRequired),
+		errors.Is(err, qrpayload.ErrInvalid),
+		errors.Is(err, domain.ErrInvalidPaymentMethod),
+		errors.Is(err, domain.ErrInvalidPaymentAmount),
+		errors.Is(err, domain.ErrPaymentMismatch),
+		errors.Is(err, domain.ErrCashbackNeedsCustomer),
+		errors.Is(err, phone.ErrInvalid),
+		errors.Is(err, phone.ErrNotMobile),
+		errors.Is(err, domain.ErrInvalidDraftStatus),
+		errors.Is(err, domain.ErrDraftTextRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrProductUnavailable),
+		errors.Is(err, domain.ErrInvalidTransition),
+		errors.Is(err, domain.ErrStatusConflict),
+		errors.Is(err, domain.ErrPaymentNotExpected),
+		errors.Is(err, domain.ErrNoPaymentToReview),
+		errors.Is(err, domain.ErrInsufficientCashback),
+		errors.Is(err, domain.ErrCartClosed),
+		errors.Is(err, domain.ErrCartExpired),
+		errors.Is(err, domain.ErrDraftClosed),
+		errors.Is(err, domain.ErrDraftUnmatched),
+		errors.Is(err, domain.ErrDraftPhoneRequired),
+		errors.Is(err, domain.ErrExportNotReady):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrPaymentProofTooLarge),
+		errors.Is(err, domain.ErrExportTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + ": " + err.Error()})
+	}
+}
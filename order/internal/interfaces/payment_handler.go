@@ -0,0 +1,126 @@
+package interfaces
+
+import (
+	"errors"
+	"miniature/order/internal/config"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) UploadPaymentProof(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	// Leave room for the multipart framing around the file itself
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.MaxPaymentProofSize+1<<20)
+	fileHeader, err := c.FormFile("receipt")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "payment proof is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "receipt file is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "could not read receipt file"})
+		return
+	}
+	defer file.Close()
+
+	order, err := h.usecase.SubmitPaymentProof(c.Param("order_id"), userIDStr, file, fileHeader.Size)
+	if err != nil {
+		respondOrderError(c, "could not upload payment proof", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *Handler) GetPaymentProof(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	file, contentType, err := h.usecase.GetPaymentProof(c.Param("order_id"), userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not retrieve payment proof", err)
+		return
+	}
+	defer file.Close()
+
+	c.DataFromReader(http.StatusOK, -1, contentType, file, nil)
+}
+
+func (h *Handler) GetShopPaymentProof(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	file, contentType, err := h.usecase.GetShopPaymentProof(c.Param("shop_id"), c.Param("order_id"), userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not retrieve payment proof", err)
+		return
+	}
+	defer file.Close()
+
+	c.DataFromReader(http.StatusOK, -1, contentType, file, nil)
+}
+
+func (h *Handler) GetPaymentQueue(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	orders, err := h.usecase.GetPaymentQueue(c.Param("shop_id"), userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not retrieve payments awaiting verification", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+func (h *Handler) ApprovePayment(c *gin.Context) {
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.usecase.ApprovePayment(c.Param("shop_id"), c.Param("order_id"), userIDStr)
+	if err != nil {
+		respondOrderError(c, "could not approve payment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *Handler) RejectPayment(c *gin.Context) {
+	var req RejectPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	userIDStr, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.usecase.RejectPayment(c.Param("shop_id"), c.Param("order_id"), userIDStr, req.Reason)
+	if err != nil {
+		respondOrderError(c, "could not reject payment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
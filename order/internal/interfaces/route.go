@@ -0,0 +1,122 @@
+package interfaces
+
+import (
+	"github.com/gin-gonic/gin"
+	"miniature/pkg/authz"
+	"miniature/pkg/token"
+)
+
+func NewRouter(handler *Handler, auth *token.Authenticator, channelKey string) *gin.Engine {
+	r := gin.Default()
+
+	// Health check endpoint
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "UP"})
+	})
+
+	v1 := r.Group("/v1")
+	{
+		orderRoutes := v1.Group("/orders")
+		orderRoutes.Use(AuthMiddleware(auth))
+		{
+			orderRoutes.POST("", authz.Require(authz.PermOrderCreate), handler.CreateOrder)
+			orderRoutes.GET("", authz.Require(authz.PermOrderRead), handler.GetMyOrders)
+			// Readable by the customer who placed it or by the shop's staff, see orderService.GetOrder
+			orderRoutes.GET("/:order_id", handler.GetOrder)
+			orderRoutes.GET("/:order_id/timeline", handler.GetOrderTimeline)
+			orderRoutes.POST("/:order_id/status", authz.Require(authz.PermOrderCreate), handler.ChangeOrderStatus)
+			orderRoutes.POST("/:order_id/payment-proof", authz.Require(authz.PermOrderCreate), handler.UploadPaymentProof)
+			orderRoutes.GET("/:order_id/payment-proof", handler.GetPaymentProof)
+		}
+
+		// Shop orders are authorized by the caller's role within the shop, see orderService
+		shopOrders := v1.Group("/shops/:shop_id/orders")
+		shopOrders.Use(AuthMiddleware(auth))
+		{
+			shopOrders.GET("", handler.GetShopOrders)
+			shopOrders.GET("/:order_id", handler.GetShopOrder)
+			shopOrders.PATCH("/:order_id", handler.UpdateShopOrder)
+			shopOrders.GET("/:order_id/timeline", handler.GetShopOrderTimeline)
+			shopOrders.GET("/:order_id/payment-proof", handler.GetShopPaymentProof)
+			shopOrders.POST("/:order_id/payment/approve", handler.ApprovePayment)
+			shopOrders.POST("/:order_id/payment/reject", handler.RejectPayment)
+		}
+
+		cashback := v1.Group("/cashback")
+		cashback.Use(AuthMiddleware(auth))
+		{
+			cashback.GET("", authz.Require(authz.PermCashbackRead), handler.GetMyCashback)
+		}
+
+		shopCashback := v1.Group("/shops/:shop_id/cashback-settings")
+		shopCashback.Use(AuthMiddleware(auth))
+		{
+			shopCashback.GET("", handler.GetCashbackSettings)
+			shopCashback.PUT("", handler.UpdateCashbackSettings)
+		}
+
+		// In-store carts are run by the shop's staff, see orderService.OpenCart
+		shopCarts := v1.Group("/shops/:shop_id/carts")
+		shopCarts.Use(AuthMiddleware(auth))
+		{
+			shopCarts.POST("", handler.OpenCart)
+			shopCarts.GET("/:session_id", handler.GetCart)
+			shopCarts.DELETE("/:session_id", handler.AbandonCart)
+			shopCarts.POST("/:session_id/items", handler.ScanCartItem)
+			shopCarts.PATCH("/:session_id/items/:item_id", handler.SetCartItemQuantity)
+			shopCarts.DELETE("/:session_id/items/:item_id", handler.RemoveCartItem)
+			shopCarts.POST("/:session_id/checkout", handler.CheckoutCart)
+		}
+
+		// Orders read out of customers' messages, reviewed by the shop's staff before they are placed
+		shopDrafts := v1.Group("/shops/:shop_id/drafts")
+		shopDrafts.Use(AuthMiddleware(auth))
+		{
+			shopDrafts.POST("", handler.CreateShopDraft)
+			shopDrafts.GET("", handler.GetShopDrafts)
+			shopDrafts.GET("/:draft_id", handler.GetShopDraft)
+			shopDrafts.PUT("/:draft_id", handler.UpdateShopDraft)
+			shopDrafts.DELETE("/:draft_id", handler.DiscardShopDraft)
+			shopDrafts.POST("/:draft_id/accept", handler.AcceptShopDraft)
+		}
+
+		// Reports are open to the shop's staff, see orderService.GetSalesReport
+		shopAnalytics := v1.Group("/shops/:shop_id/analytics")
+		shopAnalytics.Use(AuthMiddleware(auth))
+		{
+			shopAnalytics.GET("/sales", handler.GetSalesReport)
+			shopAnalytics.GET("/top-products", handler.GetTopProducts)
+			shopAnalytics.GET("/customers", handler.GetCustomersReport)
+			shopAnalytics.GET("/best-sellers", handler.GetBestSellers)
+		}
+
+		// Exports are open to the shop's owners, see orderService.ExportShopData. Large ones are
+		// written by export jobs and downloaded once DONE.
+		shopExports := v1.Group("/shops/:shop_id")
+		shopExports.Use(AuthMiddleware(auth))
+		{
+			shopExports.GET("/exports/:dataset", handler.ExportShopData)
+			shopExports.POST("/export-jobs", handler.CreateExportJob)
+			shopExports.GET("/export-jobs", handler.GetExportJobs)
+			shopExports.GET("/export-jobs/:job_id", handler.GetExportJob)
+			shopExports.GET("/export-jobs/:job_id/download", handler.DownloadExport)
+		}
+
+		shopPayments := v1.Group("/shops/:shop_id/payments")
+		shopPayments.Use(AuthMiddleware(auth))
+		{
+			shopPayments.GET("/pending", handler.GetPaymentQueue)
+		}
+
+		// Service-to-service endpoints for the chat bots
+		channel := v1.Group("/channel")
+		channel.Use(ChannelKeyMiddleware(channelKey))
+		{
+			channel.POST("/orders", handler.CreateChannelOrder)
+			channel.GET("/orders/:order_id", handler.GetChannelOrder)
+			channel.POST("/drafts", handler.CreateChannelDraft)
+			channel.GET("/drafts/:draft_id", handler.GetChannelDraft)
+		}
+	}
+	return r
+}
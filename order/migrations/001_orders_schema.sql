@@ -0,0 +1,27 @@
+-- orders and order_items, as described in schema.sql
+CREATE TABLE IF NOT EXISTS orders (
+    id UUID PRIMARY KEY,
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'PAID', 'CONFIRMED', 'SHIPPED', 'DELIVERED', 'CANCELLED')),
+    total_amount DECIMAL(12, 2) NOT NULL CHECK (total_amount >= 0),
+    delivery_estimate TEXT,
+    delivery_address TEXT,
+    payment_proof_url TEXT,
+    cashback_applied DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    confirmed_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    price_at_order DECIMAL(10, 2) NOT NULL CHECK (price_at_order >= 0)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_orders_shop_id ON orders(shop_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_customer_id ON orders(customer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items(order_id);
@@ -0,0 +1,19 @@
+-- Every status change of an order, written in the same transaction as the change itself
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    actor_id UUID NOT NULL,
+    actor_role TEXT NOT NULL,
+    note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON order_status_history(order_id, created_at);
+
+-- Orders placed before the history existed start their timeline at their current status
+INSERT INTO order_status_history (id, order_id, from_status, to_status, actor_id, actor_role, note, created_at)
+SELECT gen_random_uuid(), o.id, NULL, o.status, o.customer_id, 'CUSTOMER', 'recorded when history was introduced', o.created_at
+FROM orders o
+WHERE NOT EXISTS (SELECT 1 FROM order_status_history h WHERE h.order_id = o.id);
@@ -0,0 +1,15 @@
+-- Manual card-to-card payment verification
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS payment_status TEXT NOT NULL DEFAULT 'AWAITING_PROOF'
+        CHECK (payment_status IN ('AWAITING_PROOF', 'SUBMITTED', 'APPROVED', 'REJECTED')),
+    ADD COLUMN IF NOT EXISTS payment_rejection_reason TEXT,
+    ADD COLUMN IF NOT EXISTS payment_submitted_at TIMESTAMPTZ;
+
+-- Orders past PENDING were paid before verification existed
+UPDATE orders SET payment_status = 'APPROVED' WHERE status IN ('PAID', 'CONFIRMED', 'SHIPPED', 'DELIVERED');
+UPDATE orders SET payment_status = 'SUBMITTED', payment_submitted_at = created_at
+WHERE status = 'PENDING' AND payment_proof_url IS NOT NULL;
+
+-- Seller verification queue
+CREATE INDEX IF NOT EXISTS idx_orders_payment_review ON orders(shop_id, payment_submitted_at)
+    WHERE status = 'PENDING' AND payment_status = 'SUBMITTED';
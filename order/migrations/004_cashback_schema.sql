@@ -0,0 +1,38 @@
+-- Cashback ledger. cashback_logs is described in schema.sql; create it if this database predates it.
+CREATE TABLE IF NOT EXISTS cashback_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    customer_id UUID REFERENCES customers(id) ON DELETE CASCADE,
+    related_order_id UUID REFERENCES orders(id),
+    amount NUMERIC NOT NULL,
+    type TEXT CHECK (type IN ('EARNED', 'USED')) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE cashback_logs
+    ADD COLUMN IF NOT EXISTS shop_id UUID, -- no foreign key: the ledger outlives the shop
+    ADD COLUMN IF NOT EXISTS balance_after NUMERIC NOT NULL DEFAULT 0;
+
+-- Spent cashback is given back when its order is cancelled
+ALTER TABLE cashback_logs DROP CONSTRAINT IF EXISTS cashback_logs_type_check;
+ALTER TABLE cashback_logs ADD CONSTRAINT cashback_logs_type_check CHECK (type IN ('EARNED', 'USED', 'REFUNDED'));
+ALTER TABLE cashback_logs ADD CONSTRAINT cashback_logs_amount_check CHECK (amount > 0);
+
+CREATE INDEX IF NOT EXISTS idx_cashback_logs_customer_id ON cashback_logs(customer_id, created_at DESC);
+
+-- The ledger is append-only: corrections are new entries, never edits
+CREATE OR REPLACE FUNCTION cashback_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'cashback_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS cashback_logs_no_update ON cashback_logs;
+CREATE TRIGGER cashback_logs_no_update BEFORE UPDATE ON cashback_logs
+    FOR EACH ROW EXECUTE FUNCTION cashback_logs_append_only();
+
+-- Per-shop loyalty configuration; shops without a row give no cashback
+CREATE TABLE IF NOT EXISTS shop_cashback_settings (
+    shop_id UUID PRIMARY KEY REFERENCES shops(id) ON DELETE CASCADE,
+    percent NUMERIC(5, 2) NOT NULL CHECK (percent >= 0 AND percent <= 100),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,26 @@
+-- Cashback expiry
+ALTER TABLE shop_cashback_settings ADD COLUMN IF NOT EXISTS expiry_days INTEGER NOT NULL DEFAULT 0 CHECK (expiry_days >= 0);
+
+ALTER TABLE cashback_logs ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+ALTER TABLE cashback_logs DROP CONSTRAINT IF EXISTS cashback_logs_type_check;
+ALTER TABLE cashback_logs ADD CONSTRAINT cashback_logs_type_check
+    CHECK (type IN ('EARNED', 'USED', 'REFUNDED', 'EXPIRED'));
+
+CREATE INDEX IF NOT EXISTS idx_cashback_logs_expires_at ON cashback_logs(expires_at) WHERE type = 'EARNED';
+
+-- Which credits (EARNED, REFUNDED) each debit (USED, EXPIRED) took its amount from, oldest credit first
+CREATE TABLE IF NOT EXISTS cashback_consumptions (
+    id UUID PRIMARY KEY,
+    credit_id UUID NOT NULL REFERENCES cashback_logs(id) ON DELETE CASCADE,
+    debit_id UUID NOT NULL REFERENCES cashback_logs(id) ON DELETE CASCADE,
+    amount NUMERIC NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cashback_consumptions_credit_id ON cashback_consumptions(credit_id);
+
+-- Credits whose customer was already told they are about to expire
+CREATE TABLE IF NOT EXISTS cashback_expiry_warnings (
+    credit_id UUID PRIMARY KEY REFERENCES cashback_logs(id) ON DELETE CASCADE,
+    warned_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,25 @@
+-- In-store QR cart sessions
+CREATE TABLE IF NOT EXISTS cart_sessions (
+    id UUID PRIMARY KEY,
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    opened_by UUID NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('OPEN', 'CHECKED_OUT', 'ABANDONED', 'EXPIRED')),
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- cart_items is described in schema.sql; session_id holds a cart_sessions id
+CREATE TABLE IF NOT EXISTS cart_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id TEXT NOT NULL,
+    product_id UUID REFERENCES products(id) ON DELETE CASCADE,
+    quantity INTEGER DEFAULT 1,
+    scanned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    is_finalized BOOLEAN DEFAULT FALSE
+);
+
+ALTER TABLE cart_items ADD CONSTRAINT cart_items_quantity_check CHECK (quantity > 0);
+
+-- A product has one line per open cart
+CREATE UNIQUE INDEX IF NOT EXISTS uq_cart_items_open_product ON cart_items(session_id, product_id) WHERE NOT is_finalized;
+CREATE INDEX IF NOT EXISTS idx_cart_sessions_open_expiry ON cart_sessions(expires_at) WHERE status = 'OPEN';
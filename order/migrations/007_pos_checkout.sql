@@ -0,0 +1,14 @@
+-- In-store sales: orders checked out from a cart session, possibly for a walk-in customer
+ALTER TABLE orders ALTER COLUMN customer_id DROP NOT NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'ONLINE' CHECK (source IN ('ONLINE', 'POS'));
+
+-- Split payment parts of an in-store sale
+CREATE TABLE IF NOT EXISTS order_payments (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    method TEXT NOT NULL CHECK (method IN ('CASH', 'CARD', 'CASHBACK')),
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_payments_order_id ON order_payments(order_id);
@@ -0,0 +1,28 @@
+-- Orders read out of customers' free-text messages, waiting for the seller's review
+CREATE TABLE IF NOT EXISTS draft_orders (
+    id UUID PRIMARY KEY,
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    channel TEXT NOT NULL DEFAULT '',
+    source_text TEXT NOT NULL,
+    customer_phone TEXT NOT NULL DEFAULT '',
+    delivery_address TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL CHECK (status IN ('OPEN', 'ACCEPTED', 'DISCARDED')),
+    order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A line keeps the code as the customer wrote it; product_id is NULL if it matched no product
+CREATE TABLE IF NOT EXISTS draft_order_lines (
+    id UUID PRIMARY KEY,
+    draft_id UUID NOT NULL REFERENCES draft_orders(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    requested_code TEXT NOT NULL,
+    match_kind TEXT NOT NULL CHECK (match_kind IN ('EXACT', 'FUZZY', 'NONE')),
+    product_id UUID REFERENCES products(id) ON DELETE SET NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    variants JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS idx_draft_orders_shop_created ON draft_orders(shop_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_draft_order_lines_draft_id ON draft_order_lines(draft_id);
@@ -0,0 +1,28 @@
+-- product_stats is described in schema.sql. It counts the orders in a sale status (PAID through
+-- DELIVERED) and is updated in the transaction that moves an order in or out of them.
+CREATE TABLE IF NOT EXISTS product_stats (
+    product_id UUID PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    total_sold INTEGER DEFAULT 0,
+    total_revenue NUMERIC DEFAULT 0,
+    last_purchased_at TIMESTAMP
+);
+
+-- Orders are stamped with a time zone; so is the time a product was last sold
+ALTER TABLE product_stats ALTER COLUMN last_purchased_at TYPE TIMESTAMPTZ;
+
+-- Whether an order's items are currently counted in product_stats
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS stats_applied BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_order_items_product_id ON order_items(product_id);
+CREATE INDEX IF NOT EXISTS idx_product_stats_total_sold ON product_stats(total_sold DESC);
+
+-- Count the orders that were already sales
+UPDATE orders SET stats_applied = TRUE WHERE status IN ('PAID', 'CONFIRMED', 'SHIPPED', 'DELIVERED');
+
+DELETE FROM product_stats;
+INSERT INTO product_stats (product_id, total_sold, total_revenue, last_purchased_at)
+SELECT oi.product_id, SUM(oi.quantity), SUM(oi.quantity * oi.price_at_order), MAX(o.created_at)
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+WHERE o.stats_applied AND oi.product_id IS NOT NULL
+GROUP BY oi.product_id;
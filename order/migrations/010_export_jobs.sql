@@ -0,0 +1,25 @@
+-- Exports written in the background; the file is kept in file storage under file_key
+CREATE TABLE IF NOT EXISTS export_jobs (
+    id UUID PRIMARY KEY,
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    requested_by UUID NOT NULL,
+    dataset TEXT NOT NULL CHECK (dataset IN ('products', 'orders', 'customers', 'cashback')),
+    format TEXT NOT NULL CHECK (format IN ('csv', 'xlsx')),
+    headers TEXT NOT NULL CHECK (headers IN ('en', 'fa')),
+    calendar TEXT NOT NULL CHECK (calendar IN ('gregorian', 'jalali')),
+    range_from TIMESTAMPTZ,
+    range_to TIMESTAMPTZ,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'RUNNING', 'DONE', 'FAILED')),
+    row_count INTEGER NOT NULL DEFAULT 0,
+    file_key TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_export_jobs_shop_created ON export_jobs(shop_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_export_jobs_queue ON export_jobs(created_at) WHERE status IN ('PENDING', 'RUNNING');
+
+-- Exported ledger entries are read by shop in the order they were made
+CREATE INDEX IF NOT EXISTS idx_cashback_logs_shop_created ON cashback_logs(shop_id, created_at);
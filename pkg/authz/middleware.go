@@ -0,0 +1,42 @@
+package authz
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleFromContext returns the role AuthMiddleware stored in the request context.
+func RoleFromContext(c *gin.Context) (Role, bool) {
+	raw, exists := c.Get("role")
+	if !exists {
+		return "", false
+	}
+	str, ok := raw.(string)
+	if !ok {
+		return "", false
+	}
+	role, err := ParseRole(str)
+	if err != nil {
+		return "", false
+	}
+
+	return role, true
+}
+
+// Require aborts the request with 403 unless the caller's role is granted perm.
+// It must run after AuthMiddleware.
+func Require(perm Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, ok := RoleFromContext(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "role not found in context"})
+			return
+		}
+		if !Can(role, perm) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "permission denied: " + string(perm)})
+			return
+		}
+		c.Next()
+	}
+}
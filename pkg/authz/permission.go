@@ -0,0 +1,90 @@
+package authz
+
+// Permission names a single action a role may be allowed to perform.
+type Permission string
+
+const (
+	PermProfileRead   Permission = "profile:read"
+	PermProfileUpdate Permission = "profile:update"
+	PermTokenRevoke   Permission = "token:revoke"
+
+	PermShopCreate Permission = "shop:create"
+	PermShopRead   Permission = "shop:read"
+	PermShopUpdate Permission = "shop:update"
+	PermShopDelete Permission = "shop:delete"
+
+	PermShopMembersRead   Permission = "shop.members:read"
+	PermShopMembersManage Permission = "shop.members:manage"
+
+	PermProductCreate Permission = "product:create"
+	PermProductRead   Permission = "product:read"
+	PermProductUpdate Permission = "product:update"
+	PermProductDelete Permission = "product:delete"
+
+	PermOrderCreate Permission = "order:create"
+	PermOrderRead   Permission = "order:read"
+
+	PermShopOrdersRead   Permission = "shop.orders:read"
+	PermShopOrdersManage Permission = "shop.orders:manage"
+
+	PermShopAnalyticsRead Permission = "shop.analytics:read"
+	PermShopDataExport    Permission = "shop.data:export"
+
+	PermCashbackRead       Permission = "cashback:read"
+	PermShopCashbackManage Permission = "shop.cashback:manage"
+)
+
+var customerPermissions = []Permission{
+	PermProfileRead,
+	PermProfileUpdate,
+	PermShopRead,
+	PermProductRead,
+	PermOrderCreate,
+	PermOrderRead,
+	PermCashbackRead,
+}
+
+var sellerPermissions = append([]Permission{
+	PermShopCreate,
+	PermShopUpdate,
+	PermShopDelete,
+	PermShopMembersRead,
+	PermProductCreate,
+	PermProductUpdate,
+	PermProductDelete,
+	PermShopOrdersRead,
+	PermShopOrdersManage,
+	PermShopAnalyticsRead,
+}, customerPermissions...)
+
+var ownerPermissions = append([]Permission{
+	PermShopMembersManage,
+	PermShopCashbackManage,
+	PermShopDataExport,
+}, sellerPermissions...)
+
+// matrix lists what each role is allowed to do. ADMIN is handled separately and may do anything.
+// The same matrix is used for a user's platform role and for their role within a shop (OWNER/SELLER).
+var matrix = map[Role]map[Permission]bool{
+	RoleCustomer: setOf(customerPermissions),
+	RoleSeller:   setOf(sellerPermissions),
+	RoleOwner:    setOf(ownerPermissions),
+}
+
+// Can reports whether role is granted perm.
+func Can(role Role, perm Permission) bool {
+	if role == RoleAdmin {
+		return true
+	}
+
+	return matrix[role][perm]
+}
+
+func setOf(perms []Permission) map[Permission]bool {
+	set := make(map[Permission]bool, len(perms))
+	for _, p := range perms {
+		set[p] = true
+	}
+
+	return set
+}
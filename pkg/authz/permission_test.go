@@ -0,0 +1,71 @@
+package authz
+
+import "testing"
+
+func TestCan(t *testing.T) {
+	tests := []struct {
+		perm                           Permission
+		customer, seller, owner, admin bool
+	}{
+		{PermProfileRead, true, true, true, true},
+		{PermProfileUpdate, true, true, true, true},
+		{PermTokenRevoke, false, false, false, true},
+		{PermShopCreate, false, true, true, true},
+		{PermShopRead, true, true, true, true},
+		{PermShopUpdate, false, true, true, true},
+		{PermShopDelete, false, true, true, true},
+		{PermShopMembersRead, false, true, true, true},
+		{PermShopMembersManage, false, false, true, true},
+		{PermProductCreate, false, true, true, true},
+		{PermProductRead, true, true, true, true},
+		{PermProductUpdate, false, true, true, true},
+		{PermProductDelete, false, true, true, true},
+		{PermOrderCreate, true, true, true, true},
+		{PermOrderRead, true, true, true, true},
+		{PermShopOrdersRead, false, true, true, true},
+		{PermShopOrdersManage, false, true, true, true},
+		{PermShopAnalyticsRead, false, true, true, true},
+		{PermShopDataExport, false, false, true, true},
+		{PermCashbackRead, true, true, true, true},
+		{PermShopCashbackManage, false, false, true, true},
+		{"unknown:perm", false, false, false, true},
+	}
+	for _, tt := range tests {
+		for role, want := range map[Role]bool{
+			RoleCustomer: tt.customer, RoleSeller: tt.seller, RoleOwner: tt.owner, RoleAdmin: tt.admin, "GUEST": false,
+		} {
+			if got := Can(role, tt.perm); got != want {
+				t.Errorf("Can(%s, %s) = %t, want %t", role, tt.perm, got, want)
+			}
+		}
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	tests := []struct {
+		in           string
+		want         Role
+		selfRegister bool
+		shopRole     bool
+		wantErr      bool
+	}{
+		{"CUSTOMER", RoleCustomer, true, false, false},
+		{" seller ", RoleSeller, true, true, false},
+		{"Owner", RoleOwner, false, true, false},
+		{"admin", RoleAdmin, false, false, false},
+		{"", "", false, false, true},
+		{"ROOT", "", false, false, true},
+	}
+	for _, tt := range tests {
+		role, err := ParseRole(tt.in)
+		if role != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseRole(%q) = %q, %v, want %q", tt.in, role, err, tt.want)
+		}
+		if role.CanSelfRegister() != tt.selfRegister {
+			t.Errorf("%q.CanSelfRegister() = %t, want %t", role, role.CanSelfRegister(), tt.selfRegister)
+		}
+		if role.IsShopRole() != tt.shopRole {
+			t.Errorf("%q.IsShopRole() = %t, want %t", role, role.IsShopRole(), tt.shopRole)
+		}
+	}
+}
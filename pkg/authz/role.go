@@ -0,0 +1,50 @@
+package authz
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidRole = errors.New("invalid role")
+
+// Role is the platform-wide role carried in an access token.
+type Role string
+
+const (
+	RoleCustomer Role = "CUSTOMER"
+	RoleSeller   Role = "SELLER"
+	RoleOwner    Role = "OWNER"
+	RoleAdmin    Role = "ADMIN"
+)
+
+func ParseRole(s string) (Role, error) {
+	role := Role(strings.ToUpper(strings.TrimSpace(s)))
+	if !role.Valid() {
+		return "", ErrInvalidRole
+	}
+
+	return role, nil
+}
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleCustomer, RoleSeller, RoleOwner, RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r Role) String() string {
+	return string(r)
+}
+
+// CanSelfRegister reports whether a user may sign up with r. Admins and platform owners are appointed.
+func (r Role) CanSelfRegister() bool {
+	return r == RoleCustomer || r == RoleSeller
+}
+
+// IsShopRole reports whether r can be held by a member of a shop's staff.
+func (r Role) IsShopRole() bool {
+	return r == RoleOwner || r == RoleSeller
+}
@@ -0,0 +1,92 @@
+package jalali
+
+// The conversions go through Julian day numbers. Integer division truncates towards zero, as the
+// reference implementation's does.
+
+// breaks are the Jalali years the 33-year leap cycle is realigned in.
+var breaks = [...]int{
+	-61, 9, 38, 199, 426, 686, 756, 818, 1111, 1181, 1210,
+	1635, 2060, 2097, 2192, 2262, 2324, 2394, 2456, 3178,
+}
+
+const (
+	minYear = -61
+	maxYear = 3178
+)
+
+// calendarYear returns how many years year is after the last leap year (0 if it is one), the
+// Gregorian year it starts in and the day of March it starts on.
+func calendarYear(year int) (leap, gregorianYear, march int) {
+	gregorianYear = year + 621
+	leapJ := -14
+	jp := breaks[0]
+	jump := 0
+	for i := 1; i < len(breaks); i++ {
+		jm := breaks[i]
+		jump = jm - jp
+		if year < jm {
+			break
+		}
+		leapJ += jump/33*8 + jump%33/4
+		jp = jm
+	}
+	n := year - jp
+
+	leapJ += n/33*8 + (n%33+3)/4
+	if jump%33 == 4 && jump-n == 4 {
+		leapJ++
+	}
+	leapG := gregorianYear/4 - (gregorianYear/100+1)*3/4 - 150
+	march = 20 + leapJ - leapG
+
+	if jump-n < 6 {
+		n = n - jump + (jump+4)/33*33
+	}
+	leap = ((n+1)%33 - 1) % 4
+	if leap == -1 {
+		leap = 4
+	}
+	return leap, gregorianYear, march
+}
+
+func (d Date) dayNumber() int {
+	_, gregorianYear, march := calendarYear(d.Year)
+	return gregorianToDayNumber(gregorianYear, 3, march) + (d.Month-1)*31 - d.Month/7*(d.Month-7) + d.Day - 1
+}
+
+func fromDayNumber(jdn int) Date {
+	gregorianYear, _, _ := dayNumberToGregorian(jdn)
+	year := gregorianYear - 621
+	leap, _, march := calendarYear(year)
+	k := jdn - gregorianToDayNumber(gregorianYear, 3, march)
+
+	if k >= 0 {
+		if k <= 185 {
+			return Date{Year: year, Month: 1 + k/31, Day: k%31 + 1}
+		}
+		k -= 186
+	} else {
+		// The day is at the end of the year before, which started in the Gregorian year before
+		year--
+		k += 179
+		if leap == 1 {
+			k++
+		}
+	}
+	return Date{Year: year, Month: 7 + k/30, Day: k%30 + 1}
+}
+
+func gregorianToDayNumber(year, month, day int) int {
+	d := (year+(month-8)/6+100100)*1461/4 + (153*((month+9)%12)+2)/5 + day - 34840408
+	return d - (year+100100+(month-8)/6)/100*3/4 + 752
+}
+
+func dayNumberToGregorian(jdn int) (year, month, day int) {
+	j := 4*jdn + 139361631
+	j += (4*jdn+183187720)/146097*3/4*4 - 3908
+	i := j%1461/4*5 + 308
+	day = i%153/5 + 1
+	month = i/153%12 + 1
+	year = j/1461 - 100100 + (8-month)/6
+	return year, month, day
+}
@@ -0,0 +1,177 @@
+// Package jalali converts between the Gregorian and the Jalali (Solar Hijri) calendar, the one
+// Iranian sellers and customers date things in, and finds the Jalali months and weeks a time falls
+// in. Conversions follow the algorithm of the jalaali-js library, which matches the official
+// calendar for the years 1 to 3177.
+package jalali
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // Tehran must load on images without a time zone database
+)
+
+var (
+	ErrInvalidDate     = errors.New("invalid Jalali date")
+	ErrInvalidCalendar = errors.New("calendar must be gregorian or jalali")
+)
+
+// Tehran is the time zone shops work in. Dates without a time are taken to start at midnight there.
+var Tehran = mustLoadLocation("Asia/Tehran")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// Calendar is the calendar a client wants its dates in.
+type Calendar string
+
+const (
+	Gregorian Calendar = "gregorian"
+	Jalali    Calendar = "jalali"
+)
+
+// ParseCalendar reads a calendar name as given in a query string. An empty name means Gregorian.
+func ParseCalendar(s string) (Calendar, error) {
+	switch calendar := Calendar(strings.ToLower(strings.TrimSpace(s))); calendar {
+	case "":
+		return Gregorian, nil
+	case Gregorian, Jalali:
+		return calendar, nil
+	default:
+		return "", ErrInvalidCalendar
+	}
+}
+
+var monthNames = [...]string{
+	"فروردین", "اردیبهشت", "خرداد", "تیر", "مرداد", "شهریور",
+	"مهر", "آبان", "آذر", "دی", "بهمن", "اسفند",
+}
+
+// Date is a day in the Jalali calendar. Month runs from 1 (Farvardin) to 12 (Esfand).
+type Date struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+// NewDate checks that the day exists.
+func NewDate(year, month, day int) (Date, error) {
+	if year < minYear || year >= maxYear || month < 1 || month > 12 || day < 1 || day > DaysInMonth(year, month) {
+		return Date{}, ErrInvalidDate
+	}
+	return Date{Year: year, Month: month, Day: day}, nil
+}
+
+// ParseDate reads a date written as 1403-01-15 or 1403/01/15, in Latin or Persian digits.
+func ParseDate(s string) (Date, error) {
+	s = strings.Map(latinDigit, strings.TrimSpace(s))
+	parts := strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '/' })
+	if len(parts) != 3 {
+		return Date{}, ErrInvalidDate
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return Date{}, ErrInvalidDate
+		}
+		numbers[i] = n
+	}
+	return NewDate(numbers[0], numbers[1], numbers[2])
+}
+
+// latinDigit turns Persian and Arabic-Indic digits into Latin ones.
+func latinDigit(r rune) rune {
+	switch {
+	case r >= '۰' && r <= '۹':
+		return '0' + r - '۰'
+	case r >= '٠' && r <= '٩':
+		return '0' + r - '٠'
+	default:
+		return r
+	}
+}
+
+// FromTime returns the Jalali day t falls on in its own location.
+func FromTime(t time.Time) Date {
+	return fromDayNumber(gregorianToDayNumber(t.Year(), int(t.Month()), t.Day()))
+}
+
+// Time returns midnight at the start of the day in loc.
+func (d Date) Time(loc *time.Location) time.Time {
+	year, month, day := dayNumberToGregorian(d.dayNumber())
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc)
+}
+
+// AddMonths moves the date by n months, clamping the day to the length of the new month.
+func (d Date) AddMonths(n int) Date {
+	months := d.Year*12 + d.Month - 1 + n
+	year, month := months/12, months%12+1
+	day := min(d.Day, DaysInMonth(year, month))
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// MonthName is the Persian name of the date's month.
+func (d Date) MonthName() string {
+	return monthNames[d.Month-1]
+}
+
+// String formats the date as 1403/01/15.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d/%02d/%02d", d.Year, d.Month, d.Day)
+}
+
+// Format writes t as a Jalali date and time in Tehran, e.g. 1403/01/15 14:30:05.
+func Format(t time.Time) string {
+	t = t.In(Tehran)
+	return FromTime(t).String() + t.Format(" 15:04:05")
+}
+
+// FormatDate writes the Jalali day t falls on in Tehran, e.g. 1403/01/15.
+func FormatDate(t time.Time) string {
+	return FromTime(t.In(Tehran)).String()
+}
+
+// MonthStart returns midnight on the first day of the Jalali month t falls in, in t's location.
+func MonthStart(t time.Time) time.Time {
+	d := FromTime(t)
+	return Date{Year: d.Year, Month: d.Month, Day: 1}.Time(t.Location())
+}
+
+// NextMonthStart returns midnight on the first day of the Jalali month after the one t falls in.
+func NextMonthStart(t time.Time) time.Time {
+	d := FromTime(t)
+	return Date{Year: d.Year, Month: d.Month, Day: 1}.AddMonths(1).Time(t.Location())
+}
+
+// WeekStart returns midnight on the Saturday that starts the week t falls in, in t's location.
+func WeekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -(int(day.Weekday()+1) % 7))
+}
+
+// IsLeap reports whether Esfand has 30 days in year.
+func IsLeap(year int) bool {
+	leap, _, _ := calendarYear(year)
+	return leap == 0
+}
+
+func DaysInMonth(year, month int) int {
+	switch {
+	case month <= 6:
+		return 31
+	case month <= 11:
+		return 30
+	case IsLeap(year):
+		return 30
+	default:
+		return 29
+	}
+}
@@ -0,0 +1,166 @@
+package jalali
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFromTime(t *testing.T) {
+	tests := []struct {
+		gregorian string
+		want      Date
+	}{
+		{"1979-02-11", Date{1357, 11, 22}},
+		{"2021-03-20", Date{1399, 12, 30}},
+		{"2021-03-21", Date{1400, 1, 1}},
+		{"2023-03-21", Date{1402, 1, 1}},
+		{"2024-03-19", Date{1402, 12, 29}},
+		{"2024-03-20", Date{1403, 1, 1}},
+		{"2024-09-21", Date{1403, 6, 31}},
+		{"2024-09-22", Date{1403, 7, 1}},
+		{"2025-03-20", Date{1403, 12, 30}},
+		{"2025-03-21", Date{1404, 1, 1}},
+	}
+	for _, tt := range tests {
+		day, err := time.ParseInLocation(time.DateOnly, tt.gregorian, Tehran)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := FromTime(day); got != tt.want {
+			t.Errorf("FromTime(%s) = %s, want %s", tt.gregorian, got, tt.want)
+		}
+		if got := tt.want.Time(Tehran); !got.Equal(day) {
+			t.Errorf("%s.Time() = %s, want %s", tt.want, got.Format(time.DateOnly), tt.gregorian)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	start := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	previous := FromTime(start.AddDate(0, 0, -1))
+	for day := start; day.Year() < 2060; day = day.AddDate(0, 0, 1) {
+		d := FromTime(day)
+		if _, err := NewDate(d.Year, d.Month, d.Day); err != nil {
+			t.Fatalf("FromTime(%s) = %s, which does not exist", day.Format(time.DateOnly), d)
+		}
+		if got := d.Time(time.UTC); !got.Equal(day) {
+			t.Fatalf("%s.Time() = %s, want %s", d, got.Format(time.DateOnly), day.Format(time.DateOnly))
+		}
+		if d.Day != 1 && d != (Date{previous.Year, previous.Month, previous.Day + 1}) {
+			t.Fatalf("%s follows %s", d, previous)
+		}
+		previous = d
+	}
+}
+
+func TestIsLeap(t *testing.T) {
+	tests := map[int]bool{
+		1395: true, 1396: false, 1399: true, 1400: false, 1402: false, 1403: true, 1404: false, 1408: true,
+	}
+	for year, want := range tests {
+		if got := IsLeap(year); got != want {
+			t.Errorf("IsLeap(%d) = %t, want %t", year, got, want)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Date
+		wantErr bool
+	}{
+		{in: "1403-01-15", want: Date{1403, 1, 15}},
+		{in: "1403/1/15", want: Date{1403, 1, 15}},
+		{in: " ۱۴۰۳/۰۱/۱۵ ", want: Date{1403, 1, 15}},
+		{in: "١٤٠٣-٠١-١٥", want: Date{1403, 1, 15}},
+		{in: "1403/12/30", want: Date{1403, 12, 30}},
+		{in: "1402/12/30", wantErr: true},
+		{in: "1403/07/31", wantErr: true},
+		{in: "1403/13/01", wantErr: true},
+		{in: "1403/01", wantErr: true},
+		{in: "1403/aa/01", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDate(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseDate(%q) = %s, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDate(%q) = %s, %v; want %s", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseCalendar(t *testing.T) {
+	tests := map[string]Calendar{"": Gregorian, "gregorian": Gregorian, " Jalali ": Jalali}
+	for in, want := range tests {
+		if got, err := ParseCalendar(in); err != nil || got != want {
+			t.Errorf("ParseCalendar(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseCalendar("hijri"); err != ErrInvalidCalendar {
+		t.Errorf("ParseCalendar(hijri) error = %v, want ErrInvalidCalendar", err)
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		from Date
+		n    int
+		want Date
+	}{
+		{Date{1403, 1, 15}, 1, Date{1403, 2, 15}},
+		{Date{1403, 6, 31}, 1, Date{1403, 7, 30}},
+		{Date{1402, 11, 30}, 1, Date{1402, 12, 29}},
+		{Date{1403, 11, 30}, 1, Date{1403, 12, 30}},
+		{Date{1403, 12, 15}, 1, Date{1404, 1, 15}},
+		{Date{1403, 1, 15}, -1, Date{1402, 12, 15}},
+		{Date{1403, 5, 31}, 12, Date{1404, 5, 31}},
+	}
+	for _, tt := range tests {
+		if got := tt.from.AddMonths(tt.n); got != tt.want {
+			t.Errorf("%s.AddMonths(%d) = %s, want %s", tt.from, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestMonthAndWeekStart(t *testing.T) {
+	// 1403/01/22, a Wednesday
+	at := time.Date(2024, 4, 10, 15, 4, 5, 0, Tehran)
+	tests := []struct {
+		name string
+		got  time.Time
+		want time.Time
+	}{
+		{"MonthStart", MonthStart(at), time.Date(2024, 3, 20, 0, 0, 0, 0, Tehran)},
+		{"NextMonthStart", NextMonthStart(at), time.Date(2024, 4, 20, 0, 0, 0, 0, Tehran)},
+		{"NextMonthStart in Esfand", NextMonthStart(time.Date(2025, 3, 1, 0, 0, 0, 0, Tehran)), time.Date(2025, 3, 21, 0, 0, 0, 0, Tehran)},
+		{"WeekStart", WeekStart(at), time.Date(2024, 4, 6, 0, 0, 0, 0, Tehran)},
+		{"WeekStart on Saturday", WeekStart(time.Date(2024, 4, 6, 23, 0, 0, 0, Tehran)), time.Date(2024, 4, 6, 0, 0, 0, 0, Tehran)},
+		{"WeekStart on Friday", WeekStart(time.Date(2024, 4, 12, 9, 0, 0, 0, Tehran)), time.Date(2024, 4, 6, 0, 0, 0, 0, Tehran)},
+	}
+	for _, tt := range tests {
+		if !tt.got.Equal(tt.want) {
+			t.Errorf("%s = %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	// Tehran is UTC+3:30, so this is already the first day of 1403 there
+	at := time.Date(2024, 3, 19, 21, 0, 0, 0, time.UTC)
+	if got, want := Format(at), "1403/01/01 00:30:00"; got != want {
+		t.Errorf("Format = %q, want %q", got, want)
+	}
+	if got, want := FormatDate(at), "1403/01/01"; got != want {
+		t.Errorf("FormatDate = %q, want %q", got, want)
+	}
+	if got, want := FromTime(at.In(Tehran)).MonthName(), "فروردین"; got != want {
+		t.Errorf("MonthName = %q, want %q", got, want)
+	}
+}
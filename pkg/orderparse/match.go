@@ -0,0 +1,69 @@
+package orderparse
+
+import "strings"
+
+// MatchKind tells how sure a match is. Sellers should look at FUZZY matches before accepting.
+type MatchKind string
+
+const (
+	MatchExact MatchKind = "EXACT"
+	MatchFuzzy MatchKind = "FUZZY"
+	MatchNone  MatchKind = "NONE"
+)
+
+// minFuzzyLength keeps short codes from matching almost anything.
+const minFuzzyLength = 3
+
+// Match finds the code among known that the customer meant. Dashes are ignored, so "SL38" finds
+// "SL-38". Otherwise a single typo (a wrong, missing, extra or swapped character) is tolerated if
+// exactly one known code is that close.
+func Match(code string, known []string) (string, MatchKind) {
+	wanted := compact(code)
+	var candidate string
+	candidates := 0
+	for _, k := range known {
+		c := compact(k)
+		if c == wanted {
+			return k, MatchExact
+		}
+		if len(wanted) >= minFuzzyLength && distance(wanted, c) <= 1 {
+			candidate = k
+			candidates++
+		}
+	}
+	if candidates == 1 {
+		return candidate, MatchFuzzy
+	}
+	return "", MatchNone
+}
+
+func compact(code string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+// distance is the optimal string alignment distance: edits, insertions, deletions and swaps of
+// adjacent characters each count as one.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
@@ -0,0 +1,281 @@
+// Package orderparse pulls ordered products out of free text such as a chat message or a comment
+// under a post, e.g. "۲ تا SL38 سایز ۳۸ و یکی KB12" or "2x sl-38 size 38, kb12". Parse only reads
+// the text; Match resolves the codes it finds against a shop's codes, allowing for typos.
+package orderparse
+
+import (
+	"miniature/pkg/phone"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Line is one product the text asks for. Code is upper-cased without separators but may still be
+// misspelled. Quantity is 1 unless the text says otherwise.
+type Line struct {
+	Code     string            `json:"code"`
+	Quantity int               `json:"quantity"`
+	Variants map[string]string `json:"variants,omitempty"` // e.g. "size": "38", "color": "قرمز"
+}
+
+const maxQuantity = 1000
+
+// Parse returns the lines in the order their codes appear in text. A quantity written before a
+// code ("۲ تا SL38") or right after it ("SL38 x2") belongs to that code. A variant hint
+// ("سایز ۳۸", "color red") belongs to the code it follows, or to the next one if none came yet.
+// "و", "and", commas and line breaks end the part of the text about one product.
+//
+// A code written apart ("SL 38") is only read as one when its letters are upper-case or begin one
+// of the known codes, so "buy 2" is not taken for BUY2. known is normally the shop's codes.
+func Parse(text string, known ...string) []Line {
+	p := &parser{last: -1, prefixes: codePrefixes(known)}
+	tokens := tokenize(normalize(text))
+	for i := 0; i < len(tokens); i++ {
+		i += p.read(tokens, i) - 1
+	}
+	return p.lines
+}
+
+type parser struct {
+	lines []Line
+
+	// prefixes are the lower-cased letters the known codes start with
+	prefixes map[string]bool
+
+	// last is the line numbers and hints after a code attach to, -1 after a separator
+	last            int
+	lastHasQuantity bool
+
+	// Read before any code of the current part of the text
+	pendingQuantity int
+	pendingVariants map[string]string
+}
+
+// read consumes the token at i, and those following it that belong to it, and returns how many.
+func (p *parser) read(tokens []string, i int) int {
+	tok := strings.ToLower(tokens[i])
+	if tok == separator {
+		p.last = -1
+		p.pendingQuantity, p.pendingVariants = 0, nil
+		return 1
+	}
+
+	if key, ok := variantKeys[tok]; ok && i+1 < len(tokens) && tokens[i+1] != separator {
+		p.addVariant(key, strings.ToLower(tokens[i+1]))
+		return 2
+	}
+
+	if code, consumed := p.readCode(tokens, i); code != "" {
+		line := Line{Code: code, Quantity: 1, Variants: p.pendingVariants}
+		if p.pendingQuantity > 0 {
+			line.Quantity = p.pendingQuantity
+		}
+		p.lines = append(p.lines, line)
+		p.last = len(p.lines) - 1
+		p.lastHasQuantity = p.pendingQuantity > 0
+		p.pendingQuantity, p.pendingVariants = 0, nil
+		return consumed
+	}
+
+	if quantity, ok := readQuantity(tok); ok {
+		if p.last >= 0 && !p.lastHasQuantity {
+			p.lines[p.last].Quantity = quantity
+			p.lastHasQuantity = true
+		} else {
+			p.pendingQuantity = quantity
+		}
+	}
+	return 1
+}
+
+func (p *parser) addVariant(key, value string) {
+	if p.last < 0 {
+		if p.pendingVariants == nil {
+			p.pendingVariants = map[string]string{}
+		}
+		p.pendingVariants[key] = value
+		return
+	}
+	line := &p.lines[p.last]
+	if line.Variants == nil {
+		line.Variants = map[string]string{}
+	}
+	line.Variants[key] = value
+}
+
+// separator is the token "و", "and", commas and line breaks are turned into.
+const separator = "\x00"
+
+var separatorWords = map[string]bool{"و": true, "and": true, "+": true, "&": true}
+
+// unitWords go with a quantity and carry no meaning of their own.
+var unitWords = map[string]bool{
+	"تا": true, "عدد": true, "دونه": true, "دانه": true, "تای": true, "از": true,
+	"pcs": true, "pc": true, "x": true, "of": true,
+}
+
+var variantKeys = map[string]string{
+	"سایز": "size", "size": "size", "اندازه": "size",
+	"رنگ": "color", "color": "color", "colour": "color",
+}
+
+var numberWords = map[string]int{
+	"یک": 1, "یه": 1, "یکی": 1, "یدونه": 1, "دو": 2, "دوتا": 2, "سه": 3, "سهتا": 3, "چهار": 4, "پنج": 5,
+	"شش": 6, "شیش": 6, "هفت": 7, "هشت": 8, "نه": 9, "ده": 10,
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+}
+
+// normalize folds the ways the same text gets typed: Persian and Arabic digits, Arabic forms of
+// Persian letters and zero-width characters. Letter case is kept, since it tells codes from words.
+func normalize(text string) string {
+	text = phone.NormalizeDigits(text)
+	return strings.NewReplacer(
+		"ي", "ی", "ى", "ی", "ك", "ک", "ة", "ه", "‌", "", "‏", "", "‎", "",
+	).Replace(text)
+}
+
+// tokenize splits text into words, numbers and separators. Persian words glued to numbers are
+// split ("2تا" → "2", "تا"); latin letters and digits stay together since they may form a code.
+func tokenize(text string) []string {
+	runes := []rune(text)
+	var tokens []string
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			tok := b.String()
+			if separatorWords[strings.ToLower(tok)] {
+				tok = separator
+			}
+			tokens = append(tokens, tok)
+			b.Reset()
+		}
+	}
+
+	for i, r := range runes {
+		var prev, next rune
+		if i > 0 {
+			prev = runes[i-1]
+		}
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case r == '\n' || r == ',' || r == '،' || r == ';' || r == '؛':
+			flush()
+			tokens = append(tokens, separator)
+		case unicode.IsSpace(r) || r == '.' || r == ':' || r == '(' || r == ')' || r == '/':
+			flush()
+		case (r == 'x' || r == 'X' || r == '×' || r == '*') && !isLatinLetter(prev) && !isLatinLetter(next):
+			// "x2", "2x" and "2×" mark quantities; within latin words x is an ordinary letter
+			flush()
+			tokens = append(tokens, "x")
+		case b.Len() > 0 && isPersianLetter(r) != isPersianLetter(prev):
+			flush()
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// readCode reads a product code starting at tokens[i]: latin letters followed by digits, written
+// together ("SL38", "sl-38") or apart ("SL 38"). It returns the code and how many tokens it used.
+func (p *parser) readCode(tokens []string, i int) (string, int) {
+	tok := strings.ReplaceAll(tokens[i], "-", "")
+	if isCode(tok) {
+		return strings.ToUpper(tok), 1
+	}
+	if i+1 < len(tokens) && isDigits(tokens[i+1]) && p.isCodePrefix(tok) {
+		return strings.ToUpper(tok + tokens[i+1]), 2
+	}
+	return "", 0
+}
+
+// isCodePrefix reports whether a word written before a number is the letters of a code rather than
+// a word of the sentence, as in "SL 38" but not "buy 2": the letters of a known code, or two to four
+// upper-case letters that are not a unit or number word.
+func (p *parser) isCodePrefix(word string) bool {
+	if !isLatinWord(word) {
+		return false
+	}
+	lower := strings.ToLower(word)
+	if p.prefixes[lower] {
+		return true
+	}
+	return len(word) >= 2 && len(word) <= 4 && word == strings.ToUpper(word) &&
+		!unitWords[lower] && numberWords[lower] == 0
+}
+
+// codePrefixes returns the lower-cased latin letters each of codes starts with.
+func codePrefixes(codes []string) map[string]bool {
+	prefixes := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		end := strings.IndexFunc(code, func(r rune) bool { return !isLatinLetter(r) })
+		if end < 0 {
+			end = len(code)
+		}
+		if end > 0 {
+			prefixes[strings.ToLower(code[:end])] = true
+		}
+	}
+	return prefixes
+}
+
+func readQuantity(tok string) (int, bool) {
+	if n, ok := numberWords[tok]; ok {
+		return n, true
+	}
+	if isDigits(tok) {
+		n, err := strconv.Atoi(tok)
+		if err == nil && n > 0 && n <= maxQuantity {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// isCode reports whether tok is latin letters and digits, starts with a letter and has both.
+func isCode(tok string) bool {
+	var letters, digits int
+	for i, r := range tok {
+		switch {
+		case isLatinLetter(r):
+			letters++
+		case r >= '0' && r <= '9' && i > 0:
+			digits++
+		default:
+			return false
+		}
+	}
+	return letters > 0 && digits > 0
+}
+
+func isLatinWord(tok string) bool {
+	for _, r := range tok {
+		if !isLatinLetter(r) {
+			return false
+		}
+	}
+	return tok != ""
+}
+
+func isDigits(tok string) bool {
+	for _, r := range tok {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return tok != ""
+}
+
+func isLatinLetter(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+func isPersianLetter(r rune) bool {
+	return r >= 0x0600 && r <= 0x06FF && !(r >= '۰' && r <= '۹') && !(r >= '٠' && r <= '٩') && r != '،' && r != '؛'
+}
@@ -0,0 +1,116 @@
+package orderparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		known []string
+		want  []Line
+	}{
+		{
+			name: "persian digits and words",
+			text: "۲ تا SL38 سایز ۳۸ و یکی KB12",
+			want: []Line{
+				{Code: "SL38", Quantity: 2, Variants: map[string]string{"size": "38"}},
+				{Code: "KB12", Quantity: 1},
+			},
+		},
+		{
+			name: "quantity after the code",
+			text: "2x sl-38 size 38, kb12 x3",
+			want: []Line{
+				{Code: "SL38", Quantity: 2, Variants: map[string]string{"size": "38"}},
+				{Code: "KB12", Quantity: 3},
+			},
+		},
+		{
+			name: "english sentence",
+			text: "I want 2 of SL38 size 40 and 1 KB12",
+			want: []Line{
+				{Code: "SL38", Quantity: 2, Variants: map[string]string{"size": "40"}},
+				{Code: "KB12", Quantity: 1},
+			},
+		},
+		{
+			name: "lower-case word before a quantity",
+			text: "buy 2 SL38",
+			want: []Line{{Code: "SL38", Quantity: 2}},
+		},
+		{
+			name: "lower-case word before a quantity and a written-together code",
+			text: "need 3 kb12",
+			want: []Line{{Code: "KB12", Quantity: 3}},
+		},
+		{
+			name: "upper-case code written apart",
+			text: "SL 38 دو تا",
+			want: []Line{{Code: "SL38", Quantity: 2}},
+		},
+		{
+			name:  "known prefix written apart in lower case",
+			text:  "sl 38 رنگ قرمز",
+			known: []string{"SL-38", "KB12"},
+			want:  []Line{{Code: "SL38", Quantity: 1, Variants: map[string]string{"color": "قرمز"}}},
+		},
+		{
+			name:  "lower-case word that is not a known prefix",
+			text:  "need 3 sl38",
+			known: []string{"SL-38"},
+			want:  []Line{{Code: "SL38", Quantity: 3}},
+		},
+		{
+			name:  "generated single-letter code written apart",
+			text:  "p 07",
+			known: []string{"P07"},
+			want:  []Line{{Code: "P07", Quantity: 1}},
+		},
+		{
+			name: "arabic letters and zero-width non-joiner",
+			text: "يه‌دونه KB12",
+			want: []Line{{Code: "KB12", Quantity: 1}},
+		},
+		{
+			name: "no code",
+			text: "سلام، قیمت چنده؟",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.text, tt.known...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	known := []string{"SL-38", "SL-39", "KB12", "P01"}
+	tests := []struct {
+		code string
+		want string
+		kind MatchKind
+	}{
+		{"SL38", "SL-38", MatchExact},
+		{"sl-38", "SL-38", MatchExact},
+		{"KB12", "KB12", MatchExact},
+		{"KB21", "KB12", MatchFuzzy},  // swapped
+		{"KB122", "KB12", MatchFuzzy}, // extra
+		{"KB1", "KB12", MatchFuzzy},   // missing
+		{"SL3", "", MatchNone},        // as close to SL38 as to SL39
+		{"P0", "", MatchNone},         // too short to guess
+		{"ZZ99", "", MatchNone},
+	}
+	for _, tt := range tests {
+		got, kind := Match(tt.code, known)
+		if got != tt.want || kind != tt.kind {
+			t.Errorf("Match(%q) = %q, %s; want %q, %s", tt.code, got, kind, tt.want, tt.kind)
+		}
+	}
+}
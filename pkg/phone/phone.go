@@ -0,0 +1,107 @@
+// Package phone normalizes Iranian mobile numbers to E.164 (+989XXXXXXXXX).
+package phone
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalid   = errors.New("invalid phone number")
+	ErrNotMobile = errors.New("phone number is not an Iranian mobile number")
+)
+
+const (
+	countryCode = "98"
+	// nationalLength is the length of a mobile number without the leading 0 or country code, e.g. 9121234567.
+	nationalLength = 10
+)
+
+// Normalize accepts the usual ways a mobile number is typed, such as 09121234567,
+// +98 912 123 4567, 00989121234567, 9121234567 or the same in Persian or
+// Arabic-Indic digits, and returns it as +989121234567.
+func Normalize(input string) (string, error) {
+	digits, err := extractDigits(input)
+	if err != nil {
+		return "", err
+	}
+
+	var national string
+	switch {
+	case strings.HasPrefix(digits, "00"+countryCode):
+		national = digits[len("00"+countryCode):]
+	case strings.HasPrefix(digits, countryCode) && len(digits) == len(countryCode)+nationalLength:
+		national = digits[len(countryCode):]
+	case strings.HasPrefix(digits, "0"):
+		national = digits[1:]
+	default:
+		national = digits
+	}
+
+	if len(national) != nationalLength {
+		return "", ErrInvalid
+	}
+	if !isMobile(national) {
+		return "", ErrNotMobile
+	}
+
+	return "+" + countryCode + national, nil
+}
+
+// Local formats a normalized number the way Iranians usually write it, e.g. 09121234567.
+func Local(e164 string) string {
+	return "0" + strings.TrimPrefix(e164, "+"+countryCode)
+}
+
+// NormalizeDigits replaces Persian (۰-۹) and Arabic-Indic (٠-٩) digits with ASCII digits
+// and leaves every other rune untouched.
+func NormalizeDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '۰' && r <= '۹':
+			return '0' + (r - '۰')
+		case r >= '٠' && r <= '٩':
+			return '0' + (r - '٠')
+		default:
+			return r
+		}
+	}, s)
+}
+
+// extractDigits drops the separators people type between digit groups. A leading
+// + is allowed and dropped as well; any other character makes the input invalid.
+func extractDigits(input string) (string, error) {
+	s := strings.TrimSpace(NormalizeDigits(input))
+	s = strings.TrimPrefix(s, "+")
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ', r == '-', r == '.', r == '(', r == ')',
+			r == '\u200c', r == '\u200e', r == '\u200f', r == '\u00a0':
+			// separators and invisible direction marks copied from chat apps
+		default:
+			return "", ErrInvalid
+		}
+	}
+	if b.Len() == 0 {
+		return "", ErrInvalid
+	}
+
+	return b.String(), nil
+}
+
+// isMobile reports whether a 10 digit national number belongs to a mobile range (90x-94x, 99x).
+func isMobile(national string) bool {
+	if national[0] != '9' {
+		return false
+	}
+	switch national[1] {
+	case '0', '1', '2', '3', '4', '9':
+		return true
+	default:
+		return false
+	}
+}
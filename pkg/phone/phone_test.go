@@ -0,0 +1,69 @@
+package phone
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr error
+	}{
+		{in: "09121234567", want: "+989121234567"},
+		{in: "+98 912 123 4567", want: "+989121234567"},
+		{in: "+989121234567", want: "+989121234567"},
+		{in: "00989121234567", want: "+989121234567"},
+		{in: "989121234567", want: "+989121234567"},
+		{in: "9121234567", want: "+989121234567"},
+		{in: "0912-123-4567", want: "+989121234567"},
+		{in: "(0912) 123.4567", want: "+989121234567"},
+		{in: "  09901234567 ", want: "+989901234567"},
+		{in: "۰۹۱۲۱۲۳۴۵۶۷", want: "+989121234567"},
+		{in: "٠٩١٢١٢٣٤٥٦٧", want: "+989121234567"},
+		{in: "+98‎ 912‌123‏4567", want: "+989121234567"},
+		{in: "", wantErr: ErrInvalid},
+		{in: "+", wantErr: ErrInvalid},
+		{in: "0912123456", wantErr: ErrInvalid},
+		{in: "091212345678", wantErr: ErrInvalid},
+		{in: "0912abc4567", wantErr: ErrInvalid},
+		{in: "++989121234567", wantErr: ErrInvalid},
+		{in: "+1 415 555 2671", wantErr: ErrInvalid},
+		{in: "02112345678", wantErr: ErrNotMobile},
+		{in: "09512345678", wantErr: ErrNotMobile},
+		{in: "08121234567", wantErr: ErrNotMobile},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.in)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Normalize(%q) = %q, %v; want error %v", tt.in, got, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestLocal(t *testing.T) {
+	if got, want := Local("+989121234567"), "09121234567"; got != want {
+		t.Errorf("Local = %q, want %q", got, want)
+	}
+}
+
+func TestNormalizeDigits(t *testing.T) {
+	tests := map[string]string{
+		"۰۱۲۳۴۵۶۷۸۹":  "0123456789",
+		"٠١٢٣٤٥٦٧٨٩":  "0123456789",
+		"سایز ۳۸, x2": "سایز 38, x2",
+		"":            "",
+	}
+	for in, want := range tests {
+		if got := NormalizeDigits(in); got != want {
+			t.Errorf("NormalizeDigits(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
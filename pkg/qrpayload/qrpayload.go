@@ -0,0 +1,46 @@
+// Package qrpayload encodes and parses the text stored in product QR codes, e.g.
+// miniature://product/3f0c...?code=SL38. The product id makes the payload resolve to exactly one
+// product; the code is kept so a person reading the payload can tell which product it is.
+package qrpayload
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalid = errors.New("invalid QR payload")
+
+const (
+	scheme = "miniature"
+	host   = "product"
+)
+
+// Payload is what a product QR code points at.
+type Payload struct {
+	ProductID uuid.UUID
+	Code      string
+}
+
+func Encode(productID uuid.UUID, code string) string {
+	u := url.URL{Scheme: scheme, Host: host, Path: "/" + productID.String()}
+	if code != "" {
+		u.RawQuery = url.Values{"code": {code}}.Encode()
+	}
+	return u.String()
+}
+
+func Parse(s string) (Payload, error) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || u.Scheme != scheme || u.Host != host {
+		return Payload{}, ErrInvalid
+	}
+
+	productID, err := uuid.Parse(strings.Trim(u.Path, "/"))
+	if err != nil {
+		return Payload{}, ErrInvalid
+	}
+	return Payload{ProductID: productID, Code: u.Query().Get("code")}, nil
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"time"
+)
+
+// Limiter allows at most limit hits per key within each window.
+type Limiter struct {
+	store  Store
+	prefix string
+	limit  int
+	window time.Duration
+}
+
+// NewLimiter returns a limiter whose keys are namespaced by name, so several
+// limiters can share one store.
+func NewLimiter(store Store, name string, limit int, window time.Duration) *Limiter {
+	return &Limiter{store: store, prefix: "limit:" + name + ":", limit: limit, window: window}
+}
+
+// Allow records a hit for key. When the limit is exceeded it returns false and
+// how long the caller has to wait.
+func (l *Limiter) Allow(key string) (bool, time.Duration, error) {
+	count, resetAt, err := l.store.Hit(l.prefix+key, l.window)
+	if err != nil {
+		return false, 0, err
+	}
+	if count > l.limit {
+		return false, time.Until(resetAt), nil
+	}
+	return true, 0, nil
+}
+
+// Lockout blocks a key after repeated failures. Every threshold failures within
+// window lock the key again, each time for the next (longer) duration in durations.
+type Lockout struct {
+	store     Store
+	prefix    string
+	threshold int
+	window    time.Duration
+	durations []time.Duration
+}
+
+func NewLockout(store Store, name string, threshold int, window time.Duration, durations []time.Duration) *Lockout {
+	return &Lockout{
+		store:     store,
+		prefix:    "lockout:" + name + ":",
+		threshold: threshold,
+		window:    window,
+		durations: durations,
+	}
+}
+
+// Locked returns how long key remains locked, or zero if it is not.
+func (l *Lockout) Locked(key string) (time.Duration, error) {
+	until, err := l.store.BlockedUntil(l.prefix + key)
+	if err != nil || until.IsZero() {
+		return 0, err
+	}
+	return time.Until(until), nil
+}
+
+// Failure records a failed attempt and returns the lock duration if it triggered a lockout.
+func (l *Lockout) Failure(key string) (time.Duration, error) {
+	count, _, err := l.store.Hit(l.prefix+key, l.window)
+	if err != nil {
+		return 0, err
+	}
+	if count%l.threshold != 0 {
+		return 0, nil
+	}
+
+	level := count/l.threshold - 1
+	if level >= len(l.durations) {
+		level = len(l.durations) - 1
+	}
+	duration := l.durations[level]
+	if err := l.store.Block(l.prefix+key, time.Now().Add(duration)); err != nil {
+		return 0, err
+	}
+	return duration, nil
+}
+
+// Success forgets earlier failures of key.
+func (l *Lockout) Success(key string) error {
+	return l.store.Reset(l.prefix + key)
+}
@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	const window = 50 * time.Millisecond
+	store := NewMemoryStore()
+	byIP := NewLimiter(store, "ip", 3, window)
+	byPhone := NewLimiter(store, "phone", 1, window)
+
+	steps := []struct {
+		name    string
+		limiter *Limiter
+		key     string
+		wait    time.Duration // before the hit
+		want    bool
+	}{
+		{"first hit", byIP, "1.2.3.4", 0, true},
+		{"second hit", byIP, "1.2.3.4", 0, true},
+		{"last hit of the window", byIP, "1.2.3.4", 0, true},
+		{"over the limit", byIP, "1.2.3.4", 0, false},
+		{"still over the limit", byIP, "1.2.3.4", 0, false},
+		{"other key", byIP, "5.6.7.8", 0, true},
+		{"same key, other limiter", byPhone, "1.2.3.4", 0, true},
+		{"other limiter over its limit", byPhone, "1.2.3.4", 0, false},
+		{"next window", byIP, "1.2.3.4", window, true},
+		{"next window, other limiter", byPhone, "1.2.3.4", 0, true},
+	}
+	for _, step := range steps {
+		time.Sleep(step.wait)
+		ok, retryAfter, err := step.limiter.Allow(step.key)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if ok != step.want {
+			t.Errorf("%s: Allow = %t, want %t", step.name, ok, step.want)
+		}
+		if !ok && (retryAfter <= 0 || retryAfter > window) {
+			t.Errorf("%s: retry after %s, want the rest of the window", step.name, retryAfter)
+		}
+	}
+}
+
+func TestLockout(t *testing.T) {
+	const window = time.Hour
+	durations := []time.Duration{time.Minute, 5 * time.Minute, time.Hour}
+	lockout := NewLockout(NewMemoryStore(), "verify", 3, window, durations)
+
+	steps := []struct {
+		name       string
+		success    bool // record a success instead of a failure
+		wantLock   time.Duration
+		wantLocked bool
+	}{
+		{"first failure", false, 0, false},
+		{"second failure", false, 0, false},
+		{"threshold", false, time.Minute, true},
+		{"failure while locked", false, 0, true},
+		{"failure while locked", false, 0, true},
+		{"second threshold", false, 5 * time.Minute, true},
+		{"failures", false, 0, true},
+		{"failures", false, 0, true},
+		{"third threshold", false, time.Hour, true},
+		{"failures", false, 0, true},
+		{"failures", false, 0, true},
+		{"beyond the last duration", false, time.Hour, true},
+		{"success", true, 0, true},
+		{"failure after a success", false, 0, true},
+		{"failure after a success", false, 0, true},
+		{"threshold after a success starts over", false, time.Minute, true},
+	}
+	for _, step := range steps {
+		if step.success {
+			if err := lockout.Success("+989121234567"); err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			duration, err := lockout.Failure("+989121234567")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if duration != step.wantLock {
+				t.Errorf("%s: Failure = %s, want %s", step.name, duration, step.wantLock)
+			}
+		}
+		remaining, err := lockout.Locked("+989121234567")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (remaining > 0) != step.wantLocked {
+			t.Errorf("%s: locked for %s, want locked %t", step.name, remaining, step.wantLocked)
+		}
+	}
+
+	if remaining, _ := lockout.Locked("+989351234567"); remaining != 0 {
+		t.Errorf("another key is locked for %s", remaining)
+	}
+}
+
+func TestLockoutExpires(t *testing.T) {
+	lockout := NewLockout(NewMemoryStore(), "verify", 1, time.Hour, []time.Duration{50 * time.Millisecond})
+	if duration, _ := lockout.Failure("key"); duration != 50*time.Millisecond {
+		t.Fatalf("Failure = %s, want 50ms", duration)
+	}
+	if remaining, _ := lockout.Locked("key"); remaining <= 0 || remaining > 50*time.Millisecond {
+		t.Fatalf("locked for %s, want at most 50ms", remaining)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if remaining, _ := lockout.Locked("key"); remaining != 0 {
+		t.Errorf("still locked for %s after the lockout ended", remaining)
+	}
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type counter struct {
+	count   int
+	resetAt time.Time
+}
+
+// MemoryStore is an in-process Store. Expired entries are dropped lazily.
+type MemoryStore struct {
+	mu       sync.Mutex
+	counters map[string]counter
+	blocks   map[string]time.Time
+	lastGC   time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		counters: map[string]counter{},
+		blocks:   map[string]time.Time{},
+	}
+}
+
+func (s *MemoryStore) Hit(key string, window time.Duration) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.gc(now)
+
+	c := s.counters[key]
+	if !now.Before(c.resetAt) {
+		c = counter{resetAt: now.Add(window)}
+	}
+	c.count++
+	s.counters[key] = c
+
+	return c.count, c.resetAt, nil
+}
+
+func (s *MemoryStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.counters, key)
+	return nil
+}
+
+func (s *MemoryStore) Block(key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if until.After(s.blocks[key]) {
+		s.blocks[key] = until
+	}
+	return nil
+}
+
+func (s *MemoryStore) BlockedUntil(key string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until, ok := s.blocks[key]
+	if !ok || !time.Now().Before(until) {
+		return time.Time{}, nil
+	}
+	return until, nil
+}
+
+// gc drops expired entries at most once a minute. The caller must hold s.mu.
+func (s *MemoryStore) gc(now time.Time) {
+	if now.Sub(s.lastGC) < time.Minute {
+		return
+	}
+	s.lastGC = now
+
+	for key, c := range s.counters {
+		if !now.Before(c.resetAt) {
+			delete(s.counters, key)
+		}
+	}
+	for key, until := range s.blocks {
+		if !now.Before(until) {
+			delete(s.blocks, key)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PostgresStore shares counters between every instance of a service through the
+// rate_limit_counters and rate_limit_blocks tables.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Hit(key string, window time.Duration) (int, time.Time, error) {
+	query := `INSERT INTO rate_limit_counters (key, count, reset_at)
+              VALUES ($1, 1, NOW() + make_interval(secs => $2))
+              ON CONFLICT (key) DO UPDATE SET
+                  count    = CASE WHEN rate_limit_counters.reset_at <= NOW() THEN 1 ELSE rate_limit_counters.count + 1 END,
+                  reset_at = CASE WHEN rate_limit_counters.reset_at <= NOW() THEN EXCLUDED.reset_at ELSE rate_limit_counters.reset_at END
+              RETURNING count, reset_at`
+
+	var count int
+	var resetAt time.Time
+	err := s.db.QueryRow(query, key, window.Seconds()).Scan(&count, &resetAt)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return count, resetAt, nil
+}
+
+func (s *PostgresStore) Reset(key string) error {
+	_, err := s.db.Exec(`DELETE FROM rate_limit_counters WHERE key = $1`, key)
+	return err
+}
+
+func (s *PostgresStore) Block(key string, until time.Time) error {
+	query := `INSERT INTO rate_limit_blocks (key, blocked_until)
+              VALUES ($1, $2)
+              ON CONFLICT (key) DO UPDATE SET blocked_until = GREATEST(rate_limit_blocks.blocked_until, EXCLUDED.blocked_until)`
+	_, err := s.db.Exec(query, key, until)
+	return err
+}
+
+func (s *PostgresStore) BlockedUntil(key string) (time.Time, error) {
+	var until time.Time
+	err := s.db.QueryRow(`SELECT blocked_until FROM rate_limit_blocks WHERE key = $1 AND blocked_until > NOW()`, key).Scan(&until)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	return until, nil
+}
@@ -0,0 +1,18 @@
+// Package ratelimit provides fixed-window rate limiting and progressive lockouts
+// on top of a pluggable counter store.
+package ratelimit
+
+import "time"
+
+// Store keeps counters and blocks by key. MemoryStore is enough for a single
+// instance; PostgresStore shares state between instances.
+type Store interface {
+	// Hit increments the counter for key and returns its value and when the current window ends.
+	// A new window of length window starts when the previous one has ended.
+	Hit(key string, window time.Duration) (int, time.Time, error)
+	Reset(key string) error
+	// Block rejects key until the given time.
+	Block(key string, until time.Time) error
+	// BlockedUntil returns the end of the current block, or the zero time if key is not blocked.
+	BlockedUntil(key string) (time.Time, error)
+}
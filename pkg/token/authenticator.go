@@ -0,0 +1,34 @@
+package token
+
+import (
+	"errors"
+)
+
+var ErrTokenRevoked = errors.New("token revoked")
+
+// Authenticator validates access tokens and rejects the ones that have been revoked.
+type Authenticator struct {
+	validator   Validator
+	revocations RevocationStore
+}
+
+func NewAuthenticator(validator Validator, revocations RevocationStore) *Authenticator {
+	return &Authenticator{validator: validator, revocations: revocations}
+}
+
+func (a *Authenticator) Authenticate(tokenStr string) (*CustomClaims, error) {
+	claims, err := a.validator.ValidateToken(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := a.revocations.IsRevoked(claims)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+
+	return claims, nil
+}
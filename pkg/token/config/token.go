@@ -5,6 +5,17 @@ import (
 )
 
 var (
-	JWTSecret = []byte("your_secret_key_here")
-	TokenTTL  = time.Hour * 72 // 72 hours expiration
+	Issuer          = "miniature-customer"
+	AccessTokenTTL  = time.Minute * 15    // 15 minutes expiration
+	RefreshTokenTTL = time.Hour * 24 * 30 // 30 days expiration
+
+	// SigningKeysDir holds PEM-encoded PKCS#8 private keys (RSA or Ed25519), one per file.
+	// The file name without extension is used as the key ID (kid).
+	SigningKeysDir = "keys"
+	// ActiveKeyID selects the key that signs new tokens. When empty, the last kid in lexical order is used,
+	// so naming key files by date (e.g. 2025-01.pem) rotates them automatically.
+	ActiveKeyID = ""
+
+	JWKSURL             = "http://localhost:8080/.well-known/jwks.json"
+	JWKSRefreshInterval = time.Minute * 10
 )
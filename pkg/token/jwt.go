@@ -1,44 +1,52 @@
 package token
 
 import (
+	"crypto"
 	"errors"
 	"github.com/golang-jwt/jwt/v5"
 	"miniature/pkg/token/config"
-	"time"
 )
 
+var ErrInvalidToken = errors.New("invalid token")
+
 type CustomClaims struct {
 	UserID string `json:"user_id"`
 	Role   string `json:"role"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID, role string) (string, error) {
-	claims := CustomClaims{
-		UserID: userID,
-		Role:   role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(config.TokenTTL)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(config.JWTSecret)
+// Validator checks the signature and claims of an access token.
+type Validator interface {
+	ValidateToken(tokenStr string) (*CustomClaims, error)
 }
 
-func ValidateToken(tokenStr string) (*CustomClaims, error) {
+// publicKeyLookup returns the public key published under kid, or nil if it is unknown.
+type publicKeyLookup func(kid string) crypto.PublicKey
+
+func parseToken(tokenStr string, lookup publicKeyLookup) (*CustomClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenStr, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return config.JWTSecret, nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		public := lookup(kid)
+		if public == nil {
+			return nil, errors.New("unknown key id")
+		}
+		// The algorithm must match the key, otherwise a token could pick a weaker one.
+		if method := signingMethodFor(public); method == nil || method.Alg() != token.Method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		return public, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(config.Issuer),
+		jwt.WithExpirationRequired(),
+	)
 	if err != nil || !token.Valid {
-		return nil, errors.New("invalid token")
+		return nil, ErrInvalidToken
 	}
 	claims, ok := token.Claims.(*CustomClaims)
 	if !ok {
-		return nil, errors.New("invalid claims")
+		return nil, ErrInvalidToken
 	}
 
 	return claims, nil
-
 }
@@ -0,0 +1,103 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is a private key together with the kid it is published under.
+type SigningKey struct {
+	KID     string
+	Private crypto.Signer
+}
+
+func (k *SigningKey) Method() jwt.SigningMethod {
+	return signingMethodFor(k.Private.Public())
+}
+
+// LoadSigningKeys reads every *.pem file in dir. The file name without extension becomes the kid.
+func LoadSigningKeys(dir string) ([]*SigningKey, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	keys := make([]*SigningKey, 0, len(paths))
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		private, err := parsePrivateKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("loading %s: %w", path, err)
+		}
+		kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		keys = append(keys, &SigningKey{KID: kid, Private: private})
+	}
+
+	return keys, nil
+}
+
+// GenerateSigningKey creates a throwaway Ed25519 key. Tokens signed with it stop
+// validating once the process exits, so it is only suitable for local development.
+func GenerateSigningKey(kid string) (*SigningKey, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{KID: kid, Private: private}, nil
+}
+
+func parsePrivateKey(raw []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case ed25519.PrivateKey:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+func signingMethodFor(public crypto.PublicKey) jwt.SigningMethod {
+	switch public.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,69 @@
+package token
+
+import (
+	"database/sql"
+	"time"
+)
+
+// RevocationStore keeps track of access tokens that must be rejected before they expire.
+// Tokens are revoked one by one (by jti) or all at once for a user.
+type RevocationStore interface {
+	Revoke(jti, userID string, expiresAt time.Time) error
+	RevokeAllForUser(userID string, before time.Time) error
+	IsRevoked(claims *CustomClaims) (bool, error)
+}
+
+type postgresRevocationStore struct {
+	db *sql.DB
+}
+
+// NewPostgresRevocationStore returns a RevocationStore shared by every service through the
+// revoked_tokens and user_token_revocations tables.
+func NewPostgresRevocationStore(db *sql.DB) RevocationStore {
+	return &postgresRevocationStore{db: db}
+}
+
+func (s *postgresRevocationStore) Revoke(jti, userID string, expiresAt time.Time) error {
+	// Entries are only useful until the token would have expired anyway.
+	if _, err := s.db.Exec(`DELETE FROM revoked_tokens WHERE expires_at < NOW()`); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO revoked_tokens (jti, user_id, expires_at, revoked_at)
+              VALUES ($1, $2, $3, NOW())
+              ON CONFLICT (jti) DO NOTHING`
+	_, err := s.db.Exec(query, jti, userID, expiresAt)
+	return err
+}
+
+func (s *postgresRevocationStore) RevokeAllForUser(userID string, before time.Time) error {
+	query := `INSERT INTO user_token_revocations (user_id, revoked_before)
+              VALUES ($1, $2)
+              ON CONFLICT (user_id) DO UPDATE SET revoked_before = GREATEST(user_token_revocations.revoked_before, EXCLUDED.revoked_before)`
+	_, err := s.db.Exec(query, userID, before.Truncate(time.Second))
+	return err
+}
+
+func (s *postgresRevocationStore) IsRevoked(claims *CustomClaims) (bool, error) {
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+
+	var revoked bool
+	var revokedBefore sql.NullTime
+	query := `SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1),
+                     (SELECT revoked_before FROM user_token_revocations WHERE user_id = $2)`
+	err := s.db.QueryRow(query, claims.ID, claims.UserID).Scan(&revoked, &revokedBefore)
+	if err != nil {
+		return false, err
+	}
+	return revoked || revokedBefore.Valid && issuedBefore(issuedAt, revokedBefore.Time), nil
+}
+
+// issuedBefore reports whether a token issued at issuedAt falls under a revocation of every token
+// issued before revokedBefore. iat only has whole seconds, so a token from the same second as the
+// revocation cannot be told apart from one issued just before it and is revoked too.
+func issuedBefore(issuedAt, revokedBefore time.Time) bool {
+	return !issuedAt.Truncate(time.Second).After(revokedBefore.Truncate(time.Second))
+}
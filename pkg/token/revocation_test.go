@@ -0,0 +1,54 @@
+package token
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIssuedBefore(t *testing.T) {
+	// Logging out of every device at 12:00:00.5 is stored as 12:00:00.
+	revokedBefore := time.Date(2026, 3, 1, 12, 0, 0, 500_000_000, time.UTC).Truncate(time.Second)
+	tests := []struct {
+		name     string
+		issuedAt time.Time
+		want     bool
+	}{
+		{"a second earlier", time.Date(2026, 3, 1, 11, 59, 59, 0, time.UTC), true},
+		{"same second, before the logout", time.Date(2026, 3, 1, 12, 0, 0, 100_000_000, time.UTC), true},
+		{"same second as iat", time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), true},
+		{"same second, after the logout", time.Date(2026, 3, 1, 12, 0, 0, 900_000_000, time.UTC), true},
+		{"next second", time.Date(2026, 3, 1, 12, 0, 1, 0, time.UTC), false},
+		{"no iat", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		if got := issuedBefore(tt.issuedAt, revokedBefore); got != tt.want {
+			t.Errorf("%s: issuedBefore(%s) = %t, want %t", tt.name, tt.issuedAt.Format(time.RFC3339Nano), got, tt.want)
+		}
+	}
+}
+
+func TestIssuedBeforeOfASignedToken(t *testing.T) {
+	key, err := GenerateSigningKey("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := NewSigner([]*SigningKey{key}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenStr, err := signer.GenerateToken("user-1", "CUSTOMER")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logoutAll := time.Now()
+	claims, err := signer.ValidateToken(tokenStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// iat is cut to whole seconds, so it may be up to a second earlier than the token really was issued.
+	if !issuedBefore(claims.IssuedAt.Time, logoutAll.Truncate(time.Second)) {
+		t.Errorf("token issued at %s survived a logout of every device at %s", claims.IssuedAt, logoutAll)
+	}
+}
@@ -0,0 +1,115 @@
+package token
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"log"
+	"miniature/pkg/token/config"
+	"time"
+)
+
+// Signer issues access tokens. Only the customer service holds one; every other
+// service verifies tokens with the public keys it publishes.
+type Signer struct {
+	keys   map[string]*SigningKey
+	order  []string
+	active *SigningKey
+}
+
+// NewSigner signs with the key identified by activeKID, or with the last key when it is empty.
+// The remaining keys are still published so tokens signed before a rotation keep validating.
+func NewSigner(keys []*SigningKey, activeKID string) (*Signer, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys")
+	}
+
+	s := &Signer{keys: make(map[string]*SigningKey, len(keys))}
+	for _, key := range keys {
+		if key.Method() == nil {
+			return nil, fmt.Errorf("key %s: unsupported key type", key.KID)
+		}
+		if _, exists := s.keys[key.KID]; exists {
+			return nil, fmt.Errorf("duplicate key id %s", key.KID)
+		}
+		s.keys[key.KID] = key
+		s.order = append(s.order, key.KID)
+	}
+
+	if activeKID == "" {
+		activeKID = s.order[len(s.order)-1]
+	}
+	active, ok := s.keys[activeKID]
+	if !ok {
+		return nil, fmt.Errorf("active key %s not found", activeKID)
+	}
+	s.active = active
+
+	return s, nil
+}
+
+// NewSignerFromConfig loads the keys in config.SigningKeysDir. If the directory holds no
+// keys it falls back to a temporary key, which is only acceptable in development.
+func NewSignerFromConfig() (*Signer, error) {
+	keys, err := LoadSigningKeys(config.SigningKeysDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		log.Printf("no signing keys found in %q, generating a temporary key", config.SigningKeysDir)
+		key, err := GenerateSigningKey("dev-" + time.Now().Format("20060102150405"))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return NewSigner(keys, config.ActiveKeyID)
+}
+
+// GenerateToken issues a short-lived access token. Every token carries a unique
+// ID (jti) so it can be revoked before it expires.
+func (s *Signer) GenerateToken(userID, role string) (string, error) {
+	now := time.Now()
+	claims := CustomClaims{
+		UserID: userID,
+		Role:   role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    config.Issuer,
+			ExpiresAt: jwt.NewNumericDate(now.Add(config.AccessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(s.active.Method(), claims)
+	token.Header["kid"] = s.active.KID
+	return token.SignedString(s.active.Private)
+}
+
+// ValidateToken lets the issuing service verify its own tokens without a JWKS round trip.
+func (s *Signer) ValidateToken(tokenStr string) (*CustomClaims, error) {
+	return parseToken(tokenStr, func(kid string) crypto.PublicKey {
+		key, ok := s.keys[kid]
+		if !ok {
+			return nil
+		}
+		return key.Private.Public()
+	})
+}
+
+// JWKS returns the public half of every key, for /.well-known/jwks.json.
+func (s *Signer) JWKS() (*JWKSet, error) {
+	set := &JWKSet{Keys: make([]JWK, 0, len(s.order))}
+	for _, kid := range s.order {
+		jwk, err := NewJWK(kid, s.keys[kid].Private.Public())
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set, nil
+}
@@ -0,0 +1,121 @@
+package token
+
+import (
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// minUnknownKIDRefresh limits how often a token with an unknown kid can trigger a JWKS fetch.
+const minUnknownKIDRefresh = time.Minute
+
+// JWKSVerifier validates tokens with public keys fetched from the issuer's JWKS endpoint.
+// Keys are refreshed periodically and whenever a token references a kid that is not known yet,
+// which is what happens right after the issuer rotates keys.
+type JWKSVerifier struct {
+	url    string
+	client *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	lastRefresh time.Time
+}
+
+func NewJWKSVerifier(url string) *JWKSVerifier {
+	return &JWKSVerifier{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+		keys:   map[string]crypto.PublicKey{},
+	}
+}
+
+// Start refreshes the key set every interval until the process exits.
+func (v *JWKSVerifier) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := v.Refresh(); err != nil {
+				log.Printf("refreshing JWKS from %s: %v", v.url, err)
+			}
+		}
+	}()
+}
+
+func (v *JWKSVerifier) Refresh() error {
+	v.mu.Lock()
+	v.lastRefresh = time.Now()
+	v.mu.Unlock()
+
+	return v.fetch()
+}
+
+func (v *JWKSVerifier) fetch() error {
+	resp, err := v.client.Get(v.url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var set JWKSet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		public, err := jwk.PublicKey()
+		if err != nil {
+			log.Printf("skipping JWK %s: %v", jwk.Kid, err)
+			continue
+		}
+		keys[jwk.Kid] = public
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.mu.Unlock()
+
+	return nil
+}
+
+func (v *JWKSVerifier) ValidateToken(tokenStr string) (*CustomClaims, error) {
+	return parseToken(tokenStr, v.lookup)
+}
+
+func (v *JWKSVerifier) lookup(kid string) crypto.PublicKey {
+	v.mu.RLock()
+	public, ok := v.keys[kid]
+	v.mu.RUnlock()
+	if ok || !v.claimRefresh() {
+		return public
+	}
+
+	if err := v.fetch(); err != nil {
+		log.Printf("refreshing JWKS from %s: %v", v.url, err)
+		return nil
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.keys[kid]
+}
+
+// claimRefresh reports whether an unknown kid may trigger a fetch now, and if so records it, so that
+// a burst of tokens with made-up kids fetches the key set once.
+func (v *JWKSVerifier) claimRefresh() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if time.Since(v.lastRefresh) <= minUnknownKIDRefresh {
+		return false
+	}
+	v.lastRefresh = time.Now()
+	return true
+}
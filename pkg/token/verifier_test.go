@@ -0,0 +1,128 @@
+package token
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// jwksServer publishes the keys of signer and counts how often they are fetched.
+type jwksServer struct {
+	mu      sync.Mutex
+	signer  *Signer
+	fetches atomic.Int32
+}
+
+func (s *jwksServer) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	s.fetches.Add(1)
+	s.mu.Lock()
+	set, err := s.signer.JWKS()
+	s.mu.Unlock()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(set)
+}
+
+func (s *jwksServer) publish(signer *Signer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.signer = signer
+}
+
+func newTestSigner(t *testing.T, kids ...string) *Signer {
+	t.Helper()
+	var keys []*SigningKey
+	for _, kid := range kids {
+		key, err := GenerateSigningKey(kid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	signer, err := NewSigner(keys, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func signToken(t *testing.T, signer *Signer) string {
+	t.Helper()
+	tokenStr, err := signer.GenerateToken("user-1", "CUSTOMER")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokenStr
+}
+
+func TestJWKSVerifierThrottlesUnknownKIDRefresh(t *testing.T) {
+	issuer := newTestSigner(t, "2026-01")
+	jwks := &jwksServer{signer: issuer}
+	server := httptest.NewServer(jwks)
+	defer server.Close()
+	verifier := NewJWKSVerifier(server.URL)
+
+	rotated := newTestSigner(t, "2026-02")
+	forged := newTestSigner(t, "made-up")
+	steps := []struct {
+		name        string
+		stale       bool // the last refresh is older than minUnknownKIDRefresh, instead of just now
+		token       string
+		parallel    int
+		wantValid   bool
+		wantFetches int32
+	}{
+		{"first token loads the keys", false, signToken(t, issuer), 1, true, 1},
+		{"known kid", true, signToken(t, issuer), 1, true, 1},
+		{"unknown kid right after a refresh", false, signToken(t, forged), 1, false, 1},
+		{"burst of unknown kids", true, signToken(t, forged), 20, false, 2},
+		{"rotated key inside the window", false, signToken(t, rotated), 1, false, 2},
+	}
+	for i, step := range steps {
+		if i == len(steps)-1 {
+			jwks.publish(rotated)
+		}
+		if i > 0 {
+			verifier.mu.Lock()
+			verifier.lastRefresh = time.Now()
+			if step.stale {
+				verifier.lastRefresh = verifier.lastRefresh.Add(-2 * minUnknownKIDRefresh)
+			}
+			verifier.mu.Unlock()
+		}
+
+		var wg sync.WaitGroup
+		var valid atomic.Int32
+		for range step.parallel {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := verifier.ValidateToken(step.token); err == nil {
+					valid.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if want := map[bool]int32{true: int32(step.parallel)}[step.wantValid]; valid.Load() != want {
+			t.Errorf("%s: %d of %d tokens valid, want %d", step.name, valid.Load(), step.parallel, want)
+		}
+		if got := jwks.fetches.Load(); got != step.wantFetches {
+			t.Errorf("%s: %d fetches so far, want %d", step.name, got, step.wantFetches)
+		}
+	}
+
+	// Once the window has passed, the rotated key is picked up by the first token that uses it.
+	verifier.mu.Lock()
+	verifier.lastRefresh = time.Now().Add(-2 * minUnknownKIDRefresh)
+	verifier.mu.Unlock()
+	if _, err := verifier.ValidateToken(signToken(t, rotated)); err != nil {
+		t.Errorf("token signed with the rotated key = %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+// Package xlsx writes a single-sheet Excel workbook row by row. Rows go straight into the zip stream
+// and strings are stored inline rather than in a shared string table, so a sheet of any length is
+// written without being held in memory.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Options describe the sheet. A sheet name is cut to the 31 characters Excel allows.
+type Options struct {
+	SheetName   string
+	RightToLeft bool // lay the sheet out right to left, as Persian readers expect
+}
+
+// Writer writes rows to a workbook. Close must be called to finish the file.
+type Writer struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+	rows  int
+	err   error
+}
+
+// maxCellText is the longest text a cell may hold; longer strings are cut.
+const maxCellText = 32767
+
+// NewWriter writes the parts of the workbook that come before its rows to w.
+func NewWriter(w io.Writer, options Options) (*Writer, error) {
+	zw := zip.NewWriter(w)
+	name := options.SheetName
+	if name == "" {
+		name = "Sheet1"
+	}
+	if utf8.RuneCountInString(name) > 31 {
+		name = string([]rune(name)[:31])
+	}
+
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", contentTypes},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", fmt.Sprintf(workbook, escape(name))},
+		{"xl/_rels/workbook.xml.rels", workbookRels},
+		{"xl/styles.xml", styles},
+	}
+	for _, part := range parts {
+		if err := writePart(zw, part.name, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := zw.CreateHeader(&zip.FileHeader{Name: "xl/worksheets/sheet1.xml", Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+	writer := &Writer{zip: zw, sheet: bufio.NewWriter(sheet)}
+	rtl := ""
+	if options.RightToLeft {
+		rtl = ` rightToLeft="1"`
+	}
+	writer.write(xml.Header)
+	writer.write(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	writer.write(`<sheetViews><sheetView workbookViewId="0"` + rtl + `/></sheetViews><sheetData>`)
+	return writer, writer.err
+}
+
+func writePart(zw *zip.Writer, name, body string) error {
+	part, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(part, xml.Header+body)
+	return err
+}
+
+// WriteHeader writes a row of column names in bold.
+func (w *Writer) WriteHeader(names []string) error {
+	values := make([]any, len(names))
+	for i, name := range names {
+		values[i] = name
+	}
+	return w.writeRow(values, ` s="1"`)
+}
+
+// WriteRow writes the next row. Numbers and booleans become typed cells, nil leaves a cell empty and
+// anything else is written as text.
+func (w *Writer) WriteRow(values []any) error {
+	return w.writeRow(values, "")
+}
+
+func (w *Writer) writeRow(values []any, style string) error {
+	w.rows++
+	w.write(`<row r="` + strconv.Itoa(w.rows) + `">`)
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		ref := ` r="` + column(i) + strconv.Itoa(w.rows) + `"` + style
+		switch v := value.(type) {
+		case int:
+			w.write(`<c` + ref + `><v>` + strconv.Itoa(v) + `</v></c>`)
+		case int64:
+			w.write(`<c` + ref + `><v>` + strconv.FormatInt(v, 10) + `</v></c>`)
+		case float64:
+			w.write(`<c` + ref + `><v>` + strconv.FormatFloat(v, 'f', -1, 64) + `</v></c>`)
+		case bool:
+			b := "0"
+			if v {
+				b = "1"
+			}
+			w.write(`<c` + ref + ` t="b"><v>` + b + `</v></c>`)
+		default:
+			text := fmt.Sprint(v)
+			if len(text) > maxCellText {
+				text = strings.ToValidUTF8(text[:maxCellText], "")
+			}
+			w.write(`<c` + ref + ` t="inlineStr"><is><t xml:space="preserve">` + escape(text) + `</t></is></c>`)
+		}
+	}
+	w.write(`</row>`)
+	return w.err
+}
+
+// Close ends the sheet and writes the zip directory. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	w.write(`</sheetData></worksheet>`)
+	if w.err != nil {
+		return w.err
+	}
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.zip.Close()
+}
+
+// write keeps the first error, so a row is checked once instead of after every cell.
+func (w *Writer) write(s string) {
+	if w.err == nil {
+		_, w.err = w.sheet.WriteString(s)
+	}
+}
+
+// column returns the letters of the zero-based column i: A, B, ..., Z, AA, AB, ...
+func column(i int) string {
+	var letters []byte
+	for i++; i > 0; i = (i - 1) / 26 {
+		letters = append([]byte{byte('A' + (i-1)%26)}, letters...)
+	}
+	return string(letters)
+}
+
+// escape makes s safe inside an XML element or attribute, dropping the control characters XML
+// cannot hold at all.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' {
+			continue
+		}
+		switch r {
+		case '&':
+			b.WriteString("&amp;")
+		case '<':
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		case '"':
+			b.WriteString("&quot;")
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+const contentTypes = `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const rootRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const workbook = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+	`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>` +
+	`</workbook>`
+
+const workbookRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// styles holds two cell formats: 0 is the default and 1 is bold, for the header row.
+const styles = `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+	`</styleSheet>`
@@ -0,0 +1,119 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestColumn(t *testing.T) {
+	tests := map[int]string{0: "A", 1: "B", 25: "Z", 26: "AA", 27: "AB", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"}
+	for i, want := range tests {
+		if got := column(i); got != want {
+			t.Errorf("column(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := map[string]string{
+		"plain":              "plain",
+		`a & b <c> "d"`:      "a &amp; b &lt;c&gt; &quot;d&quot;",
+		"tab\tand\nline\r":   "tab\tand\nline\r",
+		"bell\x07 and \x00x": "bell and x",
+		"سفارش":              "سفارش",
+	}
+	for in, want := range tests {
+		if got := escape(in); got != want {
+			t.Errorf("escape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Options{SheetName: "orders & more", RightToLeft: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteHeader([]string{"کد", "count", "price", "active", "note"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow([]any{"SL-38", 3, 125000.5, true, nil}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow([]any{"<b>", int64(-1), 0.0, false, strings.Repeat("x", maxCellText+10)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("not a zip file: %v", err)
+	}
+	parts := map[string]string{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := wellFormed(body); err != nil {
+			t.Errorf("%s is not well-formed XML: %v", f.Name, err)
+		}
+		parts[f.Name] = string(body)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing part %s", name)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="orders &amp; more"`) {
+		t.Errorf("workbook does not name the sheet: %s", parts["xl/workbook.xml"])
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`rightToLeft="1"`,
+		`<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">کد</t></is></c>`,
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">SL-38</t></is></c>`,
+		`<c r="B2"><v>3</v></c>`,
+		`<c r="C2"><v>125000.5</v></c>`,
+		`<c r="D2" t="b"><v>1</v></c>`,
+		`<c r="A3" t="inlineStr"><is><t xml:space="preserve">&lt;b&gt;</t></is></c>`,
+		`<c r="B3"><v>-1</v></c>`,
+		`<c r="D3" t="b"><v>0</v></c>`,
+		`<t xml:space="preserve">` + strings.Repeat("x", maxCellText) + `</t>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet does not contain %s", want)
+		}
+	}
+	if strings.Contains(sheet, `r="E2"`) {
+		t.Error("nil value was written as a cell")
+	}
+	if strings.Contains(sheet, strings.Repeat("x", maxCellText+1)) {
+		t.Error("text longer than a cell holds was not cut")
+	}
+}
+
+func wellFormed(body []byte) error {
+	d := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		if _, err := d.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
@@ -3,8 +3,11 @@ package main
 import (
 	_ "github.com/lib/pq"
 	"log"
+	"miniature/pkg/token"
+	"miniature/pkg/token/config"
 	"miniature/product/internal/application"
 	"miniature/product/internal/infra/postgres"
+	"miniature/product/internal/infra/render"
 	"miniature/product/internal/interfaces"
 )
 
@@ -15,9 +18,15 @@ func main() {
 
 	repo := postgres.NewRepository(db)
 	shopRepo := postgres.NewShopRepository(db)
-	usecase := application.NewProductService(repo, shopRepo)
+	usecase := application.NewProductService(repo, shopRepo, render.NewRenderer())
 	productHandler := interfaces.NewHandler(usecase)
-	route := interfaces.NewRouter(productHandler)
+	verifier := token.NewJWKSVerifier(config.JWKSURL)
+	if err := verifier.Refresh(); err != nil {
+		log.Printf("cannot load JWKS from %s, will retry: %v", config.JWKSURL, err)
+	}
+	verifier.Start(config.JWKSRefreshInterval)
+	auth := token.NewAuthenticator(verifier, token.NewPostgresRevocationStore(db))
+	route := interfaces.NewRouter(productHandler, auth)
 
 	addr := "localhost:8082"
 	route.Run(addr)
@@ -0,0 +1,56 @@
+package application
+
+import (
+	"miniature/pkg/authz"
+	"miniature/product/internal/domain"
+)
+
+// GetProductByCode resolves a code typed into a chat or read by a scanner. Only active products
+// are returned, so a hidden product can't be found by guessing codes.
+func (s *productService) GetProductByCode(shopIDStr, code string) (*domain.Product, error) {
+	code, err := domain.NormalizeCode(code)
+	if err != nil {
+		return nil, domain.ErrProductNotFound
+	}
+	product, err := s.repo.FindByCode(shopIDStr, code)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil || !product.IsActive {
+		return nil, domain.ErrProductNotFound
+	}
+	return product, nil
+}
+
+func (s *productService) GetCodeSettings(shopIDStr, requestingUserIDStr string) (*domain.CodeSettings, error) {
+	if err := s.requireAllowed(requestingUserIDStr, shopIDStr, authz.PermProductUpdate); err != nil {
+		return nil, err
+	}
+	return s.repo.FindCodeSettings(shopIDStr)
+}
+
+// UpdateCodeSettings only affects codes generated from now on; existing codes are kept because
+// they are already printed and posted.
+func (s *productService) UpdateCodeSettings(shopIDStr, requestingUserIDStr string, settings *domain.CodeSettings) (*domain.CodeSettings, error) {
+	if err := s.requireAllowed(requestingUserIDStr, shopIDStr, authz.PermProductUpdate); err != nil {
+		return nil, err
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveCodeSettings(shopIDStr, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (s *productService) requireAllowed(userIDStr, shopIDStr string, perm authz.Permission) error {
+	allowed, err := s.isAllowed(userIDStr, shopIDStr, perm)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return domain.ErrNotAuthorized
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package application
+
+import (
+	"miniature/pkg/authz"
+	"miniature/pkg/qrpayload"
+	"miniature/product/internal/domain"
+	"sort"
+)
+
+func (s *productService) ProductQRCode(productIDStr string, size int) ([]byte, error) {
+	product, err := s.findProduct(productIDStr)
+	if err != nil {
+		return nil, err
+	}
+	return s.renderer.QRCode(domain.NewLabel(product).Payload, size)
+}
+
+func (s *productService) ProductBarcode(productIDStr, symbologyStr string, width, height int) ([]byte, error) {
+	symbology, err := domain.ParseSymbology(symbologyStr)
+	if err != nil {
+		return nil, err
+	}
+	product, err := s.findProduct(productIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if product.Code == "" {
+		return nil, domain.ErrProductHasNoCode
+	}
+	return s.renderer.Barcode(product.Code, symbology, width, height)
+}
+
+// ShopLabelSheet renders page (1-based) of the label sheets for every product of the shop and
+// reports how many pages there are in total.
+func (s *productService) ShopLabelSheet(shopIDStr, requestingUserIDStr string, page int) ([]byte, int, error) {
+	if err := s.requireAllowed(requestingUserIDStr, shopIDStr, authz.PermProductUpdate); err != nil {
+		return nil, 0, err
+	}
+
+	products, err := s.repo.FindByShopID(shopIDStr)
+	if err != nil {
+		return nil, 0, err
+	}
+	// A stable order keeps a product on the same page between two prints
+	sort.Slice(products, func(i, j int) bool {
+		if products[i].Name != products[j].Name {
+			return products[i].Name < products[j].Name
+		}
+		return products[i].ID.String() < products[j].ID.String()
+	})
+
+	perPage := s.renderer.LabelsPerPage()
+	totalPages := (len(products) + perPage - 1) / perPage
+	if totalPages == 0 {
+		totalPages = 1
+	}
+	if page < 1 || page > totalPages {
+		return nil, totalPages, domain.ErrPageNotFound
+	}
+
+	start := (page - 1) * perPage
+	end := min(start+perPage, len(products))
+	labels := make([]domain.Label, 0, end-start)
+	for _, product := range products[start:end] {
+		labels = append(labels, domain.NewLabel(product))
+	}
+
+	sheet, err := s.renderer.LabelSheet(labels)
+	if err != nil {
+		return nil, 0, err
+	}
+	return sheet, totalPages, nil
+}
+
+// LookupPayload resolves the text of a scanned product QR code. The product id in the payload is
+// authoritative, so a label printed before the code was changed still resolves.
+func (s *productService) LookupPayload(payload string) (*domain.Product, error) {
+	parsed, err := qrpayload.Parse(payload)
+	if err != nil {
+		return nil, err
+	}
+	return s.findProduct(parsed.ProductID.String())
+}
+
+func (s *productService) findProduct(productIDStr string) (*domain.Product, error) {
+	product, err := s.repo.FindByID(productIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, domain.ErrProductNotFound
+	}
+	return product, nil
+}
@@ -3,35 +3,38 @@ package application
 import (
 	"database/sql"
 	"errors"
+	"miniature/pkg/authz"
 	"miniature/product/internal/domain"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type productService struct {
-	repo                 domain.Repository
-	shopOwnershipChecker domain.ShopOwnershipCheckerRepository // Added
+	repo        domain.Repository
+	memberships domain.ShopMembershipRepository
+	renderer    domain.LabelRenderer
 }
 
-func NewProductService(repo domain.Repository, shopChecker domain.ShopOwnershipCheckerRepository) Usecase { // Updated
-	return &productService{repo: repo, shopOwnershipChecker: shopChecker} // Updated
+func NewProductService(repo domain.Repository, memberships domain.ShopMembershipRepository, renderer domain.LabelRenderer) Usecase {
+	return &productService{repo: repo, memberships: memberships, renderer: renderer}
 }
 
-func (s *productService) CreateProduct(shopIDStr, name, description string, price float64, sku string, stockQuantity int, creatingUserIDStr string) (*domain.Product, error) {
+func (s *productService) CreateProduct(shopIDStr, name, description string, price float64, sku, code, category string, stockQuantity int, creatingUserIDStr string) (*domain.Product, error) {
 	shopID, err := uuid.Parse(shopIDStr)
 	if err != nil {
 		return nil, errors.New("invalid shop_id format")
 	}
 
-	// Authorization: Verify creatingUserIDStr owns shopID.
-	isOwner, err := s.shopOwnershipChecker.IsShopOwner(creatingUserIDStr, shopIDStr)
+	// Authorization: Verify creatingUserIDStr is on the staff of shopID with a role that may create products.
+	allowed, err := s.isAllowed(creatingUserIDStr, shopIDStr, authz.PermProductCreate)
 	if err != nil {
 		// Log the error for server-side insight
-		// log.Printf("Error checking shop ownership for user %s, shop %s: %v", creatingUserIDStr, shopIDStr, err)
-		return nil, errors.New("could not verify shop ownership") // Generic error to client
+		// log.Printf("Error checking shop membership for user %s, shop %s: %v", creatingUserIDStr, shopIDStr, err)
+		return nil, errors.New("could not verify shop membership") // Generic error to client
 	}
-	if !isOwner {
+	if !allowed {
 		return nil, errors.New("user not authorized to add products to this shop")
 	}
 
@@ -41,6 +44,12 @@ func (s *productService) CreateProduct(shopIDStr, name, description string, pric
 	if stockQuantity < 0 {
 		return nil, errors.New("stock quantity cannot be negative")
 	}
+	// An empty code is generated by the repository from the shop's code settings
+	if code != "" {
+		if code, err = domain.NormalizeCode(code); err != nil {
+			return nil, err
+		}
+	}
 
 	product := &domain.Product{
 		ID:            uuid.New(),
@@ -49,6 +58,8 @@ func (s *productService) CreateProduct(shopIDStr, name, description string, pric
 		Description:   description,
 		Price:         price,
 		SKU:           sku,
+		Code:          code,
+		Category:      strings.TrimSpace(category),
 		StockQuantity: stockQuantity,
 		IsActive:      true, // Default to active
 		CreatedAt:     time.Now(),
@@ -86,6 +97,8 @@ func (s *productService) UpdateProduct(
 	description *string,
 	price *float64,
 	sku *string,
+	code *string,
+	category *string,
 	stockQuantity *int,
 	isActive *bool,
 	requestingUserIDStr string,
@@ -98,12 +111,12 @@ func (s *productService) UpdateProduct(
 		return nil, errors.New("product not found")
 	}
 
-	// Authorization: Verify requestingUserIDStr owns product.ShopID.
-	isOwner, err := s.shopOwnershipChecker.IsShopOwner(requestingUserIDStr, product.ShopID.String())
+	// Authorization: Verify requestingUserIDStr may update products of product.ShopID.
+	allowed, err := s.isAllowed(requestingUserIDStr, product.ShopID.String(), authz.PermProductUpdate)
 	if err != nil {
-		return nil, errors.New("could not verify shop ownership for product update")
+		return nil, errors.New("could not verify shop membership for product update")
 	}
-	if !isOwner {
+	if !allowed {
 		return nil, errors.New("user not authorized to update this product")
 	}
 
@@ -122,6 +135,16 @@ func (s *productService) UpdateProduct(
 	if sku != nil {
 		product.SKU = *sku
 	}
+	if code != nil {
+		normalized, err := domain.NormalizeCode(*code)
+		if err != nil {
+			return nil, err
+		}
+		product.Code = normalized
+	}
+	if category != nil {
+		product.Category = strings.TrimSpace(*category)
+	}
 	if stockQuantity != nil {
 		if *stockQuantity < 0 {
 			return nil, errors.New("stock quantity cannot be negative")
@@ -133,6 +156,9 @@ func (s *productService) UpdateProduct(
 	}
 
 	err = s.repo.Update(product)
+	if errors.Is(err, domain.ErrCodeTaken) {
+		return nil, err
+	}
 	if err != nil {
 		// Handle specific errors like SKU conflict if necessary
 		// if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
@@ -142,6 +168,11 @@ func (s *productService) UpdateProduct(
 		//}
 		return nil, errors.New("database error while updating product: " + err.Error())
 	}
+	if stockQuantity != nil {
+		if err := s.repo.SetStock(product); err != nil {
+			return nil, errors.New("database error while updating product stock: " + err.Error())
+		}
+	}
 	return product, nil
 }
 
@@ -154,16 +185,26 @@ func (s *productService) DeleteProduct(productIDStr string, requestingUserIDStr
 		return sql.ErrNoRows // Propagate not found
 	}
 
-	// Authorization: Verify requestingUserIDStr owns product.ShopID.
-	isOwner, err := s.shopOwnershipChecker.IsShopOwner(requestingUserIDStr, product.ShopID.String())
+	// Authorization: Verify requestingUserIDStr may delete products of product.ShopID.
+	allowed, err := s.isAllowed(requestingUserIDStr, product.ShopID.String(), authz.PermProductDelete)
 	if err != nil {
-		return errors.New("could not verify shop ownership for product deletion")
+		return errors.New("could not verify shop membership for product deletion")
 	}
-	if !isOwner {
+	if !allowed {
 		return errors.New("user not authorized to delete this product")
 	}
 	return s.repo.Delete(productIDStr)
 }
 
+// isAllowed checks perm against the role the user holds within the shop, so any staff member
+// with a suitable role is accepted, not only the owner.
+func (s *productService) isAllowed(userIDStr, shopIDStr string, perm authz.Permission) (bool, error) {
+	role, err := s.memberships.MemberRole(userIDStr, shopIDStr)
+	if err != nil {
+		return false, err
+	}
+	return role != "" && authz.Can(role, perm), nil
+}
+
 // Add placeholders for other service methods
 // ...
@@ -3,9 +3,18 @@ package application
 import "miniature/product/internal/domain"
 
 type Usecase interface {
-	CreateProduct(shopIDStr, name, description string, price float64, sku string, stockQuantity int, creatingUserIDStr string) (*domain.Product, error)
+	CreateProduct(shopIDStr, name, description string, price float64, sku, code, category string, stockQuantity int, creatingUserIDStr string) (*domain.Product, error)
 	GetProductByID(id string) (*domain.Product, error)
 	GetProductsByShopID(shopIDStr string /*, requestingUserIDStr string - for future auth */) ([]*domain.Product, error)
-	UpdateProduct(productIDStr string, name *string, description *string, price *float64, sku *string, stockQuantity *int, isActive *bool, requestingUserIDStr string) (*domain.Product, error)
+	UpdateProduct(productIDStr string, name *string, description *string, price *float64, sku *string, code *string, category *string, stockQuantity *int, isActive *bool, requestingUserIDStr string) (*domain.Product, error)
 	DeleteProduct(productIDStr string, requestingUserIDStr string) error
+	GetProductByCode(shopIDStr, code string) (*domain.Product, error)
+
+	GetCodeSettings(shopIDStr, requestingUserIDStr string) (*domain.CodeSettings, error)
+	UpdateCodeSettings(shopIDStr, requestingUserIDStr string, settings *domain.CodeSettings) (*domain.CodeSettings, error)
+
+	ProductQRCode(productIDStr string, size int) ([]byte, error)
+	ProductBarcode(productIDStr, symbology string, width, height int) ([]byte, error)
+	ShopLabelSheet(shopIDStr, requestingUserIDStr string, page int) ([]byte, int, error)
+	LookupPayload(payload string) (*domain.Product, error)
 }
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	PatternPrefixToken = "{PREFIX}"
+	PatternSeqToken    = "{SEQ}"
+
+	DefaultCodePattern = PatternPrefixToken + PatternSeqToken
+	DefaultCodePrefix  = "P"
+	DefaultCodeDigits  = 2
+
+	minCodeLength   = 2
+	maxCodeLength   = 32
+	maxCodeDigits   = 8
+	maxPrefixLength = 16
+)
+
+// CodeSettings configure how a shop's product codes are generated: Pattern is expanded with the
+// prefix of the product's category and the next number in that prefix's sequence, e.g. "SL38".
+type CodeSettings struct {
+	Pattern       string            `json:"pattern"`
+	DefaultPrefix string            `json:"default_prefix"`
+	MinDigits     int               `json:"min_digits"`
+	Prefixes      map[string]string `json:"prefixes"` // category -> prefix
+}
+
+func DefaultCodeSettings() *CodeSettings {
+	return &CodeSettings{
+		Pattern:       DefaultCodePattern,
+		DefaultPrefix: DefaultCodePrefix,
+		MinDigits:     DefaultCodeDigits,
+		Prefixes:      map[string]string{},
+	}
+}
+
+// Validate normalizes the settings in place. Only the sequence keeps generated codes apart, so the
+// pattern must contain {SEQ} exactly once. Every code the settings can generate, up to a sequence of
+// maxCodeDigits digits, must also pass NormalizeCode.
+func (s *CodeSettings) Validate() error {
+	s.Pattern = strings.ToUpper(strings.TrimSpace(s.Pattern))
+	if strings.Count(s.Pattern, PatternSeqToken) != 1 || strings.Count(s.Pattern, PatternPrefixToken) > 1 {
+		return ErrInvalidCodePattern
+	}
+	literal := strings.NewReplacer(PatternPrefixToken, "", PatternSeqToken, "").Replace(s.Pattern)
+	if !isCodeText(literal) {
+		return ErrInvalidCodePattern
+	}
+	if s.MinDigits < 1 || s.MinDigits > maxCodeDigits {
+		return ErrInvalidCodePattern
+	}
+
+	s.DefaultPrefix = strings.ToUpper(strings.TrimSpace(s.DefaultPrefix))
+	if len(s.DefaultPrefix) > maxPrefixLength || !isCodeText(s.DefaultPrefix) {
+		return ErrInvalidCodePrefix
+	}
+	shortest, longest := len(s.DefaultPrefix), len(s.DefaultPrefix)
+	prefixes := make(map[string]string, len(s.Prefixes))
+	for category, prefix := range s.Prefixes {
+		category = strings.TrimSpace(category)
+		prefix = strings.ToUpper(strings.TrimSpace(prefix))
+		if category == "" || prefix == "" || len(prefix) > maxPrefixLength || !isCodeText(prefix) {
+			return ErrInvalidCodePrefix
+		}
+		prefixes[category] = prefix
+		shortest, longest = min(shortest, len(prefix)), max(longest, len(prefix))
+	}
+	s.Prefixes = prefixes
+
+	if !strings.Contains(s.Pattern, PatternPrefixToken) {
+		shortest, longest = 0, 0
+	}
+	if len(literal)+shortest+s.MinDigits < minCodeLength || len(literal)+longest+maxCodeDigits > maxCodeLength {
+		return ErrInvalidCodePattern
+	}
+	return nil
+}
+
+// PrefixFor returns the prefix of a category, falling back to the shop's default prefix.
+func (s *CodeSettings) PrefixFor(category string) string {
+	if prefix, ok := s.Prefixes[strings.TrimSpace(category)]; ok {
+		return prefix
+	}
+	return s.DefaultPrefix
+}
+
+func (s *CodeSettings) Format(prefix string, seq int64) string {
+	return strings.NewReplacer(
+		PatternPrefixToken, prefix,
+		PatternSeqToken, fmt.Sprintf("%0*d", s.MinDigits, seq),
+	).Replace(s.Pattern)
+}
+
+// NormalizeCode upper-cases a code entered by a seller or typed in by a customer and checks it can
+// be printed on a label: 2-32 letters, digits or dashes.
+func NormalizeCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) < minCodeLength || len(code) > maxCodeLength || !isCodeText(code) {
+		return "", ErrInvalidCode
+	}
+	return code, nil
+}
+
+func isCodeText(s string) bool {
+	for _, r := range s {
+		if !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCodeSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings CodeSettings
+		want     error
+	}{
+		{"defaults", *DefaultCodeSettings(), nil},
+		{"lower-case pattern", CodeSettings{Pattern: " {prefix}-{seq} ", DefaultPrefix: "sl", MinDigits: 3}, nil},
+		{"no prefix", CodeSettings{Pattern: "{SEQ}", MinDigits: 2}, nil},
+		{"no seq", CodeSettings{Pattern: "{PREFIX}", DefaultPrefix: "P", MinDigits: 2}, ErrInvalidCodePattern},
+		{"two seqs", CodeSettings{Pattern: "{SEQ}{SEQ}", MinDigits: 2}, ErrInvalidCodePattern},
+		{"space in pattern", CodeSettings{Pattern: "{PREFIX} {SEQ}", DefaultPrefix: "P", MinDigits: 2}, ErrInvalidCodePattern},
+		{"no digits", CodeSettings{Pattern: "{SEQ}", MinDigits: 0}, ErrInvalidCodePattern},
+		{"too many digits", CodeSettings{Pattern: "{SEQ}", MinDigits: 9}, ErrInvalidCodePattern},
+		{"single digit codes", CodeSettings{Pattern: "{SEQ}", MinDigits: 1}, ErrInvalidCodePattern},
+		{"single digit with empty prefix", CodeSettings{Pattern: "{PREFIX}{SEQ}", MinDigits: 1}, ErrInvalidCodePattern},
+		{"single digit with a prefix", CodeSettings{Pattern: "{PREFIX}{SEQ}", DefaultPrefix: "P", MinDigits: 1}, nil},
+		{"single digit with a literal", CodeSettings{Pattern: "A{SEQ}", MinDigits: 1}, nil},
+		{"longest prefix", CodeSettings{Pattern: "{PREFIX}{SEQ}", DefaultPrefix: strings.Repeat("P", 16), MinDigits: 2}, nil},
+		{"prefix too long", CodeSettings{Pattern: "{SEQ}", DefaultPrefix: strings.Repeat("P", 17), MinDigits: 2}, ErrInvalidCodePrefix},
+		{
+			"category prefix too long",
+			CodeSettings{Pattern: "{PREFIX}{SEQ}", DefaultPrefix: "P", MinDigits: 2, Prefixes: map[string]string{"scarf": strings.Repeat("S", 17)}},
+			ErrInvalidCodePrefix,
+		},
+		{
+			"codes too long",
+			CodeSettings{Pattern: strings.Repeat("X", 9) + "-{PREFIX}{SEQ}", DefaultPrefix: strings.Repeat("P", 16), MinDigits: 2},
+			ErrInvalidCodePattern,
+		},
+		{
+			"category codes too long",
+			CodeSettings{
+				Pattern: "SHOPNAME-{PREFIX}-{SEQ}", DefaultPrefix: "P", MinDigits: 2,
+				Prefixes: map[string]string{"scarf": strings.Repeat("S", 16)},
+			},
+			ErrInvalidCodePattern,
+		},
+		{"empty category prefix", CodeSettings{Pattern: "{PREFIX}{SEQ}", DefaultPrefix: "P", MinDigits: 2, Prefixes: map[string]string{"scarf": ""}}, ErrInvalidCodePrefix},
+	}
+	for _, tt := range tests {
+		err := tt.settings.Validate()
+		if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: Validate = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestCodeSettingsGenerateNormalizedCodes(t *testing.T) {
+	settings := CodeSettings{
+		Pattern: "{PREFIX}-{SEQ}", DefaultPrefix: "p", MinDigits: 2, Prefixes: map[string]string{" scarf ": "sl"},
+	}
+	if err := settings.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		category string
+		seq      int64
+		want     string
+	}{
+		{"scarf", 7, "SL-07"},
+		{"hat", 38, "P-38"},
+		{"hat", 99999999, "P-99999999"},
+	}
+	for _, tt := range tests {
+		code := settings.Format(settings.PrefixFor(tt.category), tt.seq)
+		if code != tt.want {
+			t.Errorf("code of %s %d = %q, want %q", tt.category, tt.seq, code, tt.want)
+		}
+		if _, err := NormalizeCode(code); err != nil {
+			t.Errorf("generated code %q does not normalize: %v", code, err)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "errors"
+
+var (
+	ErrProductNotFound  = errors.New("product not found")
+	ErrNotAuthorized    = errors.New("user not authorized for this shop")
+	ErrProductHasNoCode = errors.New("product has no code to encode")
+	ErrInvalidSymbology = errors.New("unsupported barcode symbology")
+	ErrInvalidEAN13     = errors.New("EAN-13 needs a code of 12 or 13 digits")
+	ErrPageNotFound     = errors.New("page not found")
+)
+
+var (
+	ErrInvalidCode        = errors.New("product code must be 2-32 letters, digits or dashes")
+	ErrCodeTaken          = errors.New("product code is already used in this shop")
+	ErrInvalidCodePattern = errors.New("code pattern must contain {SEQ} once and only letters, digits or dashes, and make codes of 2-32 characters")
+	ErrInvalidCodePrefix  = errors.New("code prefixes must be at most 16 letters, digits or dashes")
+)
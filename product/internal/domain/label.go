@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"miniature/pkg/qrpayload"
+
+	"github.com/google/uuid"
+)
+
+// Symbology is a linear barcode format a product code can be printed in.
+type Symbology string
+
+const (
+	SymbologyCode128 Symbology = "code128"
+	SymbologyEAN13   Symbology = "ean13"
+)
+
+func ParseSymbology(s string) (Symbology, error) {
+	switch Symbology(s) {
+	case SymbologyCode128, SymbologyEAN13:
+		return Symbology(s), nil
+	}
+	return "", ErrInvalidSymbology
+}
+
+// Label is what is printed on a product's shelf sticker. Payload is the text of its QR code and
+// resolves back to the product through the lookup endpoint.
+type Label struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Name      string    `json:"name"`
+	Price     float64   `json:"price"`
+	Code      string    `json:"code"`
+	Payload   string    `json:"payload"`
+}
+
+func NewLabel(product *Product) Label {
+	return Label{
+		ProductID: product.ID,
+		Name:      product.Name,
+		Price:     product.Price,
+		Code:      product.Code,
+		Payload:   qrpayload.Encode(product.ID, product.Code),
+	}
+}
+
+// LabelRenderer draws product codes. Images are PNG, label sheets are A4 SVG pages.
+type LabelRenderer interface {
+	QRCode(payload string, size int) ([]byte, error)
+	Barcode(code string, symbology Symbology, width, height int) ([]byte, error)
+	// LabelsPerPage is how many labels fit on one sheet.
+	LabelsPerPage() int
+	LabelSheet(labels []Label) ([]byte, error)
+}
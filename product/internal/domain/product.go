@@ -12,6 +12,8 @@ type Product struct {
 	Description   string    `json:"description"`
 	Price         float64   `json:"price"` // Consider using a specific decimal type for currency
 	SKU           string    `json:"sku"`
+	Code          string    `json:"code"` // Short per-shop code for captions, labels and the counter, e.g. "SL38"
+	Category      string    `json:"category,omitempty"`
 	StockQuantity int       `json:"stock_quantity"`
 	IsActive      bool      `json:"is_active"`
 	CreatedAt     time.Time `json:"created_at"`
@@ -1,15 +1,30 @@
 package domain
 
+import "miniature/pkg/authz"
+
 type Repository interface {
+	// Create generates the product's code from the shop's CodeSettings when it has none.
 	Create(product *Product) error
 	FindByID(id string) (*Product, error)
 	FindByShopID(shopID string) ([]*Product, error)
+	// FindByCode looks a product up by its normalized code within a shop.
+	FindByCode(shopID, code string) (*Product, error)
+	// Update saves everything but the stock, which checkouts change concurrently.
+	// A replaced code is retired so it is never given to another product.
 	Update(product *Product) error
+	// SetStock overwrites the stock with a count entered by the seller.
+	SetStock(product *Product) error
+	// Delete removes the product and retires its code.
 	Delete(id string) error
+
+	// FindCodeSettings returns the shop's code settings, or the defaults if it never saved any.
+	FindCodeSettings(shopID string) (*CodeSettings, error)
+	SaveCodeSettings(shopID string, settings *CodeSettings) error
 }
 
-// ShopOwnershipCheckerRepository defines an interface for checking shop ownership.
-// This is used by the product service to authorize actions on products based on shop ownership.
-type ShopOwnershipCheckerRepository interface {
-	IsShopOwner(userID, shopID string) (bool, error)
+// ShopMembershipRepository defines an interface for looking up a user's role in a shop.
+// This is used by the product service to authorize actions on products based on shop membership.
+type ShopMembershipRepository interface {
+	// MemberRole returns the role userID holds in shopID, or "" if they are not on its staff.
+	MemberRole(userID, shopID string) (authz.Role, error)
 }
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"database/sql"
+	"miniature/product/internal/domain"
+)
+
+// maxCodeAttempts bounds the search for a free code when generated codes collide with codes
+// sellers picked by hand or retired ones.
+const maxCodeAttempts = 100
+
+func (r *repository) FindCodeSettings(shopID string) (*domain.CodeSettings, error) {
+	return findCodeSettings(r.db, shopID)
+}
+
+func (r *repository) SaveCodeSettings(shopID string, settings *domain.CodeSettings) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO product_code_settings (shop_id, pattern, default_prefix, min_digits, updated_at)
+              VALUES ($1, $2, $3, $4, NOW())
+              ON CONFLICT (shop_id) DO UPDATE
+              SET pattern = EXCLUDED.pattern, default_prefix = EXCLUDED.default_prefix,
+                  min_digits = EXCLUDED.min_digits, updated_at = EXCLUDED.updated_at`
+	if _, err := tx.Exec(query, shopID, settings.Pattern, settings.DefaultPrefix, settings.MinDigits); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM product_code_prefixes WHERE shop_id = $1`, shopID); err != nil {
+		return err
+	}
+	for category, prefix := range settings.Prefixes {
+		_, err := tx.Exec(
+			`INSERT INTO product_code_prefixes (shop_id, category, prefix) VALUES ($1, $2, $3)`,
+			shopID, category, prefix,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+type queryer interface {
+	QueryRow(query string, args ...any) *sql.Row
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+func findCodeSettings(q queryer, shopID string) (*domain.CodeSettings, error) {
+	settings := domain.DefaultCodeSettings()
+	err := q.QueryRow(
+		`SELECT pattern, default_prefix, min_digits FROM product_code_settings WHERE shop_id = $1`, shopID,
+	).Scan(&settings.Pattern, &settings.DefaultPrefix, &settings.MinDigits)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	rows, err := q.Query(`SELECT category, prefix FROM product_code_prefixes WHERE shop_id = $1`, shopID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var category, prefix string
+		if err := rows.Scan(&category, &prefix); err != nil {
+			return nil, err
+		}
+		settings.Prefixes[category] = prefix
+	}
+	return settings, rows.Err()
+}
+
+// generateCode takes numbers from the sequence of the product's prefix until the formatted code
+// is neither in use nor retired. The sequence row stays locked until tx ends, so concurrent
+// creates in the same shop and prefix get different numbers.
+func generateCode(tx *sql.Tx, product *domain.Product) (string, error) {
+	shopID := product.ShopID.String()
+	settings, err := findCodeSettings(tx, shopID)
+	if err != nil {
+		return "", err
+	}
+	prefix := settings.PrefixFor(product.Category)
+
+	for range maxCodeAttempts {
+		var seq int64
+		err := tx.QueryRow(
+			`INSERT INTO product_code_sequences (shop_id, prefix, last_value) VALUES ($1, $2, 1)
+             ON CONFLICT (shop_id, prefix) DO UPDATE SET last_value = product_code_sequences.last_value + 1
+             RETURNING last_value`,
+			shopID, prefix,
+		).Scan(&seq)
+		if err != nil {
+			return "", err
+		}
+
+		code := settings.Format(prefix, seq)
+		taken, err := codeTaken(tx, shopID, code, product.ID.String())
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return code, nil
+		}
+	}
+	return "", domain.ErrCodeTaken
+}
+
+// ensureCodeFree checks a code picked by the seller. A product may take back one of its own
+// retired codes.
+func ensureCodeFree(tx *sql.Tx, product *domain.Product) error {
+	taken, err := codeTaken(tx, product.ShopID.String(), product.Code, product.ID.String())
+	if err != nil {
+		return err
+	}
+	if taken {
+		return domain.ErrCodeTaken
+	}
+	_, err = tx.Exec(
+		`DELETE FROM retired_product_codes WHERE shop_id = $1 AND code = $2 AND product_id = $3`,
+		product.ShopID, product.Code, product.ID,
+	)
+	return err
+}
+
+func codeTaken(tx *sql.Tx, shopID, code, productID string) (bool, error) {
+	var taken bool
+	query := `SELECT EXISTS (SELECT 1 FROM products WHERE shop_id = $1 AND code = $2 AND id <> $3)
+                  OR EXISTS (SELECT 1 FROM retired_product_codes WHERE shop_id = $1 AND code = $2 AND product_id <> $3)`
+	err := tx.QueryRow(query, shopID, code, productID).Scan(&taken)
+	return taken, err
+}
+
+func retireCode(tx *sql.Tx, shopID, code, productID string) error {
+	_, err := tx.Exec(
+		`INSERT INTO retired_product_codes (shop_id, code, product_id, retired_at) VALUES ($1, $2, $3, NOW())
+         ON CONFLICT (shop_id, code) DO NOTHING`,
+		shopID, code, productID,
+	)
+	return err
+}
@@ -2,7 +2,10 @@ package postgres
 
 import (
 	"database/sql"
+	"errors"
 	"miniature/product/internal/domain"
+
+	"github.com/lib/pq"
 )
 
 type repository struct {
@@ -13,26 +16,56 @@ func NewRepository(db *sql.DB) *repository {
 	return &repository{db: db}
 }
 
+const productColumns = `id, shop_id, name, description, price, sku, code, COALESCE(category, ''),
+                        stock_quantity, is_active, created_at`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanProduct(row scanner) (*domain.Product, error) {
+	product := &domain.Product{}
+	err := row.Scan(
+		&product.ID, &product.ShopID, &product.Name, &product.Description, &product.Price,
+		&product.SKU, &product.Code, &product.Category, &product.StockQuantity, &product.IsActive, &product.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
 func (r *repository) Create(product *domain.Product) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if product.Code == "" {
+		if product.Code, err = generateCode(tx, product); err != nil {
+			return err
+		}
+	} else if err := ensureCodeFree(tx, product); err != nil {
+		return err
+	}
+
 	query := `INSERT INTO products
-              (id, shop_id, name, description, price, sku, stock_quantity, is_active, created_at)
-              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
-	_, err := r.db.Exec(query,
+              (id, shop_id, name, description, price, sku, code, category, stock_quantity, is_active, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10, $11)`
+	_, err = tx.Exec(query,
 		product.ID, product.ShopID, product.Name, product.Description, product.Price,
-		product.SKU, product.StockQuantity, product.IsActive, product.CreatedAt,
+		product.SKU, product.Code, product.Category, product.StockQuantity, product.IsActive, product.CreatedAt,
 	)
-	return err
+	if err != nil {
+		return codeConflict(err)
+	}
+	return tx.Commit()
 }
 
 func (r *repository) FindByID(id string) (*domain.Product, error) {
-	product := &domain.Product{}
-	query := `SELECT id, shop_id, name, description, price, sku, stock_quantity, is_active, created_at
-              FROM products WHERE id = $1`
-	row := r.db.QueryRow(query, id)
-	err := row.Scan(
-		&product.ID, &product.ShopID, &product.Name, &product.Description, &product.Price,
-		&product.SKU, &product.StockQuantity, &product.IsActive, &product.CreatedAt,
-	)
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = $1`
+	product, err := scanProduct(r.db.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Standard way to indicate not found
@@ -44,8 +77,7 @@ func (r *repository) FindByID(id string) (*domain.Product, error) {
 
 func (r *repository) FindByShopID(shopID string) ([]*domain.Product, error) {
 	var products []*domain.Product
-	query := `SELECT id, shop_id, name, description, price, sku, stock_quantity, is_active, created_at
-              FROM products WHERE shop_id = $1`
+	query := `SELECT ` + productColumns + ` FROM products WHERE shop_id = $1`
 	rows, err := r.db.Query(query, shopID)
 	if err != nil {
 		return nil, err
@@ -53,11 +85,7 @@ func (r *repository) FindByShopID(shopID string) ([]*domain.Product, error) {
 	defer rows.Close()
 
 	for rows.Next() {
-		product := &domain.Product{}
-		err := rows.Scan(
-			&product.ID, &product.ShopID, &product.Name, &product.Description, &product.Price,
-			&product.SKU, &product.StockQuantity, &product.IsActive, &product.CreatedAt,
-		)
+		product, err := scanProduct(rows)
 		if err != nil {
 			return nil, err // Or collect errors and continue
 		}
@@ -69,34 +97,89 @@ func (r *repository) FindByShopID(shopID string) ([]*domain.Product, error) {
 	return products, nil
 }
 
+func (r *repository) FindByCode(shopID, code string) (*domain.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE shop_id = $1 AND code = $2`
+	product, err := scanProduct(r.db.QueryRow(query, shopID, code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return product, nil
+}
+
 func (r *repository) Update(product *domain.Product) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var previousCode string
+	err = tx.QueryRow(`SELECT code FROM products WHERE id = $1 AND shop_id = $2 FOR UPDATE`, product.ID, product.ShopID).
+		Scan(&previousCode)
+	if err != nil {
+		return err
+	}
+	if product.Code != previousCode {
+		if err := ensureCodeFree(tx, product); err != nil {
+			return err
+		}
+		if err := retireCode(tx, product.ShopID.String(), previousCode, product.ID.String()); err != nil {
+			return err
+		}
+	}
+
 	query := `UPDATE products SET
                 name = $1,
                 description = $2,
                 price = $3,
                 sku = $4,
-                stock_quantity = $5,
-                is_active = $6
-              WHERE id = $7 AND shop_id = $8` // shop_id in WHERE for safety, though id is PK
-	_, err := r.db.Exec(query,
-		product.Name, product.Description, product.Price, product.SKU,
-		product.StockQuantity, product.IsActive, product.ID, product.ShopID,
+                code = $5,
+                category = NULLIF($6, ''),
+                is_active = $7
+              WHERE id = $8 AND shop_id = $9` // shop_id in WHERE for safety, though id is PK
+	_, err = tx.Exec(query,
+		product.Name, product.Description, product.Price, product.SKU, product.Code, product.Category,
+		product.IsActive, product.ID, product.ShopID,
 	)
+	if err != nil {
+		return codeConflict(err)
+	}
+	return tx.Commit()
+}
+
+func (r *repository) SetStock(product *domain.Product) error {
+	query := `UPDATE products SET stock_quantity = $1 WHERE id = $2 AND shop_id = $3`
+	_, err := r.db.Exec(query, product.StockQuantity, product.ID, product.ShopID)
 	return err
 }
 
 func (r *repository) Delete(id string) error {
-	query := `DELETE FROM products WHERE id = $1`
-	result, err := r.db.Exec(query, id)
+	tx, err := r.db.Begin()
 	if err != nil {
 		return err
 	}
-	rowsAffected, err := result.RowsAffected()
+	defer tx.Rollback()
+
+	var shopID, code string
+	err = tx.QueryRow(`DELETE FROM products WHERE id = $1 RETURNING shop_id, code`, id).Scan(&shopID, &code)
 	if err != nil {
+		return err // sql.ErrNoRows is the standard way to indicate not found or nothing deleted
+	}
+	if err := retireCode(tx, shopID, code, id); err != nil {
 		return err
 	}
-	if rowsAffected == 0 {
-		return sql.ErrNoRows // Standard way to indicate not found or nothing deleted
+	return tx.Commit()
+}
+
+// codeConflict turns a violation of the per-shop code uniqueness into ErrCodeTaken; it only
+// happens when two products claim the same code concurrently.
+func codeConflict(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "uq_products_shop_code" {
+		return domain.ErrCodeTaken
 	}
-	return nil
+	return err
 }
@@ -2,7 +2,7 @@ package postgres
 
 import (
 	"database/sql"
-	"github.com/google/uuid"
+	"miniature/pkg/authz"
 )
 
 type shopRepository struct {
@@ -13,24 +13,23 @@ func NewShopRepository(db *sql.DB) *shopRepository {
 	return &shopRepository{db: db}
 }
 
-// IsShopOwner checks if the given userID is the owner of the shopID.
-// This directly queries the 'shops' table.
-func (r *shopRepository) IsShopOwner(userIDStr, shopIDStr string) (bool, error) {
-	var ownerID uuid.UUID
+// MemberRole returns the role of userID on the staff of shopID (shop_users table).
+// The owner recorded on the shop itself is always treated as OWNER.
+func (r *shopRepository) MemberRole(userIDStr, shopIDStr string) (authz.Role, error) {
+	var role sql.NullString
 
-	// Ensure string IDs are valid UUIDs before querying if necessary, or let DB handle type error
-	// For this implementation, we assume shopIDStr is a valid UUID string.
-	// userIDStr should also be a valid UUID string representing the customer's ID.
-
-	query := `SELECT owner_id FROM shops WHERE id = $1`
-	err := r.db.QueryRow(query, shopIDStr).Scan(&ownerID)
+	query := `SELECT CASE WHEN s.owner_id = $2 THEN 'OWNER' ELSE su.role END
+              FROM shops s
+              LEFT JOIN shop_users su ON su.shop_id = s.id AND su.user_id = $2
+              WHERE s.id = $1`
+	err := r.db.QueryRow(query, shopIDStr, userIDStr).Scan(&role)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			// Shop not found, so user cannot be the owner
-			return false, nil // Or return an error like "shop not found"
+			// Shop not found, so user cannot be a member
+			return "", nil
 		}
-		return false, err
+		return "", err
 	}
 
-	return ownerID.String() == userIDStr, nil
+	return authz.Role(role.String), nil
 }
@@ -0,0 +1,88 @@
+// Package render draws product QR codes, barcodes and printable label sheets.
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"miniature/product/internal/domain"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/ean"
+	"github.com/boombuler/barcode/qr"
+)
+
+// quietZone is the blank margin, in pixels, scanners need around a code.
+const quietZone = 16
+
+type renderer struct{}
+
+func NewRenderer() *renderer {
+	return &renderer{}
+}
+
+func (r *renderer) QRCode(payload string, size int) ([]byte, error) {
+	code, err := qr.Encode(payload, qr.M, qr.Auto)
+	if err != nil {
+		return nil, err
+	}
+	scaled, err := barcode.Scale(code, size, size)
+	if err != nil {
+		return nil, err
+	}
+	return encodePNG(scaled)
+}
+
+func (r *renderer) Barcode(code string, symbology domain.Symbology, width, height int) ([]byte, error) {
+	var bc barcode.Barcode
+	var err error
+	switch symbology {
+	case domain.SymbologyCode128:
+		bc, err = code128.Encode(code)
+	case domain.SymbologyEAN13:
+		if !isDigits(code) || (len(code) != 12 && len(code) != 13) {
+			return nil, domain.ErrInvalidEAN13
+		}
+		bc, err = ean.Encode(code)
+	default:
+		return nil, domain.ErrInvalidSymbology
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Bars can't be narrower than one pixel, so a too small width is raised to fit the code
+	if minWidth := bc.Bounds().Dx(); width < minWidth {
+		width = minWidth
+	}
+	scaled, err := barcode.Scale(bc, width, height)
+	if err != nil {
+		return nil, err
+	}
+	return encodePNG(scaled)
+}
+
+func encodePNG(code image.Image) ([]byte, error) {
+	bounds := code.Bounds()
+	canvas := image.NewGray(image.Rect(0, 0, bounds.Dx()+2*quietZone, bounds.Dy()+2*quietZone))
+	draw.Draw(canvas, canvas.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(canvas, bounds.Add(image.Pt(quietZone, quietZone)), code, bounds.Min, draw.Src)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,125 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image/color"
+	"miniature/product/internal/domain"
+	"strconv"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+)
+
+// A sheet is an A4 page of 3×8 labels of 70×37mm, the common 24-up sticker layout.
+// All measurements below are in millimetres.
+const (
+	pageWidth    = 210.0
+	pageHeight   = 297.0
+	labelColumns = 3
+	labelRows    = 8
+	labelWidth   = 70.0
+	labelHeight  = 37.0
+	labelPadding = 3.0
+	qrSize       = labelHeight - 2*labelPadding
+
+	// maxNameRunes keeps a product name within the text column of a label
+	maxNameRunes = 18
+)
+
+func (r *renderer) LabelsPerPage() int {
+	return labelColumns * labelRows
+}
+
+// LabelSheet draws one page with up to LabelsPerPage labels. QR codes are drawn as vectors so
+// they stay sharp at any printer resolution.
+func (r *renderer) LabelSheet(labels []domain.Label) ([]byte, error) {
+	if len(labels) > r.LabelsPerPage() {
+		return nil, fmt.Errorf("a sheet holds %d labels, got %d", r.LabelsPerPage(), len(labels))
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<?xml version="1.0" encoding="UTF-8"?>`+"\n")
+	fmt.Fprintf(&buf,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%smm" height="%smm" viewBox="0 0 %s %s" font-family="sans-serif">`+"\n",
+		num(pageWidth), num(pageHeight), num(pageWidth), num(pageHeight),
+	)
+
+	marginX := (pageWidth - labelColumns*labelWidth) / 2
+	marginY := (pageHeight - labelRows*labelHeight) / 2
+	for i, label := range labels {
+		x := marginX + float64(i%labelColumns)*labelWidth
+		y := marginY + float64(i/labelColumns)*labelHeight
+		if err := writeLabel(&buf, label, x, y); err != nil {
+			return nil, fmt.Errorf("label for product %s: %w", label.ProductID, err)
+		}
+	}
+
+	buf.WriteString("</svg>\n")
+	return buf.Bytes(), nil
+}
+
+func writeLabel(buf *bytes.Buffer, label domain.Label, x, y float64) error {
+	fmt.Fprintf(buf, `<g transform="translate(%s %s)">`+"\n", num(x), num(y))
+	// Cutting guide
+	fmt.Fprintf(buf, `<rect width="%s" height="%s" fill="none" stroke="#ccc" stroke-width="0.2"/>`+"\n",
+		num(labelWidth), num(labelHeight))
+
+	if err := writeQR(buf, label.Payload, labelPadding, labelPadding, qrSize); err != nil {
+		return err
+	}
+
+	textX := num(2*labelPadding + qrSize)
+	fmt.Fprintf(buf, `<text x="%s" y="10" font-size="3.2">%s</text>`+"\n", textX, html.EscapeString(truncate(label.Name)))
+	fmt.Fprintf(buf, `<text x="%s" y="20" font-size="5" font-weight="bold">%s</text>`+"\n", textX, num(label.Price))
+	fmt.Fprintf(buf, `<text x="%s" y="30" font-size="3.5" font-family="monospace">%s</text>`+"\n", textX, html.EscapeString(label.Code))
+
+	buf.WriteString("</g>\n")
+	return nil
+}
+
+// writeQR draws the dark modules of the payload's QR code in a size×size square, one rect per
+// horizontal run of modules.
+func writeQR(buf *bytes.Buffer, payload string, x, y, size float64) error {
+	code, err := qr.Encode(payload, qr.M, qr.Auto)
+	if err != nil {
+		return err
+	}
+	bounds := code.Bounds()
+	module := size / float64(bounds.Dx())
+
+	fmt.Fprintf(buf, `<g transform="translate(%s %s) scale(%s)" fill="#000">`+"\n", num(x), num(y), num(module))
+	for row := bounds.Min.Y; row < bounds.Max.Y; row++ {
+		for col := bounds.Min.X; col < bounds.Max.X; {
+			if !isDark(code, col, row) {
+				col++
+				continue
+			}
+			start := col
+			for col < bounds.Max.X && isDark(code, col, row) {
+				col++
+			}
+			fmt.Fprintf(buf, `<rect x="%d" y="%d" width="%d" height="1"/>`, start-bounds.Min.X, row-bounds.Min.Y, col-start)
+		}
+	}
+	buf.WriteString("\n</g>\n")
+	return nil
+}
+
+func isDark(code barcode.Barcode, x, y int) bool {
+	gray := color.GrayModel.Convert(code.At(x, y)).(color.Gray)
+	return gray.Y < 128
+}
+
+func truncate(name string) string {
+	runes := []rune(name)
+	if len(runes) <= maxNameRunes {
+		return name
+	}
+	return string(runes[:maxNameRunes-1]) + "…"
+}
+
+func num(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"miniature/pkg/jalali"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// calendarFromQuery reads the ?calendar= a client wants dates in. It writes the error response and
+// returns false if the calendar is unknown.
+func calendarFromQuery(c *gin.Context) (jalali.Calendar, bool) {
+	calendar, err := jalali.ParseCalendar(c.Query("calendar"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return calendar, true
+}
@@ -0,0 +1,81 @@
+package interfaces
+
+import (
+	"errors"
+	"miniature/product/internal/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProductByCode is public so bots and scanners can resolve a code without a user token.
+func (h *Handler) GetProductByCode(c *gin.Context) {
+	calendar, ok := calendarFromQuery(c)
+	if !ok {
+		return
+	}
+
+	product, err := h.usecase.GetProductByCode(c.Param("shop_id"), c.Param("code"))
+	if err != nil {
+		respondLabelError(c, "could not retrieve product", err)
+		return
+	}
+	c.JSON(http.StatusOK, newProductResponse(product, calendar))
+}
+
+func (h *Handler) GetCodeSettings(c *gin.Context) {
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found in context"})
+		return
+	}
+	userIDStr, _ := userIDRaw.(string)
+
+	settings, err := h.usecase.GetCodeSettings(c.Param("shop_id"), userIDStr)
+	if err != nil {
+		respondLabelError(c, "could not retrieve code settings", err)
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *Handler) UpdateCodeSettings(c *gin.Context) {
+	var req CodeSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found in context"})
+		return
+	}
+	userIDStr, _ := userIDRaw.(string)
+
+	settings := &domain.CodeSettings{
+		Pattern:       req.Pattern,
+		DefaultPrefix: req.DefaultPrefix,
+		MinDigits:     req.MinDigits,
+		Prefixes:      req.Prefixes,
+	}
+	settings, err := h.usecase.UpdateCodeSettings(c.Param("shop_id"), userIDStr, settings)
+	if err != nil {
+		respondLabelError(c, "could not update code settings", err)
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// respondCodeError writes the response for product code errors and reports whether err was one.
+func respondCodeError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrInvalidCode):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrCodeTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
@@ -1,8 +1,11 @@
 package interfaces
 
 import (
-	"github.com/google/uuid"
+	"miniature/pkg/jalali"
+	"miniature/product/internal/domain"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type CreateProductRequest struct {
@@ -10,21 +13,54 @@ type CreateProductRequest struct {
 	Description   string  `json:"description"`
 	Price         float64 `json:"price" binding:"required,gte=0"`
 	SKU           string  `json:"sku"`
+	Code          string  `json:"code"` // Generated from the shop's code settings when empty
+	Category      string  `json:"category"`
 	StockQuantity int     `json:"stock_quantity" binding:"gte=0"`
 }
 
-// ProductResponse can be the domain.Product or a specific DTO
-// For now, using domain.Product directly is fine for responses.
+// ProductResponse is a product as the API returns it. CreatedAtJalali is only set for clients that
+// ask for ?calendar=jalali.
 type ProductResponse struct {
-	ID            uuid.UUID `json:"id"`
-	ShopID        uuid.UUID `json:"shop_id"`
-	Name          string    `json:"name"`
-	Description   string    `json:"description"`
-	Price         float64   `json:"price"`
-	SKU           string    `json:"sku"`
-	StockQuantity int       `json:"stock_quantity"`
-	IsActive      bool      `json:"is_active"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID              uuid.UUID `json:"id"`
+	ShopID          uuid.UUID `json:"shop_id"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	Price           float64   `json:"price"`
+	SKU             string    `json:"sku"`
+	Code            string    `json:"code"`
+	Category        string    `json:"category,omitempty"`
+	StockQuantity   int       `json:"stock_quantity"`
+	IsActive        bool      `json:"is_active"`
+	CreatedAt       time.Time `json:"created_at"`
+	CreatedAtJalali string    `json:"created_at_jalali,omitempty"`
+}
+
+func newProductResponse(product *domain.Product, calendar jalali.Calendar) *ProductResponse {
+	response := &ProductResponse{
+		ID:            product.ID,
+		ShopID:        product.ShopID,
+		Name:          product.Name,
+		Description:   product.Description,
+		Price:         product.Price,
+		SKU:           product.SKU,
+		Code:          product.Code,
+		Category:      product.Category,
+		StockQuantity: product.StockQuantity,
+		IsActive:      product.IsActive,
+		CreatedAt:     product.CreatedAt,
+	}
+	if calendar == jalali.Jalali {
+		response.CreatedAtJalali = jalali.Format(product.CreatedAt)
+	}
+	return response
+}
+
+func newProductResponses(products []*domain.Product, calendar jalali.Calendar) []*ProductResponse {
+	responses := make([]*ProductResponse, 0, len(products))
+	for _, product := range products {
+		responses = append(responses, newProductResponse(product, calendar))
+	}
+	return responses
 }
 
 type UpdateProductRequest struct {
@@ -32,6 +68,33 @@ type UpdateProductRequest struct {
 	Description   *string  `json:"description"`
 	Price         *float64 `json:"price" binding:"omitempty,gte=0"` // omitempty allows nil, gte=0 applies if not nil
 	SKU           *string  `json:"sku"`
+	Code          *string  `json:"code"`
+	Category      *string  `json:"category"`
 	StockQuantity *int     `json:"stock_quantity" binding:"omitempty,gte=0"`
 	IsActive      *bool    `json:"is_active"`
 }
+
+type QRCodeQuery struct {
+	Size int `form:"size" binding:"omitempty,min=64,max=1024"`
+}
+
+type BarcodeQuery struct {
+	Format string `form:"format"`
+	Width  int    `form:"width" binding:"omitempty,min=1,max=2000"`
+	Height int    `form:"height" binding:"omitempty,min=20,max=1000"`
+}
+
+type LabelSheetQuery struct {
+	Page int `form:"page" binding:"omitempty,min=1"`
+}
+
+type LookupQuery struct {
+	Payload string `form:"payload" binding:"required"`
+}
+
+type CodeSettingsRequest struct {
+	Pattern       string            `json:"pattern" binding:"required"`
+	DefaultPrefix string            `json:"default_prefix"`
+	MinDigits     int               `json:"min_digits" binding:"required"`
+	Prefixes      map[string]string `json:"prefixes"`
+}
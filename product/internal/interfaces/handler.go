@@ -19,6 +19,10 @@ func NewHandler(u application.Usecase) *Handler {
 func (h *Handler) CreateProduct(c *gin.Context) {
 	shopIDStr := c.Param("shop_id")
 	// Potentially validate shopIDStr format here if not done by a path regex
+	calendar, ok := calendarFromQuery(c)
+	if !ok {
+		return
+	}
 
 	var req CreateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -37,26 +41,17 @@ func (h *Handler) CreateProduct(c *gin.Context) {
 		return
 	}
 
-	roleRaw, exists := c.Get("role")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "role not found in context"})
-		return
-	}
-
-	_, ok = roleRaw.(string)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "role is not of type string"})
-		return
-	}
-
-	product, err := h.usecase.CreateProduct(shopIDStr, req.Name, req.Description, req.Price, req.SKU, req.StockQuantity, userIDStr)
+	product, err := h.usecase.CreateProduct(shopIDStr, req.Name, req.Description, req.Price, req.SKU, req.Code, req.Category, req.StockQuantity, userIDStr)
 	if err != nil {
 		// Check for specific errors from usecase
 		if err.Error() == "user not authorized to add products to this shop" ||
-			err.Error() == "could not verify shop ownership" {
+			err.Error() == "could not verify shop membership" {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
 		}
+		if respondCodeError(c, err) {
+			return
+		}
 		// if strings.Contains(err.Error(), "already exists") { // For SKU conflict
 		// 	c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		// 	return
@@ -65,12 +60,15 @@ func (h *Handler) CreateProduct(c *gin.Context) {
 		return
 	}
 
-	// Convert domain.Product to ProductResponse if they are different, or use domain.Product directly
-	c.JSON(http.StatusCreated, product)
+	c.JSON(http.StatusCreated, newProductResponse(product, calendar))
 }
 
 func (h *Handler) GetProduct(c *gin.Context) {
 	productIDStr := c.Param("product_id")
+	calendar, ok := calendarFromQuery(c)
+	if !ok {
+		return
+	}
 
 	product, err := h.usecase.GetProductByID(productIDStr)
 	if err != nil {
@@ -82,11 +80,15 @@ func (h *Handler) GetProduct(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "product not found"})
 		return
 	}
-	c.JSON(http.StatusOK, product) // Using domain.Product as response for now
+	c.JSON(http.StatusOK, newProductResponse(product, calendar))
 }
 
 func (h *Handler) GetShopProducts(c *gin.Context) {
 	shopIDStr := c.Param("shop_id")
+	calendar, ok := calendarFromQuery(c)
+	if !ok {
+		return
+	}
 
 	// userIDRaw, _ := c.Get("user_id") // For future authorization if needed
 	// userIDStr, _ := userIDRaw.(string)
@@ -97,11 +99,15 @@ func (h *Handler) GetShopProducts(c *gin.Context) {
 		return
 	}
 	// Always return a list, even if empty
-	c.JSON(http.StatusOK, products) // Using slice of domain.Product as response
+	c.JSON(http.StatusOK, newProductResponses(products, calendar))
 }
 
 func (h *Handler) UpdateProduct(c *gin.Context) {
 	productIDStr := c.Param("product_id")
+	calendar, ok := calendarFromQuery(c)
+	if !ok {
+		return
+	}
 
 	var req UpdateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -123,6 +129,8 @@ func (h *Handler) UpdateProduct(c *gin.Context) {
 		req.Description,
 		req.Price,
 		req.SKU,
+		req.Code,
+		req.Category,
 		req.StockQuantity,
 		req.IsActive,
 		userIDStr,
@@ -135,10 +143,13 @@ func (h *Handler) UpdateProduct(c *gin.Context) {
 			return
 		}
 		if err.Error() == "user not authorized to update this product" ||
-			err.Error() == "could not verify shop ownership for product update" {
+			err.Error() == "could not verify shop membership for product update" {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
 		}
+		if respondCodeError(c, err) {
+			return
+		}
 		// if strings.Contains(err.Error(), "already exists") { // For SKU conflict
 		//  c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		//  return
@@ -147,7 +158,7 @@ func (h *Handler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, updatedProduct)
+	c.JSON(http.StatusOK, newProductResponse(updatedProduct, calendar))
 }
 
 func (h *Handler) DeleteProduct(c *gin.Context) {
@@ -168,7 +179,7 @@ func (h *Handler) DeleteProduct(c *gin.Context) {
 			return
 		}
 		if err.Error() == "user not authorized to delete this product" ||
-			err.Error() == "could not verify shop ownership for product deletion" {
+			err.Error() == "could not verify shop membership for product deletion" {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
 		}
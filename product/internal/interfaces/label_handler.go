@@ -0,0 +1,123 @@
+package interfaces
+
+import (
+	"errors"
+	"miniature/pkg/qrpayload"
+	"miniature/product/internal/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultQRSize        = 256
+	defaultBarcodeHeight = 100
+)
+
+func (h *Handler) GetProductQRCode(c *gin.Context) {
+	var query QRCodeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+	if query.Size == 0 {
+		query.Size = defaultQRSize
+	}
+
+	image, err := h.usecase.ProductQRCode(c.Param("product_id"), query.Size)
+	if err != nil {
+		respondLabelError(c, "could not render QR code", err)
+		return
+	}
+	c.Data(http.StatusOK, "image/png", image)
+}
+
+func (h *Handler) GetProductBarcode(c *gin.Context) {
+	var query BarcodeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+	if query.Format == "" {
+		query.Format = string(domain.SymbologyCode128)
+	}
+	if query.Height == 0 {
+		query.Height = defaultBarcodeHeight
+	}
+
+	image, err := h.usecase.ProductBarcode(c.Param("product_id"), query.Format, query.Width, query.Height)
+	if err != nil {
+		respondLabelError(c, "could not render barcode", err)
+		return
+	}
+	c.Data(http.StatusOK, "image/png", image)
+}
+
+// GetLabelSheet returns one A4 page of labels; X-Total-Pages tells the client how many to fetch.
+func (h *Handler) GetLabelSheet(c *gin.Context) {
+	var query LabelSheetQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found in context"})
+		return
+	}
+	userIDStr, _ := userIDRaw.(string)
+
+	sheet, totalPages, err := h.usecase.ShopLabelSheet(c.Param("shop_id"), userIDStr, query.Page)
+	if totalPages > 0 {
+		c.Header("X-Total-Pages", strconv.Itoa(totalPages))
+	}
+	if err != nil {
+		respondLabelError(c, "could not render label sheet", err)
+		return
+	}
+	c.Data(http.StatusOK, "image/svg+xml", sheet)
+}
+
+func (h *Handler) LookupProduct(c *gin.Context) {
+	calendar, ok := calendarFromQuery(c)
+	if !ok {
+		return
+	}
+
+	var query LookupQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+
+	product, err := h.usecase.LookupPayload(query.Payload)
+	if err != nil {
+		respondLabelError(c, "could not look up product", err)
+		return
+	}
+	c.JSON(http.StatusOK, newProductResponse(product, calendar))
+}
+
+func respondLabelError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound),
+		errors.Is(err, domain.ErrPageNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrNotAuthorized):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrProductHasNoCode),
+		errors.Is(err, domain.ErrInvalidSymbology),
+		errors.Is(err, domain.ErrInvalidEAN13),
+		errors.Is(err, domain.ErrInvalidCodePattern),
+		errors.Is(err, domain.ErrInvalidCodePrefix),
+		errors.Is(err, qrpayload.ErrInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + ": " + err.Error()})
+	}
+}
@@ -2,9 +2,11 @@ package interfaces
 
 import (
 	"github.com/gin-gonic/gin"
+	"miniature/pkg/authz"
+	"miniature/pkg/token"
 )
 
-func NewRouter(handler *Handler) *gin.Engine {
+func NewRouter(handler *Handler, auth *token.Authenticator) *gin.Engine {
 	r := gin.Default()
 
 	// Health check endpoint
@@ -14,17 +16,32 @@ func NewRouter(handler *Handler) *gin.Engine {
 
 	v1 := r.Group("/v1")
 	{
+		// Public so bots and scanners can resolve a product code
+		v1.GET("/shops/:shop_id/products/by-code/:code", handler.GetProductByCode)
+
 		shopProducts := v1.Group("/shops/:shop_id/products")
-		shopProducts.Use(AuthMiddleware())
+		shopProducts.Use(AuthMiddleware(auth))
 		{
+			// Product changes are authorized by the caller's role within the shop, see productService
 			shopProducts.POST("", handler.CreateProduct)
-			shopProducts.GET("", handler.GetShopProducts)
+			shopProducts.GET("", authz.Require(authz.PermProductRead), handler.GetShopProducts)
+			shopProducts.GET("/labels.svg", handler.GetLabelSheet)
+		}
+
+		codeSettings := v1.Group("/shops/:shop_id/product-code-settings")
+		codeSettings.Use(AuthMiddleware(auth))
+		{
+			codeSettings.GET("", handler.GetCodeSettings)
+			codeSettings.PUT("", handler.UpdateCodeSettings)
 		}
 
 		productRoutes := v1.Group("/products")
-		productRoutes.Use(AuthMiddleware())
+		productRoutes.Use(AuthMiddleware(auth))
 		{
-			productRoutes.GET("/:product_id", handler.GetProduct)
+			productRoutes.GET("/lookup", authz.Require(authz.PermProductRead), handler.LookupProduct)
+			productRoutes.GET("/:product_id", authz.Require(authz.PermProductRead), handler.GetProduct)
+			productRoutes.GET("/:product_id/qr.png", authz.Require(authz.PermProductRead), handler.GetProductQRCode)
+			productRoutes.GET("/:product_id/barcode.png", authz.Require(authz.PermProductRead), handler.GetProductBarcode)
 			productRoutes.PUT("/:product_id", handler.UpdateProduct)
 			productRoutes.DELETE("/:product_id", handler.DeleteProduct)
 		}
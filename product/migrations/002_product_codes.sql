@@ -0,0 +1,54 @@
+-- Human-readable per-shop product codes, e.g. "SL38", used in captions, on labels and at the counter
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category VARCHAR(100);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS code VARCHAR(32);
+
+-- How a shop's codes are generated: pattern expanded with the category prefix and a sequence number
+CREATE TABLE IF NOT EXISTS product_code_settings (
+    shop_id UUID PRIMARY KEY REFERENCES shops(id) ON DELETE CASCADE,
+    pattern VARCHAR(64) NOT NULL DEFAULT '{PREFIX}{SEQ}',
+    default_prefix VARCHAR(16) NOT NULL DEFAULT 'P',
+    min_digits INTEGER NOT NULL DEFAULT 2 CHECK (min_digits BETWEEN 1 AND 8),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS product_code_prefixes (
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    category VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    PRIMARY KEY (shop_id, category)
+);
+
+-- Last number handed out per shop and prefix
+CREATE TABLE IF NOT EXISTS product_code_sequences (
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    prefix VARCHAR(16) NOT NULL,
+    last_value BIGINT NOT NULL,
+    PRIMARY KEY (shop_id, prefix)
+);
+
+-- Codes of deleted products and codes replaced by sellers. Printed labels and old captions may still
+-- carry them, so they are never given to another product. No foreign key on product_id: the product
+-- is usually gone.
+CREATE TABLE IF NOT EXISTS retired_product_codes (
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    code VARCHAR(32) NOT NULL,
+    product_id UUID NOT NULL,
+    retired_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (shop_id, code)
+);
+
+-- Existing products get P01, P02, ... in the order they were created
+UPDATE products p SET code = numbered.code
+FROM (
+    -- LPAD cuts longer strings, so pad to at least two digits without ever shortening, like %0*d
+    SELECT id, 'P' || LPAD(n::TEXT, GREATEST(2, LENGTH(n::TEXT)), '0') AS code
+    FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY shop_id ORDER BY created_at, id) AS n FROM products) ranked
+) numbered
+WHERE p.id = numbered.id AND p.code IS NULL;
+
+INSERT INTO product_code_sequences (shop_id, prefix, last_value)
+SELECT shop_id, 'P', COUNT(*) FROM products GROUP BY shop_id
+ON CONFLICT (shop_id, prefix) DO NOTHING;
+
+ALTER TABLE products ALTER COLUMN code SET NOT NULL;
+ALTER TABLE products ADD CONSTRAINT uq_products_shop_code UNIQUE (shop_id, code);
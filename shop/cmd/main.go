@@ -2,6 +2,8 @@ package main
 
 import (
 	"log"
+	"miniature/pkg/token"
+	"miniature/pkg/token/config"
 	"miniature/shop/internal/application"
 	"miniature/shop/internal/infra/postgres"
 	"miniature/shop/internal/interfaces"
@@ -15,7 +17,15 @@ func main() {
 	repo := postgres.NewPostgresShopRepository(db)
 	service := application.NewShopService(repo)
 	handler := interfaces.NewShopHandler(service)
-	route := interfaces.NewRouter(handler)
+	memberService := application.NewMemberService(repo, postgres.NewPostgresMemberRepository(db))
+	memberHandler := interfaces.NewMemberHandler(memberService)
+	verifier := token.NewJWKSVerifier(config.JWKSURL)
+	if err := verifier.Refresh(); err != nil {
+		log.Printf("cannot load JWKS from %s, will retry: %v", config.JWKSURL, err)
+	}
+	verifier.Start(config.JWKSRefreshInterval)
+	auth := token.NewAuthenticator(verifier, token.NewPostgresRevocationStore(db))
+	route := interfaces.NewRouter(handler, memberHandler, auth)
 
 	addr := "localhost:8081"
 	route.Run(addr)
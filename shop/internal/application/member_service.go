@@ -0,0 +1,124 @@
+package application
+
+import (
+	"miniature/pkg/authz"
+	"miniature/pkg/phone"
+	"miniature/shop/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MemberService struct {
+	shops   domain.Repository
+	members domain.MemberRepository
+}
+
+func NewMemberService(shops domain.Repository, members domain.MemberRepository) *MemberService {
+	return &MemberService{shops: shops, members: members}
+}
+
+// InviteMember adds the user registered with phone to the shop's staff.
+func (s *MemberService) InviteMember(shopID, requestingUserID, rawPhone string, role authz.Role) (*domain.Member, error) {
+	phone, err := phone.Normalize(rawPhone)
+	if err != nil {
+		return nil, err
+	}
+	if !role.IsShopRole() {
+		return nil, domain.ErrInvalidMemberRole
+	}
+	if err := s.authorize(shopID, requestingUserID, authz.PermShopMembersManage); err != nil {
+		return nil, err
+	}
+
+	userIDStr, err := s.members.FindUserIDByPhone(phone)
+	if err != nil {
+		return nil, err
+	}
+	if userIDStr == "" {
+		return nil, domain.ErrUserNotFound
+	}
+
+	member := &domain.Member{
+		ID:        uuid.New(),
+		ShopID:    uuid.MustParse(shopID),
+		UserID:    uuid.MustParse(userIDStr),
+		Role:      role,
+		Phone:     phone,
+		CreatedAt: time.Now(),
+	}
+	if err := s.members.Create(member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *MemberService) ListMembers(shopID, requestingUserID string) ([]*domain.Member, error) {
+	if err := s.authorize(shopID, requestingUserID, authz.PermShopMembersRead); err != nil {
+		return nil, err
+	}
+	return s.members.FindByShopID(shopID)
+}
+
+func (s *MemberService) ChangeMemberRole(shopID, requestingUserID, memberUserID string, role authz.Role) (*domain.Member, error) {
+	if !role.IsShopRole() {
+		return nil, domain.ErrInvalidMemberRole
+	}
+	if err := s.authorize(shopID, requestingUserID, authz.PermShopMembersManage); err != nil {
+		return nil, err
+	}
+	if err := s.ensureNotShopOwner(shopID, memberUserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.members.UpdateRole(shopID, memberUserID, role); err != nil {
+		return nil, err
+	}
+	return s.members.Find(shopID, memberUserID)
+}
+
+func (s *MemberService) RemoveMember(shopID, requestingUserID, memberUserID string) error {
+	if err := s.authorize(shopID, requestingUserID, authz.PermShopMembersManage); err != nil {
+		return err
+	}
+	if err := s.ensureNotShopOwner(shopID, memberUserID); err != nil {
+		return err
+	}
+
+	return s.members.Delete(shopID, memberUserID)
+}
+
+// authorize checks perm against the role the user holds in this shop, not their platform role.
+func (s *MemberService) authorize(shopIDStr, userID string, perm authz.Permission) error {
+	if _, err := uuid.Parse(shopIDStr); err != nil {
+		return domain.ErrShopNotFound
+	}
+
+	role, err := s.members.RoleOf(shopIDStr, userID)
+	if err != nil {
+		return err
+	}
+	if role == "" || !authz.Can(role, perm) {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// ensureNotShopOwner protects the owner recorded on the shop, so a shop can never be left without one.
+func (s *MemberService) ensureNotShopOwner(shopID, memberUserID string) error {
+	if _, err := uuid.Parse(memberUserID); err != nil {
+		return domain.ErrMemberNotFound
+	}
+
+	shop, err := s.shops.FindByID(shopID)
+	if err != nil {
+		return err
+	}
+	if shop == nil {
+		return domain.ErrShopNotFound
+	}
+	if shop.OwnerID.String() == memberUserID {
+		return domain.ErrShopOwnerFixed
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package application
 
-import "miniature/shop/internal/domain"
+import (
+	"miniature/pkg/authz"
+	"miniature/shop/internal/domain"
+)
 
 // Usecase defines the interface for shop-related business logic.
 type Usecase interface {
@@ -10,3 +13,11 @@ type Usecase interface {
 	UpdateShop(id, userIDFromTokenStr, name string, isActive bool) (*domain.Shop, error)
 	DeleteShop(id, userIDFromTokenStr string) error
 }
+
+// MemberUsecase defines the interface for managing a shop's staff.
+type MemberUsecase interface {
+	InviteMember(shopID, requestingUserID, phone string, role authz.Role) (*domain.Member, error)
+	ListMembers(shopID, requestingUserID string) ([]*domain.Member, error)
+	ChangeMemberRole(shopID, requestingUserID, memberUserID string, role authz.Role) (*domain.Member, error)
+	RemoveMember(shopID, requestingUserID, memberUserID string) error
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "errors"
+
+var (
+	ErrShopNotFound      = errors.New("shop not found")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrMemberNotFound    = errors.New("member not found")
+	ErrMemberExists      = errors.New("user is already a member of this shop")
+	ErrInvalidMemberRole = errors.New("member role must be OWNER or SELLER")
+	ErrShopOwnerFixed    = errors.New("the shop owner cannot be removed or demoted")
+	ErrForbidden         = errors.New("user is not authorized to manage this shop")
+)
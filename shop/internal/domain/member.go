@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"miniature/pkg/authz"
+)
+
+// Member is a user on a shop's staff, backed by the shop_users table.
+type Member struct {
+	ID        uuid.UUID  `json:"id"`
+	ShopID    uuid.UUID  `json:"shop_id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Role      authz.Role `json:"role"`
+	Phone     string     `json:"phone,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "miniature/pkg/authz"
+
 // Repository defines the interface for interacting with shop data.
 type Repository interface {
 	Create(shop *Shop) error
@@ -8,3 +10,16 @@ type Repository interface {
 	Update(shop *Shop) error
 	Delete(id string) error
 }
+
+// MemberRepository defines the interface for interacting with shop staff data.
+type MemberRepository interface {
+	Create(member *Member) error
+	FindByShopID(shopID string) ([]*Member, error)
+	Find(shopID, userID string) (*Member, error)
+	UpdateRole(shopID, userID string, role authz.Role) error
+	Delete(shopID, userID string) error
+	// RoleOf returns the role userID holds in shopID, or "" if they are not on its staff.
+	// The owner recorded on the shop itself is always an OWNER.
+	RoleOf(shopID, userID string) (authz.Role, error)
+	FindUserIDByPhone(phone string) (string, error)
+}
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"miniature/pkg/authz"
+	"miniature/shop/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+type postgresMemberRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresMemberRepository(db *sql.DB) domain.MemberRepository {
+	return &postgresMemberRepository{db: db}
+}
+
+func (r *postgresMemberRepository) Create(member *domain.Member) error {
+	query := `INSERT INTO shop_users (id, shop_id, user_id, role, created_at)
+              VALUES ($1, $2, $3, $4, $5)`
+	_, err := r.db.Exec(query, member.ID, member.ShopID, member.UserID, member.Role, member.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation on (shop_id, user_id)
+		return domain.ErrMemberExists
+	}
+	return err
+}
+
+func (r *postgresMemberRepository) FindByShopID(shopID string) ([]*domain.Member, error) {
+	var members []*domain.Member
+	query := `SELECT su.id, su.shop_id, su.user_id, su.role, COALESCE(c.phone, ''), COALESCE(c.name, ''), su.created_at
+              FROM shop_users su
+              LEFT JOIN customer c ON c.id = su.user_id
+              WHERE su.shop_id = $1
+              ORDER BY su.created_at`
+	rows, err := r.db.Query(query, shopID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		member := &domain.Member{}
+		err := rows.Scan(&member.ID, &member.ShopID, &member.UserID, &member.Role, &member.Phone, &member.Name, &member.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *postgresMemberRepository) Find(shopID, userID string) (*domain.Member, error) {
+	member := &domain.Member{}
+	query := `SELECT su.id, su.shop_id, su.user_id, su.role, COALESCE(c.phone, ''), COALESCE(c.name, ''), su.created_at
+              FROM shop_users su
+              LEFT JOIN customer c ON c.id = su.user_id
+              WHERE su.shop_id = $1 AND su.user_id = $2`
+	row := r.db.QueryRow(query, shopID, userID)
+	err := row.Scan(&member.ID, &member.ShopID, &member.UserID, &member.Role, &member.Phone, &member.Name, &member.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+func (r *postgresMemberRepository) UpdateRole(shopID, userID string, role authz.Role) error {
+	query := `UPDATE shop_users SET role = $1 WHERE shop_id = $2 AND user_id = $3`
+	result, err := r.db.Exec(query, role, shopID, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrMemberNotFound
+	}
+	return nil
+}
+
+func (r *postgresMemberRepository) Delete(shopID, userID string) error {
+	query := `DELETE FROM shop_users WHERE shop_id = $1 AND user_id = $2`
+	result, err := r.db.Exec(query, shopID, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrMemberNotFound
+	}
+	return nil
+}
+
+func (r *postgresMemberRepository) RoleOf(shopID, userID string) (authz.Role, error) {
+	var role sql.NullString
+	query := `SELECT CASE WHEN s.owner_id = $2 THEN 'OWNER' ELSE su.role END
+              FROM shops s
+              LEFT JOIN shop_users su ON su.shop_id = s.id AND su.user_id = $2
+              WHERE s.id = $1`
+	err := r.db.QueryRow(query, shopID, userID).Scan(&role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", domain.ErrShopNotFound
+		}
+		return "", err
+	}
+	return authz.Role(role.String), nil
+}
+
+func (r *postgresMemberRepository) FindUserIDByPhone(phone string) (string, error) {
+	var userID string
+	err := r.db.QueryRow(`SELECT id FROM customer WHERE phone = $1`, phone).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+	return userID, nil
+}
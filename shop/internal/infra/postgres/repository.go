@@ -2,7 +2,10 @@ package postgres
 
 import (
 	"database/sql"
+	"miniature/pkg/authz"
 	"miniature/shop/internal/domain"
+
+	"github.com/google/uuid"
 )
 
 type postgresShopRepository struct {
@@ -13,11 +16,29 @@ func NewPostgresShopRepository(db *sql.DB) domain.Repository {
 	return &postgresShopRepository{db: db}
 }
 
+// Create inserts the shop and registers its owner as the first OWNER member.
 func (r *postgresShopRepository) Create(shop *domain.Shop) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `INSERT INTO shops (id, name, owner_id, is_active, created_at)
               VALUES ($1, $2, $3, $4, $5)`
-	_, err := r.db.Exec(query, shop.ID, shop.Name, shop.OwnerID, shop.IsActive, shop.CreatedAt)
-	return err
+	_, err = tx.Exec(query, shop.ID, shop.Name, shop.OwnerID, shop.IsActive, shop.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	memberQuery := `INSERT INTO shop_users (id, shop_id, user_id, role, created_at)
+                    VALUES ($1, $2, $3, $4, $5)`
+	_, err = tx.Exec(memberQuery, uuid.New(), shop.ID, shop.OwnerID, authz.RoleOwner, shop.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *postgresShopRepository) FindByID(id string) (*domain.Shop, error) {
@@ -1,6 +1,7 @@
 package interfaces
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -8,7 +9,7 @@ import (
 	"miniature/pkg/token" // Assuming this pkg/token is a shared module
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+func AuthMiddleware(auth *token.Authenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
@@ -17,9 +18,13 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-		claims, err := token.ValidateToken(tokenStr) // This will need to be adjusted if token validation logic changes
+		claims, err := auth.Authenticate(tokenStr) // Rejects revoked tokens as well as invalid ones
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			if errors.Is(err, token.ErrInvalidToken) || errors.Is(err, token.ErrTokenRevoked) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "could not verify token"})
 			return
 		}
 
@@ -1,6 +1,9 @@
 package interfaces
 
 import (
+	"miniature/pkg/authz"
+	"miniature/pkg/jalali"
+	"miniature/shop/internal/domain"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,11 +20,74 @@ type UpdateShopRequest struct {
 	IsActive bool   `json:"is_active"`
 }
 
-// ShopResponse represents the response payload for a shop.
+// ShopResponse represents the response payload for a shop. CreatedAtJalali is only set for clients
+// that ask for ?calendar=jalali.
 type ShopResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	OwnerID   uuid.UUID `json:"owner_id"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
+	ID              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	OwnerID         uuid.UUID `json:"owner_id"`
+	IsActive        bool      `json:"is_active"`
+	CreatedAt       time.Time `json:"created_at"`
+	CreatedAtJalali string    `json:"created_at_jalali,omitempty"`
+}
+
+func newShopResponse(shop *domain.Shop, calendar jalali.Calendar) *ShopResponse {
+	response := &ShopResponse{
+		ID:        shop.ID,
+		Name:      shop.Name,
+		OwnerID:   shop.OwnerID,
+		IsActive:  shop.IsActive,
+		CreatedAt: shop.CreatedAt,
+	}
+	if calendar == jalali.Jalali {
+		response.CreatedAtJalali = jalali.Format(shop.CreatedAt)
+	}
+	return response
+}
+
+func newShopResponses(shops []*domain.Shop, calendar jalali.Calendar) []*ShopResponse {
+	responses := make([]*ShopResponse, 0, len(shops))
+	for _, shop := range shops {
+		responses = append(responses, newShopResponse(shop, calendar))
+	}
+	return responses
+}
+
+// MemberResponse represents the response payload for a member of a shop's staff.
+type MemberResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	ShopID          uuid.UUID  `json:"shop_id"`
+	UserID          uuid.UUID  `json:"user_id"`
+	Role            authz.Role `json:"role"`
+	Phone           string     `json:"phone,omitempty"`
+	Name            string     `json:"name,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	CreatedAtJalali string     `json:"created_at_jalali,omitempty"`
+}
+
+func newMemberResponse(member *domain.Member, calendar jalali.Calendar) *MemberResponse {
+	response := &MemberResponse{
+		ID:        member.ID,
+		ShopID:    member.ShopID,
+		UserID:    member.UserID,
+		Role:      member.Role,
+		Phone:     member.Phone,
+		Name:      member.Name,
+		CreatedAt: member.CreatedAt,
+	}
+	if calendar == jalali.Jalali {
+		response.CreatedAtJalali = jalali.Format(member.CreatedAt)
+	}
+	return response
+}
+
+// InviteMemberRequest represents the request payload for adding a user to a shop's staff.
+type InviteMemberRequest struct {
+	Phone string `json:"phone" binding:"required"`
+	Role  string `json:"role" binding:"required"`
+}
+
+// UpdateMemberRoleRequest represents the request payload for changing a staff member's role.
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required"`
 }
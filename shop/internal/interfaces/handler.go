@@ -6,7 +6,6 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	// "github.com/segment-sources/sources-backend-takehome-assignment/shop/internal/domain" // For ShopResponse, if it's different from domain.Shop
 )
 
 type ShopHandler struct {
@@ -18,6 +17,11 @@ func NewShopHandler(u application.Usecase) *ShopHandler {
 }
 
 func (h *ShopHandler) CreateShop(c *gin.Context) {
+	calendar, ok := calendarFromQuery(c)
+	if !ok {
+		return
+	}
+
 	var req CreateShopRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
@@ -35,36 +39,22 @@ func (h *ShopHandler) CreateShop(c *gin.Context) {
 		return
 	}
 
-	roleRaw, exists := c.Get("role")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "role not found in context"})
-		return
-	}
-	roleStr, ok := roleRaw.(string)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "role is not of type string"})
-		return
-	}
-
-	// Authorization: Only "SELLER" role can create shops for now
-	// TODO: Make "SELLER" a constant or configurable
-	if roleStr != "SELLER" {
-		//c.JSON(http.StatusForbidden, gin.H{"error": "user does not have permission to create a shop"})
-		//return
-	}
-
 	shop, err := h.usecase.CreateShop(req.Name, userIDStr)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not create shop: " + err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, shop) // Assuming domain.Shop can be directly marshalled
+	c.JSON(http.StatusCreated, newShopResponse(shop, calendar))
 }
 
 // Placeholder handlers
 func (h *ShopHandler) GetShop(c *gin.Context) {
 	shopID := c.Param("shop_id")
+	calendar, ok := calendarFromQuery(c)
+	if !ok {
+		return
+	}
 
 	shop, err := h.usecase.GetShopByID(shopID)
 	if err != nil {
@@ -77,10 +67,15 @@ func (h *ShopHandler) GetShop(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "shop not found"})
 		return
 	}
-	c.JSON(http.StatusOK, shop)
+	c.JSON(http.StatusOK, newShopResponse(shop, calendar))
 }
 
 func (h *ShopHandler) GetUserShops(c *gin.Context) {
+	calendar, ok := calendarFromQuery(c)
+	if !ok {
+		return
+	}
+
 	userIDRaw, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found in context"})
@@ -98,11 +93,15 @@ func (h *ShopHandler) GetUserShops(c *gin.Context) {
 		return
 	}
 	// Always return a list, even if empty
-	c.JSON(http.StatusOK, shops)
+	c.JSON(http.StatusOK, newShopResponses(shops, calendar))
 }
 
 func (h *ShopHandler) UpdateShop(c *gin.Context) {
 	shopID := c.Param("shop_id")
+	calendar, ok := calendarFromQuery(c)
+	if !ok {
+		return
+	}
 
 	var req UpdateShopRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -121,22 +120,6 @@ func (h *ShopHandler) UpdateShop(c *gin.Context) {
 		return
 	}
 
-	roleRaw, exists := c.Get("role")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "role not found in context"})
-		return
-	}
-	roleStr, ok := roleRaw.(string)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "role is not of type string"})
-		return
-	}
-
-	if roleStr != "SELLER" { // Assuming "SELLER" role is required to update
-		//c.JSON(http.StatusForbidden, gin.H{"error": "user does not have permission to update shops"})
-		//return
-	}
-
 	updatedShop, err := h.usecase.UpdateShop(shopID, userIDStr, req.Name, req.IsActive)
 	if err != nil {
 		// Basic error handling, can be more granular
@@ -152,7 +135,7 @@ func (h *ShopHandler) UpdateShop(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, updatedShop)
+	c.JSON(http.StatusOK, newShopResponse(updatedShop, calendar))
 }
 
 func (h *ShopHandler) DeleteShop(c *gin.Context) {
@@ -169,23 +152,6 @@ func (h *ShopHandler) DeleteShop(c *gin.Context) {
 		return
 	}
 
-	roleRaw, exists := c.Get("role")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "role not found in context"})
-		return
-	}
-	roleStr, ok := roleRaw.(string)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "role is not of type string"})
-		return
-	}
-
-	if roleStr != "SELLER" { // Assuming "SELLER" role is required to delete
-		// TODO check the correct role
-		//c.JSON(http.StatusForbidden, gin.H{"error": "user does not have permission to delete shops"})
-		//return
-	}
-
 	err := h.usecase.DeleteShop(shopID, userIDStr)
 	if err != nil {
 		if err.Error() == "shop not found" {
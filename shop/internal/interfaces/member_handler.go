@@ -0,0 +1,154 @@
+package interfaces
+
+import (
+	"errors"
+	"miniature/pkg/authz"
+	"miniature/pkg/phone"
+	"miniature/shop/internal/application"
+	"miniature/shop/internal/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MemberHandler struct {
+	usecase application.MemberUsecase
+}
+
+func NewMemberHandler(u application.MemberUsecase) *MemberHandler {
+	return &MemberHandler{usecase: u}
+}
+
+func (h *MemberHandler) InviteMember(c *gin.Context) {
+	calendar, ok := calendarFromQuery(c)
+	if !ok {
+		return
+	}
+
+	var req InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+	role, err := authz.ParseRole(req.Role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidMemberRole.Error()})
+		return
+	}
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	member, err := h.usecase.InviteMember(c.Param("shop_id"), userID, req.Phone, role)
+	if err != nil {
+		respondMemberError(c, "could not invite member", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newMemberResponse(member, calendar))
+}
+
+func (h *MemberHandler) ListMembers(c *gin.Context) {
+	calendar, ok := calendarFromQuery(c)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.usecase.ListMembers(c.Param("shop_id"), userID)
+	if err != nil {
+		respondMemberError(c, "could not retrieve members", err)
+		return
+	}
+
+	responses := make([]*MemberResponse, 0, len(members))
+	for _, member := range members {
+		responses = append(responses, newMemberResponse(member, calendar))
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+func (h *MemberHandler) UpdateMemberRole(c *gin.Context) {
+	calendar, ok := calendarFromQuery(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+	role, err := authz.ParseRole(req.Role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidMemberRole.Error()})
+		return
+	}
+
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	member, err := h.usecase.ChangeMemberRole(c.Param("shop_id"), userID, c.Param("user_id"), role)
+	if err != nil {
+		respondMemberError(c, "could not update member", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newMemberResponse(member, calendar))
+}
+
+func (h *MemberHandler) RemoveMember(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.RemoveMember(c.Param("shop_id"), userID, c.Param("user_id")); err != nil {
+		respondMemberError(c, "could not remove member", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// userIDFromContext reads the user set by AuthMiddleware, writing an error response if it is missing.
+func userIDFromContext(c *gin.Context) (string, bool) {
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found in context"})
+		return "", false
+	}
+	userIDStr, ok := userIDRaw.(string)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "user_id is not of type string"})
+		return "", false
+	}
+	return userIDStr, true
+}
+
+func respondMemberError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrShopNotFound),
+		errors.Is(err, domain.ErrUserNotFound),
+		errors.Is(err, domain.ErrMemberNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrMemberExists),
+		errors.Is(err, domain.ErrShopOwnerFixed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidMemberRole),
+		errors.Is(err, phone.ErrInvalid),
+		errors.Is(err, phone.ErrNotMobile):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + ": " + err.Error()})
+	}
+}
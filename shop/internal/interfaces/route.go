@@ -2,9 +2,11 @@ package interfaces
 
 import (
 	"github.com/gin-gonic/gin"
+	"miniature/pkg/authz"
+	"miniature/pkg/token"
 )
 
-func NewRouter(handler *ShopHandler) *gin.Engine {
+func NewRouter(handler *ShopHandler, memberHandler *MemberHandler, auth *token.Authenticator) *gin.Engine {
 	r := gin.Default()
 
 	// Health check endpoint
@@ -16,13 +18,22 @@ func NewRouter(handler *ShopHandler) *gin.Engine {
 	{
 		// Shop routes
 		shopRoutes := v1.Group("/shop")
-		shopRoutes.Use(AuthMiddleware()) // Apply AuthMiddleware to all /shop routes in this group
+		shopRoutes.Use(AuthMiddleware(auth)) // Apply AuthMiddleware to all /shop routes in this group
 		{
-			shopRoutes.POST("", handler.CreateShop)             // POST /v1/shop
-			shopRoutes.GET("/my", handler.GetUserShops)       // GET /v1/shop/my
-			shopRoutes.GET("/:shop_id", handler.GetShop)      // GET /v1/shop/:shop_id
-			shopRoutes.PUT("/:shop_id", handler.UpdateShop)   // PUT /v1/shop/:shop_id
-			shopRoutes.DELETE("/:shop_id", handler.DeleteShop) // DELETE /v1/shop/:shop_id
+			shopRoutes.POST("", authz.Require(authz.PermShopCreate), handler.CreateShop)            // POST /v1/shop
+			shopRoutes.GET("/my", authz.Require(authz.PermShopRead), handler.GetUserShops)          // GET /v1/shop/my
+			shopRoutes.GET("/:shop_id", authz.Require(authz.PermShopRead), handler.GetShop)         // GET /v1/shop/:shop_id
+			shopRoutes.PUT("/:shop_id", authz.Require(authz.PermShopUpdate), handler.UpdateShop)    // PUT /v1/shop/:shop_id
+			shopRoutes.DELETE("/:shop_id", authz.Require(authz.PermShopDelete), handler.DeleteShop) // DELETE /v1/shop/:shop_id
+
+			// Staff routes. Permissions are checked against the caller's role within the shop.
+			members := shopRoutes.Group("/:shop_id/members")
+			{
+				members.POST("", memberHandler.InviteMember)             // POST /v1/shop/:shop_id/members
+				members.GET("", memberHandler.ListMembers)               // GET /v1/shop/:shop_id/members
+				members.PUT("/:user_id", memberHandler.UpdateMemberRole) // PUT /v1/shop/:shop_id/members/:user_id
+				members.DELETE("/:user_id", memberHandler.RemoveMember)  // DELETE /v1/shop/:shop_id/members/:user_id
+			}
 		}
 	}
 	return r
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS shop_users (
+    id UUID PRIMARY KEY,
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('OWNER', 'SELLER')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (shop_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shop_users_user_id ON shop_users(user_id);
+
+-- Existing shops get their owner as the first OWNER member.
+INSERT INTO shop_users (id, shop_id, user_id, role, created_at)
+SELECT gen_random_uuid(), id, owner_id, 'OWNER', created_at FROM shops
+ON CONFLICT (shop_id, user_id) DO NOTHING;